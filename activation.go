@@ -0,0 +1,110 @@
+package evo
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"sync"
+)
+
+// Known errors
+var (
+	ErrInvalidActivation   = errors.New("activation requires a name and a function")
+	ErrDuplicateActivation = errors.New("activation name is already registered")
+	ErrTooManyActivations  = errors.New("no activation values remain to register")
+)
+
+// ActivationFunc transforms the summed input of a neuron
+type ActivationFunc func(x float64) float64
+
+// A custom activation added with RegisterActivation
+type customActivation struct {
+	name       string
+	fn         ActivationFunc
+	derivative ActivationFunc
+}
+
+var (
+	registerMu sync.Mutex
+	customs    [256]*customActivation
+	nextCustom = 255 // custom values count down so they never collide with new built-in ones
+)
+
+// RegisterActivation adds a custom activation function, with its optional derivative, and returns
+// its value. The name is case insensitive and is added to Activations so that the activation can be
+// used in configurations and by the codecs like the built-in ones. Activations should be registered
+// during initialisation as registration is not safe while networks are being activated.
+//
+//	var Step, _ = evo.RegisterActivation("step", func(x float64) float64 {
+//		if x > 0 {
+//			return 1
+//		}
+//		return 0
+//	}, nil)
+func RegisterActivation(name string, fn, derivative ActivationFunc) (Activation, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" || fn == nil {
+		return 0, ErrInvalidActivation
+	}
+	registerMu.Lock()
+	defer registerMu.Unlock()
+	if _, ok := Activations[name]; ok {
+		return 0, ErrDuplicateActivation
+	}
+	if nextCustom <= int(ReLU) {
+		return 0, ErrTooManyActivations
+	}
+	a := Activation(nextCustom)
+	nextCustom--
+	customs[a] = &customActivation{name: name, fn: fn, derivative: derivative}
+	Activations[name] = a
+	return a, nil
+}
+
+// CustomActivations returns the registered custom activations, ordered by value
+func CustomActivations() []Activation {
+	registerMu.Lock()
+	defer registerMu.Unlock()
+	var as []Activation
+	for i, c := range customs {
+		if c != nil {
+			as = append(as, Activation(i))
+		}
+	}
+	return as
+}
+
+// Derivative returns the derivative of the activation function at x. Ok is false if the derivative
+// is not known, either because the activation is unknown or because it was registered without one.
+func (a Activation) Derivative(x float64) (y float64, ok bool) {
+	switch a {
+	case Direct:
+		return 1.0, true
+	case Sigmoid:
+		s := a.Activate(x)
+		return s * (1.0 - s), true
+	case SteepenedSigmoid:
+		s := a.Activate(x)
+		return 4.9 * s * (1.0 - s), true
+	case Tanh:
+		t := a.Activate(x)
+		return 1.0 - t*t, true
+	case InverseAbs:
+		d := 1.0 + math.Abs(x)
+		return 1.0 / (d * d), true
+	case Sin:
+		return math.Cos(x), true
+	case Gauss:
+		return -4.0 * x * a.Activate(x), true
+	case ReLU:
+		if x > 0 {
+			return 1.0, true
+		}
+		return 0.0, true
+	default:
+		if c := customs[a]; c != nil && c.derivative != nil {
+			return c.derivative(x), true
+		}
+		return 0.0, false
+	}
+}
@@ -0,0 +1,114 @@
+package evo
+
+import (
+	"math"
+	"testing"
+)
+
+// Custom activations are registered once per process
+var (
+	square, errSquare = RegisterActivation("Test-Square", func(x float64) float64 { return x * x }, func(x float64) float64 { return 2.0 * x })
+	step, errStep     = RegisterActivation("test-step", func(x float64) float64 {
+		if x > 0 {
+			return 1
+		}
+		return 0
+	}, nil)
+)
+
+func TestRegisterActivation(t *testing.T) {
+	if errSquare != nil || errStep != nil {
+		t.Fatalf("error not expected registering: %v, %v", errSquare, errStep)
+	}
+	if square <= ReLU || step <= ReLU || square == step {
+		t.Fatalf("incorrect values: expected distinct values above %d, actual %d and %d", ReLU, square, step)
+	}
+
+	var cases = []struct {
+		Desc       string
+		Activation Activation
+		Name       string
+		X          float64
+		Value      float64
+		Derivative float64
+		Known      bool
+	}{
+		{Desc: "with derivative", Activation: square, Name: "test-square", X: 3.0, Value: 9.0, Derivative: 6.0, Known: true},
+		{Desc: "without derivative", Activation: step, Name: "test-step", X: 0.5, Value: 1.0},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Desc, func(t *testing.T) {
+			if s := c.Activation.String(); s != c.Name {
+				t.Errorf("incorrect name: expected %s, actual %s", c.Name, s)
+			}
+			if a, ok := Activations[c.Name]; !ok || a != c.Activation {
+				t.Errorf("incorrect activation by name: expected %d, actual %d", c.Activation, a)
+			}
+			if y := c.Activation.Activate(c.X); y != c.Value {
+				t.Errorf("incorrect value: expected %f, actual %f", c.Value, y)
+			}
+			d, ok := c.Activation.Derivative(c.X)
+			if ok != c.Known {
+				t.Errorf("incorrect derivative known: expected %t, actual %t", c.Known, ok)
+			} else if ok && d != c.Derivative {
+				t.Errorf("incorrect derivative: expected %f, actual %f", c.Derivative, d)
+			}
+		})
+	}
+
+	// Registered activations are listed
+	found := 0
+	for _, a := range CustomActivations() {
+		if a == square || a == step {
+			found++
+		}
+	}
+	if found != 2 {
+		t.Errorf("incorrect custom activations: expected both registered, found %d", found)
+	}
+}
+
+func TestRegisterActivationErrors(t *testing.T) {
+	fn := func(x float64) float64 { return x }
+	var cases = []struct {
+		Desc     string
+		Name     string
+		Fn       ActivationFunc
+		Expected error
+	}{
+		{Desc: "missing name", Name: " ", Fn: fn, Expected: ErrInvalidActivation},
+		{Desc: "missing function", Name: "test-missing", Expected: ErrInvalidActivation},
+		{Desc: "built-in name", Name: "Sigmoid", Fn: fn, Expected: ErrDuplicateActivation},
+		{Desc: "registered name", Name: "test-step", Fn: fn, Expected: ErrDuplicateActivation},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Desc, func(t *testing.T) {
+			if _, err := RegisterActivation(c.Name, c.Fn, nil); err != c.Expected {
+				t.Errorf("incorrect error: expected %v, actual %v", c.Expected, err)
+			}
+		})
+	}
+}
+
+func TestActivationDerivative(t *testing.T) {
+	const h = 1e-6
+	for _, a := range []Activation{Direct, Sigmoid, SteepenedSigmoid, Tanh, InverseAbs, Sin, Gauss, ReLU} {
+		t.Run(a.String(), func(t *testing.T) {
+			for _, x := range []float64{-2.0, -0.5, 0.3, 1.5} {
+				d, ok := a.Derivative(x)
+				if !ok {
+					t.Fatalf("derivative should be known")
+				}
+				exp := (a.Activate(x+h) - a.Activate(x-h)) / (2.0 * h)
+				if math.Abs(exp-d) > 1e-6 {
+					t.Errorf("incorrect derivative at %f: expected %f, actual %f", x, exp, d)
+				}
+			}
+		})
+	}
+	if _, ok := Activation(0).Derivative(1.0); ok {
+		t.Errorf("derivative of unknown activation should not be known")
+	}
+}
@@ -0,0 +1,125 @@
+package evo
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// CheckpointVersion is the current version of the checkpoint format
+const CheckpointVersion = 1
+
+// Known errors
+var (
+	ErrUnknownCheckpointVersion = errors.New("unknown checkpoint version")
+)
+
+// A Checkpoint captures everything necessary to resume an experiment: the population, the last
+// genome ID assigned, and the internal state of the experiment's helpers.
+type Checkpoint struct {
+	Version    int        // The checkpoint format version
+	Population Population // The population after the last evaluation
+	LastGID    int64      // The last genome ID assigned
+	State      []byte     // The experiment's internal state, if it is Stateful
+}
+
+// Stateful helpers carry internal state between iterations which must be saved in order to resume an
+// experiment.
+type Stateful interface {
+	State() ([]byte, error)
+	Restore([]byte) error
+}
+
+// Checkpointer saves the checkpoint taken at the end of each iteration. An experiment that
+// implements this will be checkpointed by Run and Resume.
+type Checkpointer interface {
+	Checkpoint(Checkpoint) error
+}
+
+// NewCheckpoint creates a checkpoint for the experiment, population and the last genome ID
+func NewCheckpoint(exp Experiment, pop Population, lastGID int64) (cp Checkpoint, err error) {
+	cp = Checkpoint{
+		Version:    CheckpointVersion,
+		Population: pop,
+		LastGID:    lastGID,
+	}
+	if sx, ok := exp.(Stateful); ok {
+		cp.State, err = sx.State()
+	}
+	return
+}
+
+// ReadCheckpoint decodes a checkpoint from the reader
+func ReadCheckpoint(r io.Reader) (cp Checkpoint, err error) {
+	if err = json.NewDecoder(r).Decode(&cp); err != nil {
+		return
+	}
+	if cp.Version != CheckpointVersion {
+		err = ErrUnknownCheckpointVersion
+	}
+	return
+}
+
+// ReadCheckpointFromFile is a convenience function that decodes a checkpoint from the file specified
+// by filename.
+func ReadCheckpointFromFile(filename string) (cp Checkpoint, err error) {
+
+	// Open the file
+	var f *os.File
+	if f, err = os.Open(filename); err != nil {
+		return
+	}
+
+	// Decode the checkpoint
+	if cp, err = ReadCheckpoint(f); err != nil {
+		f.Close() // ignore error as it would overwrite the decoding one
+		return
+	}
+
+	// Close the file and return
+	err = f.Close()
+	return
+}
+
+// WriteCheckpoint encodes the checkpoint to the writer
+func WriteCheckpoint(w io.Writer, cp Checkpoint) error {
+	return json.NewEncoder(w).Encode(cp)
+}
+
+// FileCheckpointer writes checkpoints to a file, replacing the previous checkpoint. The file is
+// written to a temporary location first so an interrupted write does not destroy the last good
+// checkpoint.
+type FileCheckpointer struct {
+	Filename string // The checkpoint file
+	Interval int    // Number of generations between checkpoints. Values less than 1 checkpoint every generation.
+}
+
+// Checkpoint writes the checkpoint to the file if the population's generation falls on the interval
+func (fc FileCheckpointer) Checkpoint(cp Checkpoint) (err error) {
+
+	// Only write on the interval
+	if fc.Interval > 1 && cp.Population.Generation%fc.Interval != 0 {
+		return
+	}
+
+	// Write to a temporary file in the same directory
+	var f *os.File
+	if f, err = ioutil.TempFile(filepath.Dir(fc.Filename), filepath.Base(fc.Filename)+".tmp"); err != nil {
+		return
+	}
+	if err = WriteCheckpoint(f, cp); err != nil {
+		f.Close() // ignore error as it would overwrite the encoding one
+		os.Remove(f.Name())
+		return
+	}
+	if err = f.Close(); err != nil {
+		os.Remove(f.Name())
+		return
+	}
+
+	// Replace the previous checkpoint
+	return os.Rename(f.Name(), fc.Filename)
+}
@@ -0,0 +1,226 @@
+package evo
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestCheckpointReadWrite(t *testing.T) {
+	var cases = []struct {
+		Desc     string
+		HasError bool
+		Actual   Checkpoint
+	}{
+		{
+			Desc:     "unknown version",
+			HasError: true,
+			Actual:   Checkpoint{Version: CheckpointVersion + 1},
+		},
+		{
+			Desc:     "valid checkpoint",
+			HasError: false,
+			Actual: Checkpoint{
+				Version: CheckpointVersion,
+				Population: Population{
+					Generation: 5,
+					Genomes: []Genome{
+						{ID: 10, Species: 2, Fitness: 1.5, Encoded: Substrate{Nodes: []Node{{Neuron: Input, Activation: Direct}}}},
+						{ID: 12, Species: 3, Fitness: 2.5, Traits: []float64{0.5}},
+					},
+				},
+				LastGID: 12,
+				State:   []byte("state"),
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Desc, func(t *testing.T) {
+			b := new(bytes.Buffer)
+			if err := WriteCheckpoint(b, c.Actual); err != nil {
+				t.Errorf("error not expected writing checkpoint: %v", err)
+				return
+			}
+			cp, err := ReadCheckpoint(b)
+			if c.HasError {
+				if err == nil {
+					t.Error("expected error not found")
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("error not expected: %v", err)
+				return
+			}
+			if cp.LastGID != c.Actual.LastGID {
+				t.Errorf("incorrect last genome ID: expected %d, actual %d", c.Actual.LastGID, cp.LastGID)
+			}
+			if cp.Population.Generation != c.Actual.Population.Generation {
+				t.Errorf("incorrect generation: expected %d, actual %d", c.Actual.Population.Generation, cp.Population.Generation)
+			}
+			if len(cp.Population.Genomes) != len(c.Actual.Population.Genomes) {
+				t.Errorf("incorrect number of genomes: expected %d, actual %d", len(c.Actual.Population.Genomes), len(cp.Population.Genomes))
+				return
+			}
+			for i, g := range cp.Population.Genomes {
+				e := c.Actual.Population.Genomes[i]
+				if g.ID != e.ID || g.Species != e.Species || g.Fitness != e.Fitness || g.Complexity() != e.Complexity() {
+					t.Errorf("incorrect genome %d: expected %+v, actual %+v", i, e, g)
+				}
+			}
+			if string(cp.State) != string(c.Actual.State) {
+				t.Errorf("incorrect state: expected %s, actual %s", c.Actual.State, cp.State)
+			}
+		})
+	}
+}
+
+func TestFileCheckpointer(t *testing.T) {
+
+	// Create a temporary directory for the checkpoints
+	dir, err := ioutil.TempDir("", "evo-checkpoint")
+	if err != nil {
+		t.Fatalf("could not create temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	filename := filepath.Join(dir, "checkpoint.json")
+
+	// Checkpoint on every other generation
+	fc := FileCheckpointer{Filename: filename, Interval: 2}
+	for i := 1; i <= 3; i++ {
+		cp := Checkpoint{Version: CheckpointVersion, Population: Population{Generation: i, Genomes: []Genome{{ID: int64(i)}}}, LastGID: int64(i)}
+		if err = fc.Checkpoint(cp); err != nil {
+			t.Fatalf("error not expected: %v", err)
+		}
+	}
+
+	// The file should contain the checkpoint from generation 2
+	cp, err := ReadCheckpointFromFile(filename)
+	if err != nil {
+		t.Fatalf("error not expected: %v", err)
+	}
+	if cp.Population.Generation != 2 {
+		t.Errorf("incorrect generation: expected 2, actual %d", cp.Population.Generation)
+	}
+
+	// No temporary files should remain
+	var fis []os.FileInfo
+	if fis, err = ioutil.ReadDir(dir); err != nil {
+		t.Fatalf("could not read directory: %v", err)
+	}
+	if len(fis) != 1 {
+		t.Errorf("incorrect number of files: expected 1, actual %d", len(fis))
+	}
+}
+
+func TestResume(t *testing.T) {
+	var cases = []struct {
+		Desc       string
+		HasError   bool
+		Checkpoint Checkpoint
+		Stateful   *mockStateful
+	}{
+		{
+			Desc:       "unknown version",
+			HasError:   true,
+			Checkpoint: Checkpoint{Population: Population{Genomes: []Genome{{ID: 1}}}},
+		},
+		{
+			Desc:       "no genomes",
+			HasError:   true,
+			Checkpoint: Checkpoint{Version: CheckpointVersion},
+		},
+		{
+			Desc:       "restore fails",
+			HasError:   true,
+			Checkpoint: Checkpoint{Version: CheckpointVersion, Population: Population{Genomes: []Genome{{ID: 1}}}, State: []byte("x")},
+			Stateful:   &mockStateful{HasError: true},
+		},
+		{
+			Desc:       "continues after last genome ID",
+			HasError:   false,
+			Checkpoint: Checkpoint{Version: CheckpointVersion, Population: Population{Generation: 10, Genomes: []Genome{{ID: 1}}}, LastGID: 100, State: []byte("x")},
+			Stateful:   &mockStateful{},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Desc, func(t *testing.T) {
+			ctx, fn := context.WithTimeout(context.Background(), time.Nanosecond*100)
+			defer fn()
+
+			// Setup the experiment and resume
+			var exp Experiment = &mockExperiment{}
+			if c.Stateful != nil {
+				exp = &mockStatefulExperiment{mockExperiment: &mockExperiment{}, mockStateful: c.Stateful}
+			}
+			pop, err := Resume(ctx, exp, &mockEvaluator{}, c.Checkpoint)
+
+			if c.HasError {
+				if err == nil {
+					t.Error("expected error not found")
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("error not expected: %v", err)
+				return
+			}
+
+			// State should have been restored and checkpointed
+			if string(c.Stateful.Restored) != string(c.Checkpoint.State) {
+				t.Errorf("state not restored: expected %s, actual %s", c.Checkpoint.State, c.Stateful.Restored)
+			}
+			if len(c.Stateful.Checkpoints) == 0 {
+				t.Error("no checkpoints taken")
+			}
+			if pop.Generation <= c.Checkpoint.Population.Generation {
+				t.Errorf("generation did not advance: expected > %d, actual %d", c.Checkpoint.Population.Generation, pop.Generation)
+			}
+
+			// Offspring IDs must follow the checkpoint's sequence
+			found := false
+			for _, g := range pop.Genomes {
+				if g.ID > c.Checkpoint.LastGID {
+					found = true
+					break
+				}
+			}
+			if !found {
+				t.Error("offspring do not start after last genome ID")
+			}
+		})
+	}
+}
+
+type mockStateful struct {
+	HasError    bool
+	Restored    []byte
+	Checkpoints []Checkpoint
+}
+
+func (m *mockStateful) State() ([]byte, error) { return m.Restored, nil }
+
+func (m *mockStateful) Restore(b []byte) error {
+	if m.HasError {
+		return errors.New("error in mock stateful")
+	}
+	m.Restored = b
+	return nil
+}
+
+func (m *mockStateful) Checkpoint(cp Checkpoint) error {
+	m.Checkpoints = append(m.Checkpoints, cp)
+	return nil
+}
+
+type mockStatefulExperiment struct {
+	*mockExperiment
+	*mockStateful
+}
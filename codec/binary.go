@@ -0,0 +1,506 @@
+package codec
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"math"
+
+	"github.com/klokare/evo"
+)
+
+// Magic bytes beginning the binary format
+const magic = "EVO"
+
+// Tags describing the encoded learning rule
+const (
+	noRule byte = iota
+	hebbianRule
+)
+
+// Tags describing the encoded behavior
+const (
+	noBehavior byte = iota
+	floatsBehavior
+	jsonBehavior
+)
+
+// Encode the value in the binary format
+func encodeBinary(w io.Writer, v interface{}) (err error) {
+	var k kind
+	if k, err = kindOf(v); err != nil {
+		return
+	}
+	e := &encoder{w: bufio.NewWriter(w)}
+	e.bytes([]byte(magic))
+	e.byte(Version)
+	e.byte(byte(k))
+	e.activations()
+	switch x := v.(type) {
+	case evo.Substrate:
+		e.substrate(x)
+	case *evo.Substrate:
+		e.substrate(*x)
+	case evo.Genome:
+		e.genome(x)
+	case *evo.Genome:
+		e.genome(*x)
+	case evo.Population:
+		e.population(x)
+	case *evo.Population:
+		e.population(*x)
+	case evo.Species:
+		e.species(x)
+	case *evo.Species:
+		e.species(*x)
+	}
+	if e.err != nil {
+		return e.err
+	}
+	return e.w.Flush()
+}
+
+// Decode the value from the binary format
+func decodeBinary(r io.ByteReader, v interface{}) (err error) {
+	d := &decoder{r: r}
+	for range magic {
+		d.byte()
+	}
+	if d.err != nil {
+		return d.err
+	}
+	if v := d.byte(); d.err == nil && v != Version {
+		return ErrUnknownVersion
+	}
+	k := kind(d.byte())
+	if d.err != nil {
+		return d.err
+	}
+	var expected kind
+	if expected, err = kindOf(v); err != nil {
+		return
+	}
+	if k != expected {
+		return ErrMismatchedKind
+	}
+	d.activations()
+	switch x := v.(type) {
+	case *evo.Substrate:
+		*x = d.substrate()
+	case *evo.Genome:
+		*x = d.genome()
+	case *evo.Population:
+		*x = d.population()
+	case *evo.Species:
+		*x = d.species()
+	default:
+		return ErrUnsupportedType
+	}
+	return d.err
+}
+
+// The encoder writes the binary format, remembering the first error
+type encoder struct {
+	w   *bufio.Writer
+	buf [binary.MaxVarintLen64]byte
+	err error
+}
+
+func (e *encoder) bytes(b []byte) {
+	if e.err == nil {
+		_, e.err = e.w.Write(b)
+	}
+}
+
+func (e *encoder) byte(b byte) { e.bytes([]byte{b}) }
+
+func (e *encoder) bool(b bool) {
+	if b {
+		e.byte(1)
+	} else {
+		e.byte(0)
+	}
+}
+
+func (e *encoder) int(x int64) { e.bytes(e.buf[:binary.PutVarint(e.buf[:], x)]) }
+
+func (e *encoder) uint(x uint64) { e.bytes(e.buf[:binary.PutUvarint(e.buf[:], x)]) }
+
+func (e *encoder) float(x float64) {
+	binary.LittleEndian.PutUint64(e.buf[:8], math.Float64bits(x))
+	e.bytes(e.buf[:8])
+}
+
+func (e *encoder) floats(xs []float64) {
+	e.uint(uint64(len(xs)))
+	for _, x := range xs {
+		e.float(x)
+	}
+}
+
+func (e *encoder) ints(xs []int64) {
+	e.uint(uint64(len(xs)))
+	for _, x := range xs {
+		e.int(x)
+	}
+}
+
+func (e *encoder) string(s string) {
+	e.uint(uint64(len(s)))
+	e.bytes([]byte(s))
+}
+
+func (e *encoder) strings(ss []string) {
+	e.uint(uint64(len(ss)))
+	for _, s := range ss {
+		e.string(s)
+	}
+}
+
+// Activations writes the values and names of the custom activations so that a program which
+// registered them in a different order can decode them
+func (e *encoder) activations() {
+	as := evo.CustomActivations()
+	e.uint(uint64(len(as)))
+	for _, a := range as {
+		e.byte(byte(a))
+		e.string(a.String())
+	}
+}
+
+func (e *encoder) position(p evo.Position) {
+	e.float(p.Layer)
+	e.float(p.X)
+	e.float(p.Y)
+	e.float(p.Z)
+}
+
+func (e *encoder) substrate(s evo.Substrate) {
+	e.uint(uint64(len(s.Nodes)))
+	for _, n := range s.Nodes {
+		e.position(n.Position)
+		e.byte(byte(n.Neuron))
+		e.byte(byte(n.Activation))
+		e.float(n.Bias)
+		e.bool(n.Locked)
+		e.float(n.TimeConstant)
+	}
+	e.uint(uint64(len(s.Conns)))
+	for _, c := range s.Conns {
+		e.position(c.Source)
+		e.position(c.Target)
+		e.float(c.Weight)
+		e.bool(c.Enabled)
+		e.bool(c.Locked)
+		if c.Hebbian == (evo.Hebbian{}) {
+			e.byte(noRule)
+		} else {
+			e.byte(hebbianRule)
+			e.float(c.A)
+			e.float(c.B)
+			e.float(c.C)
+			e.float(c.D)
+			e.float(c.Rate)
+		}
+	}
+}
+
+func (e *encoder) genome(g evo.Genome) {
+	e.int(g.ID)
+	e.int(int64(g.Species))
+	e.int(int64(g.Age))
+	e.float(g.Fitness)
+	e.float(g.Novelty)
+	e.floats(g.Objectives)
+	e.bool(g.Solved)
+	switch b := g.Behavior.(type) {
+	case nil:
+		e.byte(noBehavior)
+	case []float64:
+		e.byte(floatsBehavior)
+		e.floats(b)
+	default:
+		var x []byte
+		if x, e.err = json.Marshal(b); e.err != nil {
+			return
+		}
+		e.byte(jsonBehavior)
+		e.uint(uint64(len(x)))
+		e.bytes(x)
+	}
+	e.floats(g.Traits)
+	e.substrate(g.Encoded)
+	e.substrate(g.Decoded)
+	e.ints(g.Parents)
+	e.int(int64(g.Birth))
+	e.strings(g.Mutations)
+}
+
+func (e *encoder) population(p evo.Population) {
+	e.int(int64(p.Generation))
+	e.uint(uint64(len(p.Genomes)))
+	for _, g := range p.Genomes {
+		e.genome(g)
+	}
+}
+
+func (e *encoder) species(s evo.Species) {
+	e.int(s.ID)
+	e.float(s.Decay)
+	e.int(s.Champion)
+	e.genome(s.Example)
+}
+
+// The decoder reads the binary format, remembering the first error. Once an error occurs, zero
+// values are returned.
+type decoder struct {
+	r       io.ByteReader
+	customs map[byte]evo.Activation // encoded values of the custom activations
+	unknown map[byte]bool           // encoded values of custom activations not registered here
+	buf     [8]byte
+	err     error
+}
+
+func (d *decoder) byte() (b byte) {
+	if d.err == nil {
+		b, d.err = d.r.ReadByte()
+		d.unexpected()
+	}
+	return
+}
+
+// The input should not end part way through a value
+func (d *decoder) unexpected() {
+	if d.err == io.EOF {
+		d.err = io.ErrUnexpectedEOF
+	}
+}
+
+func (d *decoder) bool() bool { return d.byte() != 0 }
+
+func (d *decoder) int() (x int64) {
+	if d.err == nil {
+		x, d.err = binary.ReadVarint(d.r)
+		d.unexpected()
+	}
+	return
+}
+
+func (d *decoder) uint() (x uint64) {
+	if d.err == nil {
+		x, d.err = binary.ReadUvarint(d.r)
+		d.unexpected()
+	}
+	return
+}
+
+// Length reads the number of elements that follow, rejecting any too large to be genuine
+func (d *decoder) length() int {
+	n := d.uint()
+	if n > math.MaxInt32 {
+		if d.err == nil {
+			d.err = ErrInvalidBinary
+		}
+		return 0
+	}
+	return int(n)
+}
+
+// Capacity limits the initial allocation for the elements so that a corrupt length fails when the
+// input runs out rather than by exhausting memory
+func capacity(n int) int {
+	if n > 1024 {
+		return 1024
+	}
+	return n
+}
+
+func (d *decoder) float() float64 {
+	for i := range d.buf {
+		d.buf[i] = d.byte()
+	}
+	return math.Float64frombits(binary.LittleEndian.Uint64(d.buf[:]))
+}
+
+func (d *decoder) floats() (xs []float64) {
+	n := d.length()
+	if n == 0 || d.err != nil {
+		return
+	}
+	xs = make([]float64, 0, capacity(n))
+	for i := 0; i < n && d.err == nil; i++ {
+		xs = append(xs, d.float())
+	}
+	return
+}
+
+func (d *decoder) ints() (xs []int64) {
+	n := d.length()
+	if n == 0 || d.err != nil {
+		return
+	}
+	xs = make([]int64, 0, capacity(n))
+	for i := 0; i < n && d.err == nil; i++ {
+		xs = append(xs, d.int())
+	}
+	return
+}
+
+func (d *decoder) string() string {
+	n := d.length()
+	b := make([]byte, 0, capacity(n))
+	for i := 0; i < n && d.err == nil; i++ {
+		b = append(b, d.byte())
+	}
+	return string(b)
+}
+
+func (d *decoder) strings() (ss []string) {
+	n := d.length()
+	if n == 0 || d.err != nil {
+		return
+	}
+	ss = make([]string, 0, capacity(n))
+	for i := 0; i < n && d.err == nil; i++ {
+		ss = append(ss, d.string())
+	}
+	return
+}
+
+// Activations reads the custom activations written by the encoder and maps them to the values
+// registered in this program. Those not registered here are remembered so that decoding fails only
+// if one is used.
+func (d *decoder) activations() {
+	n := d.length()
+	for i := 0; i < n && d.err == nil; i++ {
+		v, name := d.byte(), d.string()
+		if d.err != nil {
+			return
+		}
+		a, ok := evo.Activations[name]
+		if !ok {
+			if d.unknown == nil {
+				d.unknown = make(map[byte]bool, n)
+			}
+			d.unknown[v] = true
+			continue
+		}
+		if d.customs == nil {
+			d.customs = make(map[byte]evo.Activation, n)
+		}
+		d.customs[v] = a
+	}
+}
+
+func (d *decoder) activation() evo.Activation {
+	b := d.byte()
+	if a, ok := d.customs[b]; ok {
+		return a
+	}
+	if d.unknown[b] {
+		d.err = ErrUnknownActivation
+		return 0
+	}
+	return evo.Activation(b)
+}
+
+func (d *decoder) position() evo.Position {
+	return evo.Position{Layer: d.float(), X: d.float(), Y: d.float(), Z: d.float()}
+}
+
+func (d *decoder) substrate() (s evo.Substrate) {
+	if n := d.length(); n > 0 && d.err == nil {
+		s.Nodes = make([]evo.Node, 0, capacity(n))
+		for i := 0; i < n && d.err == nil; i++ {
+			s.Nodes = append(s.Nodes, evo.Node{
+				Position:     d.position(),
+				Neuron:       evo.Neuron(d.byte()),
+				Activation:   d.activation(),
+				Bias:         d.float(),
+				Locked:       d.bool(),
+				TimeConstant: d.float(),
+			})
+		}
+	}
+	if n := d.length(); n > 0 && d.err == nil {
+		s.Conns = make([]evo.Conn, 0, capacity(n))
+		for i := 0; i < n && d.err == nil; i++ {
+			s.Conns = append(s.Conns, evo.Conn{
+				Source:  d.position(),
+				Target:  d.position(),
+				Weight:  d.float(),
+				Enabled: d.bool(),
+				Locked:  d.bool(),
+				Hebbian: d.hebbian(),
+			})
+		}
+	}
+	return
+}
+
+func (d *decoder) hebbian() (h evo.Hebbian) {
+	switch d.byte() {
+	case noRule:
+	case hebbianRule:
+		h = evo.Hebbian{A: d.float(), B: d.float(), C: d.float(), D: d.float(), Rate: d.float()}
+	default:
+		if d.err == nil {
+			d.err = ErrInvalidBinary
+		}
+	}
+	return
+}
+
+func (d *decoder) genome() (g evo.Genome) {
+	g.ID = d.int()
+	g.Species = int(d.int())
+	g.Age = int(d.int())
+	g.Fitness = d.float()
+	g.Novelty = d.float()
+	g.Objectives = d.floats()
+	g.Solved = d.bool()
+	switch d.byte() {
+	case noBehavior:
+	case floatsBehavior:
+		g.Behavior = d.floats()
+	case jsonBehavior:
+		n := d.length()
+		b := make([]byte, 0, capacity(n))
+		for i := 0; i < n && d.err == nil; i++ {
+			b = append(b, d.byte())
+		}
+		if d.err == nil {
+			g.Behavior, d.err = behavior(b)
+		}
+	default:
+		if d.err == nil {
+			d.err = ErrInvalidBinary
+		}
+	}
+	g.Traits = d.floats()
+	g.Encoded = d.substrate()
+	g.Decoded = d.substrate()
+	g.Parents = d.ints()
+	g.Birth = int(d.int())
+	g.Mutations = d.strings()
+	return
+}
+
+func (d *decoder) population() (p evo.Population) {
+	p.Generation = int(d.int())
+	n := d.length()
+	p.Genomes = make([]evo.Genome, 0, capacity(n))
+	for i := 0; i < n && d.err == nil; i++ {
+		p.Genomes = append(p.Genomes, d.genome())
+	}
+	return
+}
+
+func (d *decoder) species() (s evo.Species) {
+	s.ID = d.int()
+	s.Decay = d.float()
+	s.Champion = d.int()
+	s.Example = d.genome()
+	return
+}
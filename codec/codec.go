@@ -0,0 +1,212 @@
+// Package codec saves and loads genomes, substrates, populations and species so that, for
+// example, the champion of an experiment can be deployed later. Two formats are provided: a stable
+// JSON format, which names neurons and activations rather than using their internal codes, and a
+// compact binary format. Both begin with a header carrying the format version and the kind of value
+// encoded, and Decode detects the format from the header.
+//
+// A genome in the JSON format looks like:
+//
+//	{"version":1,"kind":"genome","genome":{"id":7,"species":2,"age":3,"fitness":15.2,"novelty":0,"solved":true,
+//	 "encoded":{"nodes":[{"position":{"layer":0,"x":0,"y":0,"z":0},"neuron":"input","activation":"direct","bias":0}, ...],
+//	 "conns":[{"source":{"layer":0,"x":0,"y":0,"z":0},"target":{"layer":1,"x":0,"y":0,"z":0},"weight":1.2,"enabled":true}, ...]}}}
+//
+// The binary format begins with the bytes "EVO", the version and the kind, followed by the value's
+// fields in order. Integers are written as varints and floats as 8 little-endian bytes. A genome's
+// behavior is written as floats when it is a []float64 and as JSON otherwise.
+//
+// Custom activations, added with evo.RegisterActivation, are written by name in the JSON format. The
+// binary format follows its header with a table of the custom activations' values and names so
+// that they decode correctly in programs that registered them in a different order. Decoding fails
+// with ErrUnknownActivation only if a node uses an activation this program has not registered.
+package codec
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/klokare/evo"
+)
+
+// Version is the current version of the encodings
+const Version = 1
+
+// Known errors
+var (
+	ErrUnknownVersion    = errors.New("unknown encoding version")
+	ErrUnknownFormat     = errors.New("unknown encoding format")
+	ErrUnsupportedType   = errors.New("unsupported type; use a genome, substrate, population or species")
+	ErrMismatchedKind    = errors.New("encoded kind does not match the value")
+	ErrUnknownNeuron     = errors.New("unknown neuron type")
+	ErrUnknownActivation = errors.New("unknown activation type")
+	ErrInvalidBinary     = errors.New("invalid binary encoding")
+)
+
+// Format of the encoding
+type Format byte
+
+// Known formats
+const (
+	JSON Format = iota + 1
+	Binary
+)
+
+func (f Format) String() string {
+	switch f {
+	case JSON:
+		return "json"
+	case Binary:
+		return "binary"
+	default:
+		return "unknown"
+	}
+}
+
+// Formats provides a map of formats by name
+var Formats = map[string]Format{
+	"json":   JSON,
+	"binary": Binary,
+}
+
+// Kind of value encoded
+type kind byte
+
+const (
+	substrateKind kind = iota + 1
+	genomeKind
+	populationKind
+	speciesKind
+)
+
+func (k kind) String() string {
+	switch k {
+	case substrateKind:
+		return "substrate"
+	case genomeKind:
+		return "genome"
+	case populationKind:
+		return "population"
+	case speciesKind:
+		return "species"
+	default:
+		return "unknown"
+	}
+}
+
+// Identify the kind of the value, which may be the value itself or a pointer to it
+func kindOf(v interface{}) (k kind, err error) {
+	switch v.(type) {
+	case evo.Substrate, *evo.Substrate:
+		k = substrateKind
+	case evo.Genome, *evo.Genome:
+		k = genomeKind
+	case evo.Population, *evo.Population:
+		k = populationKind
+	case evo.Species, *evo.Species:
+		k = speciesKind
+	default:
+		err = ErrUnsupportedType
+	}
+	return
+}
+
+// Encode the value, an evo.Genome, evo.Substrate, evo.Population or evo.Species, to the writer in
+// the format. If the format is zero, JSON is used.
+func Encode(w io.Writer, f Format, v interface{}) error {
+	switch f {
+	case 0, JSON:
+		return encodeJSON(w, v)
+	case Binary:
+		return encodeBinary(w, v)
+	default:
+		return ErrUnknownFormat
+	}
+}
+
+// Decode the value from the reader into v, which must be a pointer to an evo.Genome,
+// evo.Substrate, evo.Population or evo.Species. The format is detected from the header.
+func Decode(r io.Reader, v interface{}) (err error) {
+	br := bufio.NewReader(r)
+	var b []byte
+	if b, err = br.Peek(len(magic)); err != nil {
+		return
+	}
+	if string(b) == magic {
+		return decodeBinary(br, v)
+	}
+	return decodeJSON(br, v)
+}
+
+// WriteFile encodes the value to the file in the format. The file is written to a temporary location
+// first so an interrupted write does not destroy an earlier file.
+func WriteFile(filename string, f Format, v interface{}) (err error) {
+
+	// Write to a temporary file in the same directory
+	var tmp *os.File
+	if tmp, err = ioutil.TempFile(filepath.Dir(filename), filepath.Base(filename)+".tmp"); err != nil {
+		return
+	}
+	if err = Encode(tmp, f, v); err != nil {
+		tmp.Close() // ignore error as it would overwrite the encoding one
+		os.Remove(tmp.Name())
+		return
+	}
+	if err = tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return
+	}
+
+	// Replace any earlier file
+	return os.Rename(tmp.Name(), filename)
+}
+
+// ReadFile decodes the value from the file into v
+func ReadFile(filename string, v interface{}) (err error) {
+
+	// Open the file
+	var f *os.File
+	if f, err = os.Open(filename); err != nil {
+		return
+	}
+
+	// Decode the value
+	if err = Decode(f, v); err != nil {
+		f.Close() // ignore error as it would overwrite the decoding one
+		return
+	}
+
+	// Close the file and return
+	err = f.Close()
+	return
+}
+
+// Best returns the best genome in the population according to the comparisons. If none are given,
+// genomes are compared by solved, fitness, complexity and age as with example.ShowBest.
+func Best(pop evo.Population, comparisons ...evo.Comparison) (best evo.Genome) {
+	if len(pop.Genomes) == 0 {
+		return
+	}
+	if len(comparisons) == 0 {
+		comparisons = []evo.Comparison{evo.BySolved, evo.ByFitness, evo.ByComplexity, evo.ByAge}
+	}
+
+	// Copy the genomes so we can sort them without affecting other listeners
+	genomes := make([]evo.Genome, len(pop.Genomes))
+	copy(genomes, pop.Genomes)
+	evo.SortBy(genomes, comparisons...)
+	return genomes[len(genomes)-1]
+}
+
+// SaveBest returns a callback, usually subscribed to the Completed event, that writes the best
+// genome in the population to the file in the format. See Best for the comparisons.
+func SaveBest(filename string, f Format, comparisons ...evo.Comparison) evo.Callback {
+	return func(pop evo.Population) error {
+		if len(pop.Genomes) == 0 {
+			return nil
+		}
+		return WriteFile(filename, f, Best(pop, comparisons...))
+	}
+}
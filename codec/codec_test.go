@@ -0,0 +1,296 @@
+package codec
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/klokare/evo"
+)
+
+func newTestSubstrate() evo.Substrate {
+	return evo.Substrate{
+		Nodes: []evo.Node{
+			{Position: evo.Position{Layer: 0.0, X: 0.25}, Neuron: evo.Input, Activation: evo.Direct, Locked: true},
+			{Position: evo.Position{Layer: 0.5, X: 0.5, Y: -0.5, Z: 0.1}, Neuron: evo.Hidden, Activation: evo.SteepenedSigmoid, Bias: -1.25, TimeConstant: 0.5},
+			{Position: evo.Position{Layer: 1.0, X: 0.5}, Neuron: evo.Output, Activation: evo.Sigmoid, Bias: 0.5},
+		},
+		Conns: []evo.Conn{
+			{Source: evo.Position{Layer: 0.0, X: 0.25}, Target: evo.Position{Layer: 0.5, X: 0.5, Y: -0.5, Z: 0.1}, Weight: 1.5, Enabled: true, Locked: true},
+			{Source: evo.Position{Layer: 0.5, X: 0.5, Y: -0.5, Z: 0.1}, Target: evo.Position{Layer: 1.0, X: 0.5}, Weight: -0.75, Hebbian: evo.Hebbian{A: 1.0, B: -0.5, C: 0.25, D: -0.125, Rate: 0.1}},
+		},
+	}
+}
+
+func newTestGenome(id int64) evo.Genome {
+	return evo.Genome{
+		ID:         id,
+		Species:    3,
+		Age:        7,
+		Fitness:    12.5,
+		Novelty:    0.125,
+		Objectives: []float64{12.5, -5},
+		Solved:     true,
+		Behavior:   []float64{0.1, 0.9},
+		Traits:     []float64{0.3},
+		Encoded:    newTestSubstrate(),
+		Decoded:    evo.Substrate{Nodes: newTestSubstrate().Nodes[:1]},
+		Parents:    []int64{5, 6},
+		Birth:      4,
+		Mutations:  []string{"add-node", "weight"},
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+
+	// A genome with a behavior that is not a slice of floats
+	other := newTestGenome(2)
+	other.Behavior = map[string]interface{}{"moves": 3.0}
+	other.Objectives = nil
+	other.Traits = nil
+	other.Decoded = evo.Substrate{}
+	other.Parents = nil
+	other.Birth = 0
+	other.Mutations = nil
+
+	var cases = []struct {
+		Desc     string
+		Value    interface{}
+		Expected interface{}
+		Target   interface{}
+	}{
+		{Desc: "substrate", Value: newTestSubstrate(), Target: &evo.Substrate{}},
+		{Desc: "substrate pointer", Value: &evo.Substrate{}, Expected: evo.Substrate{}, Target: &evo.Substrate{}},
+		{Desc: "genome", Value: newTestGenome(1), Target: &evo.Genome{}},
+		{Desc: "genome with other behavior", Value: other, Target: &evo.Genome{}},
+		{
+			Desc:   "population",
+			Value:  evo.Population{Generation: 4, Genomes: []evo.Genome{newTestGenome(1), other}},
+			Target: &evo.Population{},
+		},
+		{
+			Desc:   "species",
+			Value:  evo.Species{ID: 3, Decay: 0.2, Champion: 1, Example: newTestGenome(1)},
+			Target: &evo.Species{},
+		},
+	}
+
+	for _, f := range []Format{JSON, Binary} {
+		for _, c := range cases {
+			t.Run(f.String()+" "+c.Desc, func(t *testing.T) {
+				b := &bytes.Buffer{}
+				if err := Encode(b, f, c.Value); err != nil {
+					t.Fatalf("error not expected on encode: %v", err)
+				}
+				target := reflect.New(reflect.TypeOf(c.Target).Elem()).Interface()
+				if err := Decode(b, target); err != nil {
+					t.Fatalf("error not expected on decode: %v", err)
+				}
+				expected := c.Expected
+				if expected == nil {
+					expected = c.Value
+				}
+				if actual := reflect.ValueOf(target).Elem().Interface(); !reflect.DeepEqual(actual, expected) {
+					t.Errorf("incorrect value after round trip:\nexpected %+v\nactual   %+v", expected, actual)
+				}
+			})
+		}
+	}
+}
+
+func TestEncodeHeader(t *testing.T) {
+
+	// The JSON format names the neurons and activations
+	b := &bytes.Buffer{}
+	if err := Encode(b, 0, newTestGenome(1)); err != nil {
+		t.Fatalf("error not expected: %v", err)
+	}
+	for _, s := range []string{`"version":1`, `"kind":"genome"`, `"tau":0.5`, `"parents":[5,6]`, `"mutations":["add-node","weight"]`, `"neuron":"hidden"`, `"activation":"steepened-sigmoid"`} {
+		if !strings.Contains(b.String(), s) {
+			t.Errorf("JSON encoding does not contain %s", s)
+		}
+	}
+
+	// The binary format begins with the magic bytes, version and kind, and is more compact
+	n := b.Len()
+	b.Reset()
+	if err := Encode(b, Binary, newTestGenome(1)); err != nil {
+		t.Fatalf("error not expected: %v", err)
+	}
+	if !bytes.HasPrefix(b.Bytes(), []byte{'E', 'V', 'O', Version, byte(genomeKind)}) {
+		t.Errorf("incorrect binary header: %v", b.Bytes()[:5])
+	}
+	if b.Len() >= n {
+		t.Errorf("binary encoding is not smaller than JSON: %d and %d bytes", b.Len(), n)
+	}
+}
+
+func TestCodecErrors(t *testing.T) {
+	var g evo.Genome
+	var cases = []struct {
+		Desc     string
+		Input    string
+		Target   interface{}
+		Expected error
+	}{
+		{Desc: "unknown json version", Input: `{"version":9,"kind":"genome","genome":{}}`, Target: &g, Expected: ErrUnknownVersion},
+		{Desc: "mismatched json kind", Input: `{"version":1,"kind":"species","species":{}}`, Target: &g, Expected: ErrMismatchedKind},
+		{Desc: "unsupported json type", Input: `{"version":1,"kind":"genome","genome":{}}`, Target: &bytes.Buffer{}, Expected: ErrUnsupportedType},
+		{
+			Desc:     "unknown neuron",
+			Input:    `{"version":1,"kind":"substrate","substrate":{"nodes":[{"neuron":"bogus","activation":"direct"}]}}`,
+			Target:   &evo.Substrate{},
+			Expected: ErrUnknownNeuron,
+		},
+		{
+			Desc:     "unknown activation",
+			Input:    `{"version":1,"kind":"substrate","substrate":{"nodes":[{"neuron":"input","activation":"bogus"}]}}`,
+			Target:   &evo.Substrate{},
+			Expected: ErrUnknownActivation,
+		},
+		{Desc: "unknown binary version", Input: "EVO\x09\x02", Target: &g, Expected: ErrUnknownVersion},
+		{Desc: "mismatched binary kind", Input: "EVO\x01\x04", Target: &g, Expected: ErrMismatchedKind},
+		{Desc: "unknown binary rule", Input: "EVO\x01\x01\x00\x00\x01" + strings.Repeat("\x00", 74) + "\x07", Target: &evo.Substrate{}, Expected: ErrInvalidBinary},
+		{Desc: "truncated binary", Input: "EVO\x01\x02\x02", Target: &g, Expected: io.ErrUnexpectedEOF},
+		{Desc: "corrupt binary length", Input: "EVO\x01\x01\xff\xff\xff\xff\xff\xff\x01", Target: &evo.Substrate{}, Expected: ErrInvalidBinary},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Desc, func(t *testing.T) {
+			if err := Decode(strings.NewReader(c.Input), c.Target); err != c.Expected {
+				t.Errorf("incorrect error: expected %v, actual %v", c.Expected, err)
+			}
+		})
+	}
+
+	// Encoding errors
+	if err := Encode(ioutil.Discard, JSON, 5); err != ErrUnsupportedType {
+		t.Errorf("incorrect error: expected %v, actual %v", ErrUnsupportedType, err)
+	}
+	if err := Encode(ioutil.Discard, Format(9), g); err != ErrUnknownFormat {
+		t.Errorf("incorrect error: expected %v, actual %v", ErrUnknownFormat, err)
+	}
+}
+
+// Custom activations are registered once per process
+var square, errSquare = evo.RegisterActivation("codec-square", func(x float64) float64 { return x * x }, nil)
+
+func TestCustomActivation(t *testing.T) {
+	if errSquare != nil {
+		t.Fatalf("error not expected registering: %v", errSquare)
+	}
+	expected := evo.Substrate{
+		Nodes: []evo.Node{{Position: evo.Position{Layer: 1.0}, Neuron: evo.Output, Activation: square}},
+	}
+
+	// Both formats round trip the activation
+	for _, f := range []Format{JSON, Binary} {
+		t.Run(f.String(), func(t *testing.T) {
+			b := &bytes.Buffer{}
+			if err := Encode(b, f, expected); err != nil {
+				t.Fatalf("error not expected encoding: %v", err)
+			}
+			var actual evo.Substrate
+			if err := Decode(b, &actual); err != nil {
+				t.Fatalf("error not expected decoding: %v", err)
+			}
+			if !reflect.DeepEqual(expected, actual) {
+				t.Errorf("incorrect substrate: expected %v, actual %v", expected, actual)
+			}
+		})
+	}
+
+	// A binary encoding from a program that registered the activation with another value is mapped
+	// to this program's value
+	t.Run("remapped", func(t *testing.T) {
+		var actual evo.Substrate
+		if err := Decode(customEncoding(t, "codec-square", 200, 200), &actual); err != nil {
+			t.Fatalf("error not expected decoding: %v", err)
+		}
+		if !reflect.DeepEqual(expected, actual) {
+			t.Errorf("incorrect substrate: expected %v, actual %v", expected, actual)
+		}
+	})
+
+	// Activations registered by the encoding program but not this one are only an error if used
+	t.Run("unknown and unused", func(t *testing.T) {
+		var actual evo.Substrate
+		if err := Decode(customEncoding(t, "bogus", 200, byte(square)), &actual); err != nil {
+			t.Fatalf("error not expected decoding: %v", err)
+		}
+		if !reflect.DeepEqual(expected, actual) {
+			t.Errorf("incorrect substrate: expected %v, actual %v", expected, actual)
+		}
+	})
+	t.Run("unknown and used", func(t *testing.T) {
+		var actual evo.Substrate
+		if err := Decode(customEncoding(t, "bogus", 200, 200), &actual); err != ErrUnknownActivation {
+			t.Errorf("incorrect error: expected %v, actual %v", ErrUnknownActivation, err)
+		}
+	})
+}
+
+// Returns a binary encoding, as from a program that registered the named activation with the
+// value, of a substrate with a single output node whose activation is written as the given byte
+func customEncoding(t *testing.T, name string, value, used byte) *bytes.Buffer {
+	b := &bytes.Buffer{}
+	e := &encoder{w: bufio.NewWriter(b)}
+	e.bytes([]byte(magic))
+	e.byte(Version)
+	e.byte(byte(substrateKind))
+	e.uint(1)
+	e.byte(value)
+	e.string(name)
+	e.uint(1)
+	e.position(evo.Position{Layer: 1.0})
+	e.byte(byte(evo.Output))
+	e.byte(used)
+	e.float(0.0)
+	e.bool(false)
+	e.float(0.0)
+	e.uint(0)
+	if err := e.w.Flush(); err != nil {
+		t.Fatalf("error not expected writing: %v", err)
+	}
+	return b
+}
+
+func TestSaveBest(t *testing.T) {
+	dir, err := ioutil.TempDir("", "codec")
+	if err != nil {
+		t.Fatalf("error not expected: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	// Save the best genome on completion
+	pop := evo.Population{Genomes: []evo.Genome{newTestGenome(1), newTestGenome(2), newTestGenome(3)}}
+	pop.Genomes[1].Fitness = 20.0
+	pop.Genomes[2].Solved = false
+	pop.Genomes[2].Fitness = 30.0
+	for _, f := range []Format{JSON, Binary} {
+		t.Run(f.String(), func(t *testing.T) {
+			filename := filepath.Join(dir, "best."+f.String())
+			if err := SaveBest(filename, f)(pop); err != nil {
+				t.Fatalf("error not expected: %v", err)
+			}
+			var g evo.Genome
+			if err := ReadFile(filename, &g); err != nil {
+				t.Fatalf("error not expected: %v", err)
+			}
+			if g.ID != 2 {
+				t.Errorf("incorrect best genome: expected 2, actual %d", g.ID)
+			}
+		})
+	}
+
+	// Other comparisons can be used
+	if g := Best(pop, evo.ByFitness); g.ID != 3 {
+		t.Errorf("incorrect best genome by fitness: expected 3, actual %d", g.ID)
+	}
+}
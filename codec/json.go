@@ -0,0 +1,341 @@
+package codec
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/klokare/evo"
+)
+
+// The JSON document wraps the value with the header
+type document struct {
+	Version    int             `json:"version"`
+	Kind       string          `json:"kind"`
+	Substrate  *jsonSubstrate  `json:"substrate,omitempty"`
+	Genome     *jsonGenome     `json:"genome,omitempty"`
+	Population *jsonPopulation `json:"population,omitempty"`
+	Species    *jsonSpecies    `json:"species,omitempty"`
+}
+
+type jsonPosition struct {
+	Layer float64 `json:"layer"`
+	X     float64 `json:"x"`
+	Y     float64 `json:"y"`
+	Z     float64 `json:"z"`
+}
+
+type jsonNode struct {
+	Position   jsonPosition `json:"position"`
+	Neuron     string       `json:"neuron"`
+	Activation string       `json:"activation"`
+	Bias       float64      `json:"bias"`
+	Locked     bool         `json:"locked,omitempty"`
+	Tau        float64      `json:"tau,omitempty"`
+}
+
+type jsonConn struct {
+	Source  jsonPosition `json:"source"`
+	Target  jsonPosition `json:"target"`
+	Weight  float64      `json:"weight"`
+	Enabled bool         `json:"enabled"`
+	Locked  bool         `json:"locked,omitempty"`
+	Hebbian *jsonHebbian `json:"hebbian,omitempty"`
+}
+
+type jsonHebbian struct {
+	A    float64 `json:"a"`
+	B    float64 `json:"b"`
+	C    float64 `json:"c"`
+	D    float64 `json:"d"`
+	Rate float64 `json:"rate"`
+}
+
+type jsonSubstrate struct {
+	Nodes []jsonNode `json:"nodes"`
+	Conns []jsonConn `json:"conns"`
+}
+
+type jsonGenome struct {
+	ID         int64           `json:"id"`
+	Species    int             `json:"species"`
+	Age        int             `json:"age"`
+	Fitness    float64         `json:"fitness"`
+	Novelty    float64         `json:"novelty"`
+	Objectives []float64       `json:"objectives,omitempty"`
+	Solved     bool            `json:"solved"`
+	Behavior   json.RawMessage `json:"behavior,omitempty"`
+	Traits     []float64       `json:"traits,omitempty"`
+	Encoded    jsonSubstrate   `json:"encoded"`
+	Decoded    *jsonSubstrate  `json:"decoded,omitempty"`
+	Parents    []int64         `json:"parents,omitempty"`
+	Birth      int             `json:"birth,omitempty"`
+	Mutations  []string        `json:"mutations,omitempty"`
+}
+
+type jsonPopulation struct {
+	Generation int          `json:"generation"`
+	Genomes    []jsonGenome `json:"genomes"`
+}
+
+type jsonSpecies struct {
+	ID       int64      `json:"id"`
+	Decay    float64    `json:"decay"`
+	Champion int64      `json:"champion"`
+	Example  jsonGenome `json:"example"`
+}
+
+// Encode the value as a JSON document
+func encodeJSON(w io.Writer, v interface{}) (err error) {
+	var k kind
+	if k, err = kindOf(v); err != nil {
+		return
+	}
+	doc := document{Version: Version, Kind: k.String()}
+	switch x := v.(type) {
+	case evo.Substrate:
+		doc.Substrate = toJSONSubstrate(x)
+	case *evo.Substrate:
+		doc.Substrate = toJSONSubstrate(*x)
+	case evo.Genome:
+		doc.Genome, err = toJSONGenome(x)
+	case *evo.Genome:
+		doc.Genome, err = toJSONGenome(*x)
+	case evo.Population:
+		doc.Population, err = toJSONPopulation(x)
+	case *evo.Population:
+		doc.Population, err = toJSONPopulation(*x)
+	case evo.Species:
+		doc.Species, err = toJSONSpecies(x)
+	case *evo.Species:
+		doc.Species, err = toJSONSpecies(*x)
+	}
+	if err != nil {
+		return
+	}
+	return json.NewEncoder(w).Encode(doc)
+}
+
+// Decode the JSON document into the value
+func decodeJSON(r io.Reader, v interface{}) (err error) {
+	var doc document
+	if err = json.NewDecoder(r).Decode(&doc); err != nil {
+		return
+	}
+	if doc.Version != Version {
+		return ErrUnknownVersion
+	}
+	switch x := v.(type) {
+	case *evo.Substrate:
+		if doc.Kind != substrateKind.String() || doc.Substrate == nil {
+			return ErrMismatchedKind
+		}
+		*x, err = fromJSONSubstrate(*doc.Substrate)
+	case *evo.Genome:
+		if doc.Kind != genomeKind.String() || doc.Genome == nil {
+			return ErrMismatchedKind
+		}
+		*x, err = fromJSONGenome(*doc.Genome)
+	case *evo.Population:
+		if doc.Kind != populationKind.String() || doc.Population == nil {
+			return ErrMismatchedKind
+		}
+		*x, err = fromJSONPopulation(*doc.Population)
+	case *evo.Species:
+		if doc.Kind != speciesKind.String() || doc.Species == nil {
+			return ErrMismatchedKind
+		}
+		*x, err = fromJSONSpecies(*doc.Species)
+	default:
+		err = ErrUnsupportedType
+	}
+	return
+}
+
+func toJSONPosition(p evo.Position) jsonPosition {
+	return jsonPosition{Layer: p.Layer, X: p.X, Y: p.Y, Z: p.Z}
+}
+
+func fromJSONPosition(p jsonPosition) evo.Position {
+	return evo.Position{Layer: p.Layer, X: p.X, Y: p.Y, Z: p.Z}
+}
+
+func toJSONSubstrate(s evo.Substrate) *jsonSubstrate {
+	x := &jsonSubstrate{
+		Nodes: make([]jsonNode, len(s.Nodes)),
+		Conns: make([]jsonConn, len(s.Conns)),
+	}
+	for i, n := range s.Nodes {
+		x.Nodes[i] = jsonNode{
+			Position:   toJSONPosition(n.Position),
+			Neuron:     n.Neuron.String(),
+			Activation: n.Activation.String(),
+			Bias:       n.Bias,
+			Locked:     n.Locked,
+			Tau:        n.TimeConstant,
+		}
+	}
+	for i, c := range s.Conns {
+		x.Conns[i] = jsonConn{
+			Source:  toJSONPosition(c.Source),
+			Target:  toJSONPosition(c.Target),
+			Weight:  c.Weight,
+			Enabled: c.Enabled,
+			Locked:  c.Locked,
+		}
+		if c.Hebbian != (evo.Hebbian{}) {
+			x.Conns[i].Hebbian = &jsonHebbian{A: c.A, B: c.B, C: c.C, D: c.D, Rate: c.Rate}
+		}
+	}
+	return x
+}
+
+func fromJSONSubstrate(x jsonSubstrate) (s evo.Substrate, err error) {
+	if len(x.Nodes) > 0 {
+		s.Nodes = make([]evo.Node, len(x.Nodes))
+	}
+	for i, n := range x.Nodes {
+		s.Nodes[i] = evo.Node{Position: fromJSONPosition(n.Position), Bias: n.Bias, Locked: n.Locked, TimeConstant: n.Tau}
+		if s.Nodes[i].Neuron, err = neuron(n.Neuron); err != nil {
+			return
+		}
+		if s.Nodes[i].Activation, err = activation(n.Activation); err != nil {
+			return
+		}
+	}
+	if len(x.Conns) > 0 {
+		s.Conns = make([]evo.Conn, len(x.Conns))
+	}
+	for i, c := range x.Conns {
+		s.Conns[i] = evo.Conn{
+			Source:  fromJSONPosition(c.Source),
+			Target:  fromJSONPosition(c.Target),
+			Weight:  c.Weight,
+			Enabled: c.Enabled,
+			Locked:  c.Locked,
+		}
+		if h := c.Hebbian; h != nil {
+			s.Conns[i].Hebbian = evo.Hebbian{A: h.A, B: h.B, C: h.C, D: h.D, Rate: h.Rate}
+		}
+	}
+	return
+}
+
+func toJSONGenome(g evo.Genome) (x *jsonGenome, err error) {
+	x = &jsonGenome{
+		ID:         g.ID,
+		Species:    g.Species,
+		Age:        g.Age,
+		Fitness:    g.Fitness,
+		Novelty:    g.Novelty,
+		Objectives: g.Objectives,
+		Solved:     g.Solved,
+		Traits:     g.Traits,
+		Encoded:    *toJSONSubstrate(g.Encoded),
+		Parents:    g.Parents,
+		Birth:      g.Birth,
+		Mutations:  g.Mutations,
+	}
+	if g.Behavior != nil {
+		if x.Behavior, err = json.Marshal(g.Behavior); err != nil {
+			return
+		}
+	}
+	if g.Decoded.Complexity() > 0 {
+		x.Decoded = toJSONSubstrate(g.Decoded)
+	}
+	return
+}
+
+func fromJSONGenome(x jsonGenome) (g evo.Genome, err error) {
+	g = evo.Genome{
+		ID:         x.ID,
+		Species:    x.Species,
+		Age:        x.Age,
+		Fitness:    x.Fitness,
+		Novelty:    x.Novelty,
+		Objectives: x.Objectives,
+		Solved:     x.Solved,
+		Traits:     x.Traits,
+		Parents:    x.Parents,
+		Birth:      x.Birth,
+		Mutations:  x.Mutations,
+	}
+	if len(x.Behavior) > 0 {
+		if g.Behavior, err = behavior(x.Behavior); err != nil {
+			return
+		}
+	}
+	if g.Encoded, err = fromJSONSubstrate(x.Encoded); err != nil {
+		return
+	}
+	if x.Decoded != nil {
+		g.Decoded, err = fromJSONSubstrate(*x.Decoded)
+	}
+	return
+}
+
+func toJSONPopulation(p evo.Population) (x *jsonPopulation, err error) {
+	x = &jsonPopulation{Generation: p.Generation, Genomes: make([]jsonGenome, len(p.Genomes))}
+	for i, g := range p.Genomes {
+		var y *jsonGenome
+		if y, err = toJSONGenome(g); err != nil {
+			return
+		}
+		x.Genomes[i] = *y
+	}
+	return
+}
+
+func fromJSONPopulation(x jsonPopulation) (p evo.Population, err error) {
+	p = evo.Population{Generation: x.Generation, Genomes: make([]evo.Genome, len(x.Genomes))}
+	for i, g := range x.Genomes {
+		if p.Genomes[i], err = fromJSONGenome(g); err != nil {
+			return
+		}
+	}
+	return
+}
+
+func toJSONSpecies(s evo.Species) (x *jsonSpecies, err error) {
+	var g *jsonGenome
+	if g, err = toJSONGenome(s.Example); err != nil {
+		return
+	}
+	x = &jsonSpecies{ID: s.ID, Decay: s.Decay, Champion: s.Champion, Example: *g}
+	return
+}
+
+func fromJSONSpecies(x jsonSpecies) (s evo.Species, err error) {
+	s = evo.Species{ID: x.ID, Decay: x.Decay, Champion: x.Champion}
+	s.Example, err = fromJSONGenome(x.Example)
+	return
+}
+
+// Decode the behavior, preferring a slice of floats, the form used by novelty search, when
+// possible
+func behavior(b []byte) (v interface{}, err error) {
+	var fs []float64
+	if err = json.Unmarshal(b, &fs); err == nil {
+		return fs, nil
+	}
+	err = json.Unmarshal(b, &v)
+	return
+}
+
+// Neuron returns the neuron type by name
+func neuron(name string) (evo.Neuron, error) {
+	for n := evo.Input; n <= evo.Output; n++ {
+		if n.String() == name {
+			return n, nil
+		}
+	}
+	return 0, ErrUnknownNeuron
+}
+
+// Activation returns the activation type by name
+func activation(name string) (evo.Activation, error) {
+	if a, ok := evo.Activations[name]; ok {
+		return a, nil
+	}
+	return 0, ErrUnknownActivation
+}
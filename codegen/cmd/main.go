@@ -0,0 +1,58 @@
+// Command cmd generates a standalone Go source file from a genome or substrate saved with the codec
+// package.
+//
+//	cmd -package xor -output xor/network.go best.json
+//
+// A genome's decoded substrate is generated. The generated file depends only on the standard
+// library.
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+
+	"github.com/klokare/evo"
+	"github.com/klokare/evo/codec"
+	"github.com/klokare/evo/codegen"
+)
+
+func main() {
+
+	// Parse the flags
+	var (
+		output = flag.String("output", "", "filename for the generated source or standard output if empty")
+		pkg    = flag.String("package", "network", "name of the generated package")
+		fn     = flag.String("func", "Activate", "name of the generated function")
+	)
+	flag.Parse()
+	args := flag.Args()
+	if len(args) != 1 {
+		log.Fatal("you must specify one genome or substrate file")
+	}
+
+	// Load the substrate, trying the file as a genome first
+	var sub evo.Substrate
+	var g evo.Genome
+	err := codec.ReadFile(args[0], &g)
+	switch {
+	case err == nil:
+		sub = g.Decoded
+	case err == codec.ErrMismatchedKind:
+		err = codec.ReadFile(args[0], &sub)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Generate the source
+	gen := codegen.Generator{Package: *pkg, Func: *fn}
+	if *output == "" {
+		err = gen.Substrate(os.Stdout, sub)
+	} else {
+		err = gen.WriteFile(*output, sub)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,180 @@
+// Package codegen generates standalone Go source for an evolved network so that it can be deployed
+// without this module, gonum or the translator. The generated file has a single exported function,
+// Activate, which takes the inputs and returns the outputs using the same activation math as
+// evo.Activation.Activate. It imports nothing beyond the standard library's math package. Custom
+// activations added with evo.RegisterActivation have no source to generate and are reported with
+// ErrUnsupportedActivation.
+package codegen
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/format"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+
+	"github.com/klokare/evo"
+	"github.com/klokare/evo/internal/export"
+	"github.com/klokare/evo/network/forward"
+)
+
+// Known errors
+var (
+	ErrUnsupportedActivation = errors.New("activation cannot be generated")
+	ErrInvalidWeight         = errors.New("network has a weight or bias that is not a finite number")
+	ErrEmptyNetwork          = export.ErrEmptyNetwork
+)
+
+// The activation functions, named and written as in evo.Activation.Activate
+var activations = map[evo.Activation]struct {
+	name, body string
+}{
+	evo.Direct:           {"direct", "return x"},
+	evo.Sigmoid:          {"sigmoid", "return 1.0 / (1.0 + math.Exp(-x))"},
+	evo.SteepenedSigmoid: {"steepenedSigmoid", "return 1.0 / (1.0 + math.Exp(-4.9*x))"},
+	evo.Tanh:             {"tanh", "return math.Tanh(x)"},
+	evo.InverseAbs:       {"inverseAbs", "return x / (1.0 + math.Abs(x))"},
+	evo.Sin:              {"sin", "return math.Sin(x)"},
+	evo.Gauss:            {"gauss", "return math.Exp(-2.0 * x * x)"},
+	evo.ReLU:             {"relu", "if x > 0 {\n\t\treturn x\n\t}\n\treturn 0"},
+}
+
+// Generator writes the Go source of networks
+type Generator struct {
+	Package string // Name of the generated package. Defaults to "network".
+	Func    string // Name of the generated function. Defaults to "Activate".
+}
+
+// Substrate writes the source of the network translated from the decoded substrate
+func (g Generator) Substrate(w io.Writer, sub evo.Substrate) error {
+	return export.Substrate(w, g, sub)
+}
+
+// WriteFile writes the source of the network translated from the decoded substrate to the file
+func (g Generator) WriteFile(filename string, sub evo.Substrate) error {
+	return export.WriteFile(filename, g, sub)
+}
+
+// Network writes the source of the forward network. Each neuron's value is the sum of its bias and
+// the weighted values of each source layer, added in the order the network adds them, so that the
+// generated function returns the same outputs as the network.
+func (g Generator) Network(w io.Writer, net forward.Network) (err error) {
+
+	// Check for errors
+	if len(net.Layers) < 2 {
+		return ErrEmptyNetwork
+	}
+	used := make(map[evo.Activation]bool, len(activations))
+	for i, lay := range net.Layers[1:] {
+		for j, a := range lay.Activations {
+			if _, ok := activations[a]; !ok {
+				return fmt.Errorf("%w: %v in layer %d", ErrUnsupportedActivation, a, i+1)
+			}
+			used[a] = true
+			if j < len(lay.Biases) && !finite(lay.Biases[j]) {
+				return ErrInvalidWeight
+			}
+		}
+	}
+
+	// Set the names
+	pkg, fn := g.Package, g.Func
+	if pkg == "" {
+		pkg = "network"
+	}
+	if fn == "" {
+		fn = "Activate"
+	}
+
+	// Write the header
+	ni, no := len(net.Layers[0].Activations), len(net.Layers[len(net.Layers)-1].Activations)
+	b := new(bytes.Buffer)
+	fmt.Fprintf(b, "// Code generated by github.com/klokare/evo/codegen. DO NOT EDIT.\n\n")
+	fmt.Fprintf(b, "package %s\n\n", pkg)
+	if needsMath(used) {
+		fmt.Fprintf(b, "import \"math\"\n\n")
+	}
+
+	// Write the function
+	fmt.Fprintf(b, "// %s activates the network with the %d inputs and returns its %d outputs. It panics if given\n", fn, ni, no)
+	fmt.Fprintf(b, "// the wrong number of inputs.\n")
+	fmt.Fprintf(b, "func %s(inputs []float64) []float64 {\n", fn)
+	fmt.Fprintf(b, "if len(inputs) != %d {\npanic(\"network requires %d inputs\")\n}\n", ni, ni)
+	fmt.Fprintf(b, "l0 := inputs\n")
+	for i, lay := range net.Layers[1:] {
+		l := i + 1
+		fmt.Fprintf(b, "\n// Layer %d\n", l)
+		fmt.Fprintf(b, "l%d := make([]float64, %d)\n", l, len(lay.Activations))
+		for j, a := range lay.Activations {
+			expr := "0.0"
+			if j < len(lay.Biases) {
+				expr = literal(lay.Biases[j])
+			}
+			for k, src := range lay.Sources {
+				var dot string
+				rows, _ := lay.Weights[k].Dims()
+				for s := 0; s < rows; s++ {
+					x := lay.Weights[k].At(s, j)
+					if x == 0.0 {
+						continue
+					}
+					if !finite(x) {
+						return ErrInvalidWeight
+					}
+					if dot != "" {
+						dot += " + "
+					}
+					dot += fmt.Sprintf("l%d[%d]*%s", src, s, literal(x))
+				}
+				if dot != "" {
+					expr = fmt.Sprintf("%s + (%s)", expr, dot)
+				}
+			}
+			fmt.Fprintf(b, "l%d[%d] = %s(%s)\n", l, j, activations[a].name, expr)
+		}
+	}
+	fmt.Fprintf(b, "return l%d\n}\n", len(net.Layers)-1)
+
+	// Write the activation functions used
+	as := make([]evo.Activation, 0, len(used))
+	for a := range used {
+		as = append(as, a)
+	}
+	sort.Slice(as, func(i, j int) bool { return as[i] < as[j] })
+	for _, a := range as {
+		x := activations[a]
+		fmt.Fprintf(b, "\nfunc %s(x float64) float64 {\n\t%s\n}\n", x.name, x.body)
+	}
+
+	// Format and write the source
+	var src []byte
+	if src, err = format.Source(b.Bytes()); err != nil {
+		return
+	}
+	_, err = w.Write(src)
+	return
+}
+
+// Returns true if any of the activations use the math package
+func needsMath(used map[evo.Activation]bool) bool {
+	for a := range used {
+		if a != evo.Direct && a != evo.ReLU {
+			return true
+		}
+	}
+	return false
+}
+
+func finite(x float64) bool { return !math.IsNaN(x) && !math.IsInf(x, 0) }
+
+// Returns the shortest literal that parses to exactly the value
+func literal(x float64) string {
+	s := strconv.FormatFloat(x, 'g', -1, 64)
+	if x < 0 {
+		return "(" + s + ")"
+	}
+	return s
+}
@@ -0,0 +1,219 @@
+package codegen
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/klokare/evo"
+	"github.com/klokare/evo/network/forward"
+	"gonum.org/v1/gonum/mat"
+)
+
+// Returns a substrate with a hidden neuron of each activation, a connection that skips the hidden
+// layer and a disabled connection
+func newTestSubstrate() evo.Substrate {
+	sub := evo.Substrate{}
+	ins := []evo.Position{{Layer: 0.0, X: 0.0}, {Layer: 0.0, X: 0.5}, {Layer: 0.0, X: 1.0}}
+	for _, p := range ins {
+		sub.Nodes = append(sub.Nodes, evo.Node{Position: p, Neuron: evo.Input, Activation: evo.Direct})
+	}
+	outs := []evo.Position{{Layer: 1.0, X: 0.0}, {Layer: 1.0, X: 1.0}}
+	sub.Nodes = append(sub.Nodes,
+		evo.Node{Position: outs[0], Neuron: evo.Output, Activation: evo.Sigmoid, Bias: -0.25},
+		evo.Node{Position: outs[1], Neuron: evo.Output, Activation: evo.Direct, Bias: 0.125},
+	)
+	as := []evo.Activation{evo.Direct, evo.Sigmoid, evo.SteepenedSigmoid, evo.Tanh, evo.InverseAbs, evo.Sin, evo.Gauss, evo.ReLU}
+	for i, a := range as {
+		p := evo.Position{Layer: 0.5, X: float64(i) / float64(len(as)-1)}
+		sub.Nodes = append(sub.Nodes, evo.Node{Position: p, Neuron: evo.Hidden, Activation: a, Bias: 0.1 * float64(i-3)})
+		for j, in := range ins {
+			sub.Conns = append(sub.Conns, evo.Conn{Source: in, Target: p, Weight: 0.7*float64(j) - 0.3*float64(i) + 0.05, Enabled: true})
+		}
+		for j, out := range outs {
+			sub.Conns = append(sub.Conns, evo.Conn{Source: p, Target: out, Weight: 1.0/float64(i+1) - 0.4*float64(j), Enabled: true})
+		}
+	}
+	sub.Conns = append(sub.Conns,
+		evo.Conn{Source: ins[1], Target: outs[1], Weight: 2.5, Enabled: true},
+		evo.Conn{Source: ins[2], Target: outs[0], Weight: 9.0, Enabled: false},
+	)
+	return sub
+}
+
+func TestGeneratorParity(t *testing.T) {
+	gobin, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go tool not available")
+	}
+
+	// Translate the substrate into the original network
+	sub := newTestSubstrate()
+	x, err := (forward.Translator{}).Translate(sub)
+	if err != nil {
+		t.Fatalf("error not expected translating: %v", err)
+	}
+	net := x.(forward.Network)
+
+	// Generate the network into a standalone program that prints its outputs for each line of inputs
+	dir := t.TempDir()
+	if err = (Generator{Package: "main"}).WriteFile(filepath.Join(dir, "network.go"), sub); err != nil {
+		t.Fatalf("error not expected generating: %v", err)
+	}
+	files := map[string]string{
+		"go.mod": "module generated\n\ngo 1.18\n",
+		"main.go": `package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+func main() {
+	s := bufio.NewScanner(os.Stdin)
+	for s.Scan() {
+		var xs []float64
+		for _, f := range strings.Fields(s.Text()) {
+			x, _ := strconv.ParseFloat(f, 64)
+			xs = append(xs, x)
+		}
+		for _, y := range Activate(xs) {
+			fmt.Print(strconv.FormatFloat(y, 'g', -1, 64), " ")
+		}
+		fmt.Println()
+	}
+}
+`,
+	}
+	for name, src := range files {
+		if err = os.WriteFile(filepath.Join(dir, name), []byte(src), 0644); err != nil {
+			t.Fatalf("error not expected writing %s: %v", name, err)
+		}
+	}
+
+	// Activate both with the same inputs
+	inputs := [][]float64{
+		{0.0, 0.0, 0.0},
+		{1.0, 0.0, 1.0},
+		{-1.0, 0.5, 0.25},
+		{3.5, -2.25, 10.0},
+		{-100.0, 100.0, 1e-9},
+	}
+	stdin := new(strings.Builder)
+	for _, in := range inputs {
+		for _, v := range in {
+			stdin.WriteString(strconv.FormatFloat(v, 'g', -1, 64) + " ")
+		}
+		stdin.WriteString("\n")
+	}
+	cmd := exec.Command(gobin, "run", ".")
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GOFLAGS=-mod=mod", "GOWORK=off")
+	cmd.Stdin = strings.NewReader(stdin.String())
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("error not expected running generated code: %v\n%s", err, out)
+	}
+	lines := strings.Split(strings.TrimSpace(string(out)), "\n")
+	if len(lines) != len(inputs) {
+		t.Fatalf("incorrect number of results: expected %d, actual %d\n%s", len(inputs), len(lines), out)
+	}
+
+	for i, in := range inputs {
+		m, err := net.Activate(mat.NewDense(1, len(in), in))
+		if err != nil {
+			t.Fatalf("error not expected activating: %v", err)
+		}
+		fs := strings.Fields(lines[i])
+		_, c := m.Dims()
+		if len(fs) != c {
+			t.Errorf("incorrect number of outputs for %v: expected %d, actual %d", in, c, len(fs))
+			continue
+		}
+		for j, f := range fs {
+			act, _ := strconv.ParseFloat(f, 64)
+			if exp := m.At(0, j); math.Abs(exp-act) > 1e-12 {
+				t.Errorf("incorrect output %d for %v: expected %v, actual %v", j, in, exp, act)
+			}
+		}
+	}
+}
+
+func TestGeneratorNetwork(t *testing.T) {
+	var cases = []struct {
+		Desc     string
+		Network  forward.Network
+		Err      error
+		Contains []string
+		Excludes []string
+	}{
+		{
+			Desc: "direct network has no imports",
+			Network: forward.Network{Layers: []forward.Layer{
+				{Activations: []evo.Activation{evo.Direct, evo.Direct}},
+				{Activations: []evo.Activation{evo.ReLU}, Biases: []float64{0.5}, Sources: []int{0}, Weights: []*mat.Dense{mat.NewDense(2, 1, []float64{-1.5, 0.0})}},
+			}},
+			Contains: []string{"package network", "func Activate(inputs []float64) []float64", "relu(0.5 + (l0[0] * (-1.5)))", "return l1"},
+			Excludes: []string{"import", "l0[1]", "func direct"},
+		},
+		{
+			Desc: "activations use math",
+			Network: forward.Network{Layers: []forward.Layer{
+				{Activations: []evo.Activation{evo.Direct}},
+				{Activations: []evo.Activation{evo.Gauss}, Biases: []float64{0.0}, Sources: []int{0}, Weights: []*mat.Dense{mat.NewDense(1, 1, []float64{1e-7})}},
+			}},
+			Contains: []string{"import \"math\"", "gauss(0 + (l0[0] * 1e-07))", "math.Exp(-2.0 * x * x)"},
+		},
+		{
+			Desc:    "missing output layer",
+			Network: forward.Network{Layers: []forward.Layer{{Activations: []evo.Activation{evo.Direct}}}},
+			Err:     ErrEmptyNetwork,
+		},
+		{
+			Desc: "unknown activation",
+			Network: forward.Network{Layers: []forward.Layer{
+				{Activations: []evo.Activation{evo.Direct}},
+				{Activations: []evo.Activation{evo.Activation(99)}, Biases: []float64{0.0}},
+			}},
+			Err: ErrUnsupportedActivation,
+		},
+		{
+			Desc: "infinite weight",
+			Network: forward.Network{Layers: []forward.Layer{
+				{Activations: []evo.Activation{evo.Direct}},
+				{Activations: []evo.Activation{evo.Tanh}, Biases: []float64{0.0}, Sources: []int{0}, Weights: []*mat.Dense{mat.NewDense(1, 1, []float64{math.Inf(1)})}},
+			}},
+			Err: ErrInvalidWeight,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Desc, func(t *testing.T) {
+			b := new(bytes.Buffer)
+			err := Generator{}.Network(b, c.Network)
+			if !errors.Is(err, c.Err) {
+				t.Fatalf("incorrect error: expected %v, actual %v", c.Err, err)
+			}
+			src := b.String()
+			for _, s := range c.Contains {
+				if !strings.Contains(src, s) {
+					t.Errorf("source does not contain %q:\n%s", s, src)
+				}
+			}
+			for _, s := range c.Excludes {
+				if strings.Contains(src, s) {
+					t.Errorf("source should not contain %q:\n%s", s, src)
+				}
+			}
+		})
+	}
+}
@@ -117,6 +117,28 @@ func (c Comparison) Compare(a, b Genome) int8 {
 	}
 }
 
+// Value returns the genome's score for the comparison as a single number where, as with Compare,
+// higher is better. This allows comparisons to be used as objectives in multi-objective selection.
+func (c Comparison) Value(g Genome) float64 {
+	switch c {
+	case ByNovelty:
+		return g.Novelty
+	case ByComplexity:
+		return -float64(g.Complexity())
+	case ByAge:
+		return -float64(g.ID)
+	case BySolved:
+		if g.Solved {
+			return 1.0
+		}
+		return 0.0
+	case BySpecies:
+		return float64(g.Species)
+	default: // by fitness
+		return g.Fitness
+	}
+}
+
 // Compares provides map of compare functions by name
 var (
 	Comparisons = map[string]Comparison{
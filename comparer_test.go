@@ -281,3 +281,24 @@ func TestSortBy(t *testing.T) {
 		})
 	}
 }
+
+func TestComparisonValue(t *testing.T) {
+
+	// The value should order genomes in the same way as the comparison
+	a := Genome{ID: 2, Species: 1, Fitness: 1.0, Novelty: 2.0, Encoded: Substrate{Nodes: make([]Node, 3)}}
+	b := Genome{ID: 1, Species: 2, Fitness: 2.0, Novelty: 1.0, Solved: true, Encoded: Substrate{Nodes: make([]Node, 2)}}
+	for _, c := range []Comparison{ByFitness, ByNovelty, ByComplexity, ByAge, BySolved, BySpecies} {
+		t.Run(c.String(), func(t *testing.T) {
+			var x int8
+			switch va, vb := c.Value(a), c.Value(b); {
+			case va < vb:
+				x = -1
+			case va > vb:
+				x = 1
+			}
+			if e := c.Compare(a, b); x != e {
+				t.Errorf("incorrect value order: expected %d, actual %d", e, x)
+			}
+		})
+	}
+}
@@ -0,0 +1,127 @@
+// Command cmd prints the effective configuration recorded by a run and compares those of two runs.
+//
+//	cmd print run.json
+//	cmd diff run1.json run2.json
+//
+// The files are written by a config.Recorder. Each key is shown with its value and the source that
+// supplied it. Keys that were not found in any source are shown with a value of "-".
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"text/tabwriter"
+
+	"github.com/klokare/evo/config"
+)
+
+func main() {
+
+	// Parse the flags and command
+	unset := flag.Bool("unset", true, "include keys that were not found in any source")
+	flag.Parse()
+	args := flag.Args()
+	if len(args) == 0 {
+		log.Fatal("you must specify a command: print or diff")
+	}
+
+	// Run the command
+	var err error
+	switch args[0] {
+	case "print":
+		if len(args) != 2 {
+			log.Fatal("print requires one configuration file")
+		}
+		err = printConfig(os.Stdout, args[1], *unset)
+	case "diff":
+		if len(args) != 3 {
+			log.Fatal("diff requires two configuration files")
+		}
+		err = diffConfigs(os.Stdout, args[1], args[2])
+	default:
+		err = fmt.Errorf("unknown command %s", args[0])
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+// Print the effective configuration in the file
+func printConfig(w io.Writer, filename string, unset bool) error {
+	recs, err := readRecords(filename)
+	if err != nil {
+		return err
+	}
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "KEY\tVALUE\tSOURCE")
+	for _, r := range recs {
+		if r.Value == nil && !unset {
+			continue
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", r.Key, value(&r), source(&r))
+	}
+	return tw.Flush()
+}
+
+// Print the keys whose values differ between the configurations in the files
+func diffConfigs(w io.Writer, filename1, filename2 string) error {
+	a, err := readRecords(filename1)
+	if err != nil {
+		return err
+	}
+	b, err := readRecords(filename2)
+	if err != nil {
+		return err
+	}
+	diffs := config.Diff(a, b)
+	if len(diffs) == 0 {
+		_, err = fmt.Fprintln(w, "configurations are the same")
+		return err
+	}
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(tw, "KEY\t%s\tSOURCE\t%s\tSOURCE\n", filename1, filename2)
+	for _, d := range diffs {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", d.Key, value(d.A), source(d.A), value(d.B), source(d.B))
+	}
+	return tw.Flush()
+}
+
+// Read the records from the file
+func readRecords(filename string) (recs []config.Record, err error) {
+	var f *os.File
+	if f, err = os.Open(filename); err != nil {
+		return
+	}
+	defer f.Close()
+	return config.ReadRecords(f)
+}
+
+// Format the record's value
+func value(r *config.Record) string {
+	if r == nil || r.Value == nil {
+		return "-"
+	}
+	b, err := json.Marshal(r.Value)
+	if err != nil {
+		return fmt.Sprint(r.Value)
+	}
+	return string(b)
+}
+
+// Format the record's source including, if from another namespace, the key that supplied the value
+func source(r *config.Record) string {
+	switch {
+	case r == nil:
+		return "(not resolved)"
+	case r.Value == nil:
+		return "-"
+	case r.Match != "" && r.Match != r.Key:
+		return r.Source + " (" + r.Match + ")"
+	default:
+		return r.Source
+	}
+}
@@ -12,40 +12,139 @@ type Source interface {
 	Value(namespaces []string, key string) interface{}
 }
 
-// Configurer provides a query-based configuration helper
+// Configurer provides a query-based configuration helper. If the configurer has a registry, the
+// helpers declare their keys with it, keys missing from the source take their registered
+// defaults, and Validate checks the source strictly against the registered keys. If the
+// configurer has a recorder, every key resolved is recorded with its value and source.
 type Configurer struct {
 	Source
+	Registry *Registry
+	Recorder *Recorder
+}
+
+// Declare the keys used by a helper. The keys are ignored if the configurer has no registry.
+func (c *Configurer) Declare(keys ...Key) {
+	if c.Registry != nil {
+		c.Registry.Register(keys...)
+	}
+}
+
+// Validate the source against the registered keys. Nil is returned if there is no registry.
+func (c *Configurer) Validate() error {
+	if c.Registry == nil {
+		return nil
+	}
+	return c.Registry.Validate(c.Source)
 }
 
 // Int returns the int value for the key or 0.
 func (c *Configurer) Int(key string) int {
-	x := find(c.Source, key)
-	if x == nil {
-		return 0
+	z, _ := toInt(c.find(key))
+	return z
+}
+
+// Ints returns the slice of int values for the key or nil.
+func (c *Configurer) Ints(key string) []int {
+	z, _ := toInts(c.find(key))
+	return z
+}
+
+// Float64 returns the float64 value for the key or 0.0.
+func (c *Configurer) Float64(key string) float64 {
+	z, _ := toFloat64(c.find(key))
+	return z
+}
+
+// Float64s returns the slice of float64 values for the key or nil.
+func (c *Configurer) Float64s(key string) []float64 {
+	z, _ := toFloat64s(c.find(key))
+	return z
+}
+
+// Bool returns the boolean value for the key or false.
+func (c *Configurer) Bool(key string) bool {
+	z, _ := toBool(c.find(key))
+	return z
+}
+
+// Bools returns the slice of boolean values for the key or nil.
+func (c *Configurer) Bools(key string) []bool {
+	z, _ := toBools(c.find(key))
+	return z
+}
+
+// String returns the string value for the key or "".
+func (c *Configurer) String(key string) string {
+	z, _ := toString(c.find(key))
+	return z
+}
+
+// Strings returns the slice of string values for the key or nil.
+func (c *Configurer) Strings(key string) []string {
+	z, _ := toStrings(c.find(key))
+	return z
+}
+
+// Activation returns the activation for the key or 0.
+func (c *Configurer) Activation(key string) evo.Activation {
+	z, _ := toActivation(c.find(key))
+	return z
+}
+
+// Activations returns the slice of activations for the key or nil.
+func (c *Configurer) Activations(key string) []evo.Activation {
+	z, _ := toActivations(c.find(key))
+	return z
+}
+
+// Comparison returns the comparison for the key or 0.
+func (c *Configurer) Comparison(key string) evo.Comparison {
+	z, _ := toComparison(c.find(key))
+	return z
+}
+
+// Comparisons returns the slice of comparisons for the key or nil.
+func (c *Configurer) Comparisons(key string) []evo.Comparison {
+	z, _ := toComparisons(c.find(key))
+	return z
+}
+
+// Find the value for the key in the source or, if missing, the key's registered default
+func (c *Configurer) find(key string) interface{} {
+	x, match, name := trace(c.Source, key)
+	if x == nil && c.Registry != nil {
+		if k, ok := c.Registry.Lookup(key); ok && k.Default != nil {
+			x, name = k.Default, "default"
+		}
+	}
+	if c.Recorder != nil {
+		c.Recorder.Record(Record{Key: key, Match: match, Value: x, Source: name})
 	}
+	return x
+}
+
+// The conversions below return the value as the configurer's accessors do and whether it could be
+// parsed as that type.
+
+func toInt(x interface{}) (int, bool) {
 	if y, ok := x.(int); ok {
-		return y
+		return y, true
 	}
 	if y, ok := x.(string); ok {
 		z, err := strconv.Atoi(y)
 		if err == nil {
-			return z
+			return z, true
 		}
 	}
 	if y, ok := x.(float64); ok {
-		return int(y)
+		return int(y), true
 	}
-	return 0
+	return 0, false
 }
 
-// Ints returns the slice of int values for the key or nil.
-func (c *Configurer) Ints(key string) []int {
-	x := find(c.Source, key)
-	if x == nil {
-		return nil
-	}
+func toInts(x interface{}) ([]int, bool) {
 	if y, ok := x.([]int); ok {
-		return y
+		return y, true
 	}
 	if y, ok := x.([]string); ok {
 		var err error
@@ -53,40 +152,44 @@ func (c *Configurer) Ints(key string) []int {
 		for i := 0; i < len(y); i++ {
 			z[i], err = strconv.Atoi(y[i])
 			if err != nil {
-				return nil
+				return nil, false
 			}
 		}
-		return z
+		return z, true
 	}
-	return nil
+	if y, ok := x.([]interface{}); ok { // as decoded from JSON
+		z := make([]int, len(y))
+		for i := 0; i < len(y); i++ {
+			switch a := y[i].(type) {
+			case int:
+				z[i] = a
+			case float64:
+				z[i] = int(a)
+			default:
+				return nil, false
+			}
+		}
+		return z, true
+	}
+	return nil, false
 }
 
-// Float64 returns the float64 value for the key or 0.0.
-func (c *Configurer) Float64(key string) float64 {
-	x := find(c.Source, key)
-	if x == nil {
-		return 0.0
-	}
+func toFloat64(x interface{}) (float64, bool) {
 	if y, ok := x.(float64); ok {
-		return y
+		return y, true
 	}
 	if y, ok := x.(string); ok {
 		z, err := strconv.ParseFloat(y, 64)
 		if err == nil {
-			return z
+			return z, true
 		}
 	}
-	return 0.0
+	return 0.0, false
 }
 
-// Float64s returns the slice of float64 values for the key or nil.
-func (c *Configurer) Float64s(key string) []float64 {
-	x := find(c.Source, key)
-	if x == nil {
-		return nil
-	}
+func toFloat64s(x interface{}) ([]float64, bool) {
 	if y, ok := x.([]float64); ok {
-		return y
+		return y, true
 	}
 	if y, ok := x.([]string); ok {
 		var err error
@@ -94,187 +197,194 @@ func (c *Configurer) Float64s(key string) []float64 {
 		for i := 0; i < len(y); i++ {
 			z[i], err = strconv.ParseFloat(y[i], 64)
 			if err != nil {
-				return nil
+				return nil, false
+			}
+		}
+		return z, true
+	}
+	if y, ok := x.([]interface{}); ok { // as decoded from JSON
+		z := make([]float64, len(y))
+		for i := 0; i < len(y); i++ {
+			switch a := y[i].(type) {
+			case float64:
+				z[i] = a
+			case int:
+				z[i] = float64(a)
+			default:
+				return nil, false
 			}
 		}
-		return z
+		return z, true
 	}
-	return nil
+	return nil, false
 }
 
-// Bool returns the boolean value for the key or false.
-func (c *Configurer) Bool(key string) bool {
-	x := find(c.Source, key)
-	if x == nil {
-		return false
-	}
+func toBool(x interface{}) (bool, bool) {
 	if y, ok := x.(bool); ok {
-		return y
+		return y, true
 	}
 	if y, ok := x.(int); ok {
-		return y != 0
+		return y != 0, true
 	}
 	if y, ok := x.(float64); ok {
-		return int(y) != 0
+		return int(y) != 0, true
 	}
 	if y, ok := x.(string); ok {
-		return strings.ToLower(y) == "true"
+		s := strings.ToLower(y)
+		return s == "true", s == "true" || s == "false"
 	}
-	return false
+	return false, false
 }
 
-// Bools returns the slice of boolean values for the key or nil.
-func (c *Configurer) Bools(key string) []bool {
-	x := find(c.Source, key)
-	if x == nil {
-		return nil
-	}
+func toBools(x interface{}) ([]bool, bool) {
 	if y, ok := x.([]bool); ok {
-		return y
+		return y, true
 	}
 	if y, ok := x.([]int); ok {
 		z := make([]bool, len(y))
 		for i := 0; i < len(y); i++ {
 			z[i] = y[i] != 0
 		}
-		return z
+		return z, true
 	}
 	if y, ok := x.([]string); ok {
+		valid := true
 		z := make([]bool, len(y))
 		for i := 0; i < len(y); i++ {
-			z[i] = strings.ToLower(y[i]) == "true"
+			var ok bool
+			z[i], ok = toBool(y[i])
+			valid = valid && ok
 		}
-		return z
+		return z, valid
 	}
-	return nil
+	return nil, false
 }
 
-// String returns the string value for the key or "".
-func (c *Configurer) String(key string) string {
-	x := find(c.Source, key)
-	if x == nil {
-		return ""
-	}
+func toString(x interface{}) (string, bool) {
 	if y, ok := x.(string); ok {
-		return y
+		return y, true
 	}
-	return ""
+	return "", false
 }
 
-// Strings returns the slice of string values for the key or nil.
-func (c *Configurer) Strings(key string) []string {
-	x := find(c.Source, key)
-	if x == nil {
-		return nil
-	}
+func toStrings(x interface{}) ([]string, bool) {
 	if y, ok := x.([]string); ok {
-		return y
+		return y, true
 	}
-	return nil
+	if y, ok := x.([]interface{}); ok { // as decoded from JSON
+		z := make([]string, len(y))
+		for i := 0; i < len(y); i++ {
+			if z[i], ok = y[i].(string); !ok {
+				return nil, false
+			}
+		}
+		return z, true
+	}
+	return nil, false
 }
 
-// Activation returns the activation for the key or 0.
-func (c *Configurer) Activation(key string) evo.Activation {
-	x := find(c.Source, key)
-	if x == nil {
-		return 0
-	}
+func toActivation(x interface{}) (evo.Activation, bool) {
 	if y, ok := x.(int); ok {
-		return evo.Activation(y)
+		return evo.Activation(y), true
 	}
 	if y, ok := x.(float64); ok {
-		return evo.Activation(int(y))
+		return evo.Activation(int(y)), true
 	}
 	if y, ok := x.(string); ok {
-		return evo.Activations[strings.ToLower(y)]
+		z, ok := evo.Activations[strings.ToLower(y)]
+		return z, ok
 	}
-	return 0
+	return 0, false
 }
 
-// Activations returns the slice of activations for the key or nil.
-func (c *Configurer) Activations(key string) []evo.Activation {
-	x := find(c.Source, key)
-	if x == nil {
-		return nil
-	}
+func toActivations(x interface{}) ([]evo.Activation, bool) {
 	if y, ok := x.([]int); ok {
 		z := make([]evo.Activation, len(y))
 		for i := 0; i < len(y); i++ {
 			z[i] = evo.Activation(y[i])
 		}
-		return z
+		return z, true
 	}
 	if y, ok := x.([]string); ok {
+		valid := true
 		z := make([]evo.Activation, len(y))
 		for i := 0; i < len(y); i++ {
-			z[i] = evo.Activations[y[i]]
+			var ok bool
+			z[i], ok = evo.Activations[strings.ToLower(y[i])]
+			valid = valid && ok
 		}
-		return z
+		return z, valid
 	}
 	if y, ok := x.([]interface{}); ok {
+		valid := true
 		z := make([]evo.Activation, len(y))
 		for i := 0; i < len(y); i++ {
 			if a, ok := y[i].(int); ok {
 				z[i] = evo.Activation(a)
+			} else if a, ok := y[i].(float64); ok { // as decoded from JSON
+				z[i] = evo.Activation(int(a))
 			} else if b, ok := y[i].(string); ok {
-				z[i] = evo.Activations[b]
+				z[i], ok = evo.Activations[strings.ToLower(b)]
+				valid = valid && ok
+			} else {
+				valid = false
 			}
 		}
-		return z
+		return z, valid
 	}
-	return nil
+	return nil, false
 }
 
-// Comparison returns the comparison for the key or 0.
-func (c *Configurer) Comparison(key string) evo.Comparison {
-	x := find(c.Source, key)
-	if x == nil {
-		return 0
-	}
+func toComparison(x interface{}) (evo.Comparison, bool) {
 	if y, ok := x.(int); ok {
-		return evo.Comparison(y)
+		return evo.Comparison(y), true
 	}
 	if y, ok := x.(float64); ok {
-		return evo.Comparison(int(y))
+		return evo.Comparison(int(y)), true
 	}
 	if y, ok := x.(string); ok {
-		return evo.Comparisons[strings.ToLower(y)]
+		z, ok := evo.Comparisons[strings.ToLower(y)]
+		return z, ok
 	}
-	return 0
+	return 0, false
 }
 
-// Comparisons returns the slice of comparisons for the key or nil.
-func (c *Configurer) Comparisons(key string) []evo.Comparison {
-	x := find(c.Source, key)
-	if x == nil {
-		return nil
-	}
+func toComparisons(x interface{}) ([]evo.Comparison, bool) {
 	if y, ok := x.([]int); ok {
 		z := make([]evo.Comparison, len(y))
 		for i := 0; i < len(y); i++ {
 			z[i] = evo.Comparison(y[i])
 		}
-		return z
+		return z, true
 	}
 	if y, ok := x.([]string); ok {
+		valid := true
 		z := make([]evo.Comparison, len(y))
 		for i := 0; i < len(y); i++ {
-			z[i] = evo.Comparisons[y[i]]
+			var ok bool
+			z[i], ok = evo.Comparisons[y[i]]
+			valid = valid && ok
 		}
-		return z
+		return z, valid
 	}
 	if y, ok := x.([]interface{}); ok {
+		valid := true
 		z := make([]evo.Comparison, len(y))
 		for i := 0; i < len(y); i++ {
 			if a, ok := y[i].(int); ok {
 				z[i] = evo.Comparison(a)
+			} else if a, ok := y[i].(float64); ok { // as decoded from JSON
+				z[i] = evo.Comparison(int(a))
 			} else if b, ok := y[i].(string); ok {
-				z[i] = evo.Comparisons[b]
+				z[i], ok = evo.Comparisons[b]
+				valid = valid && ok
+			} else {
+				valid = false
 			}
 		}
-		return z
+		return z, valid
 	}
-	return nil
+	return nil, false
 }
 
 func split(key string) (ns []string, k string) {
@@ -290,18 +400,8 @@ func split(key string) (ns []string, k string) {
 	}
 }
 
+// Find the value for the key in the source, searching from the key's namespace outward
 func find(src Source, key string) interface{} {
-	// Find the value in the source
-	var x interface{}
-	ns, k := split(key)
-	for i := len(ns); i > 0; i-- {
-		x = src.Value(ns[:i], k)
-		if x != nil {
-			break // value found
-		}
-	}
-	if x == nil {
-		x = src.Value(nil, k) // try without a namespace
-	}
+	x, _, _ := trace(src, key)
 	return x
 }
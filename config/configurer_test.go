@@ -52,18 +52,21 @@ var (
 		"name1|ints-b":       []string{"2", "3"}, // value may come in as string
 		"name2|name3|ints-c": []int{3, 4},
 		"ints-bad":           []string{"5", "bad"},
+		"ints-json":          []interface{}{5.0, 6.0}, // as decoded from JSON
 
 		// strings
 		"strings-a":             []string{"foo", "fee"},
 		"name1|strings-b":       []string{"goo", "gee"},
 		"name2|name3|strings-c": []string{"hoo", "hee"},
 		"strings-bad":           []int{123, 234},
+		"strings-json":          []interface{}{"ioo", "iee"}, // as decoded from JSON
 
 		// float64s
 		"floats-a":             []float64{1.1, 2.2},
 		"name1|floats-b":       []string{"1.1", "2.2"}, // value may come in a string
 		"name2|name3|floats-c": []float64{3.3, 4.4},
 		"floats-bad":           []string{"1.23", "bad"},
+		"floats-json":          []interface{}{5.5, 6.0}, // as decoded from JSON
 
 		// bools
 		"bools-a":             []bool{false, true},
@@ -76,12 +79,14 @@ var (
 		"name1|activations-b":       []string{"sigmoid", "tanh"}, // value may come in a string
 		"name2|name3|activations-c": []int{3, 4},
 		"activations-bad":           []float64{1.1, 2.2},
+		"activations-json":          []interface{}{1.0, "tanh"}, // as decoded from JSON
 
 		// compare -- only available as string
 		"compares-a":             []int{1, 2},
 		"name1|compares-b":       []string{"age", "novelty"}, // value may come in a string
 		"name2|name3|compares-c": []string{"novelty", "complexity"},
 		"compares-bad":           []float64{1.1, 2.2},
+		"compares-json":          []interface{}{1.0, "novelty"}, // as decoded from JSON
 	}
 )
 
@@ -172,6 +177,11 @@ func TestInts(t *testing.T) {
 			Key:      "ints-bad",
 			Expected: nil,
 		},
+		{
+			Desc:     "decoded from JSON",
+			Key:      "ints-json",
+			Expected: []int{5, 6},
+		},
 		{
 			Desc:     "no namespace",
 			Key:      "ints-a",
@@ -302,6 +312,11 @@ func TestFloat64s(t *testing.T) {
 			Key:      "floats-bad",
 			Expected: nil,
 		},
+		{
+			Desc:     "decoded from JSON",
+			Key:      "floats-json",
+			Expected: []float64{5.5, 6.0},
+		},
 		{
 			Desc:     "no namespace",
 			Key:      "floats-a",
@@ -571,6 +586,11 @@ func TestStrings(t *testing.T) {
 			Key:      "strings-bad",
 			Expected: nil,
 		},
+		{
+			Desc:     "decoded from JSON",
+			Key:      "strings-json",
+			Expected: []string{"ioo", "iee"},
+		},
 		{
 			Desc:     "no namespace",
 			Key:      "strings-a",
@@ -685,6 +705,30 @@ func TestActivation(t *testing.T) {
 	}
 }
 
+// Custom activations are registered once per process
+var step, errStep = evo.RegisterActivation("config-step", func(x float64) float64 {
+	if x > 0 {
+		return 1
+	}
+	return 0
+}, nil)
+
+func TestCustomActivation(t *testing.T) {
+	if errStep != nil {
+		t.Fatalf("error not expected registering: %v", errStep)
+	}
+	cfg := &Configurer{Source: mockSource{
+		"activation":  "Config-Step",
+		"activations": []interface{}{"tanh", "CONFIG-STEP"},
+	}}
+	if x := cfg.Activation("activation"); x != step {
+		t.Errorf("incorrect activation: expected %s, actual %s", step, x)
+	}
+	if x := cfg.Activations("activations"); len(x) != 2 || x[0] != evo.Tanh || x[1] != step {
+		t.Errorf("incorrect activations: expected [tanh %s], actual %v", step, x)
+	}
+}
+
 func TestActivations(t *testing.T) {
 	var cases = []struct {
 		Desc     string
@@ -731,6 +775,11 @@ func TestActivations(t *testing.T) {
 			Key:      "name1|name2|activations-a",
 			Expected: []evo.Activation{evo.Direct, evo.Sigmoid},
 		},
+		{
+			Desc:     "as decoded from JSON",
+			Key:      "activations-json",
+			Expected: []evo.Activation{evo.Direct, evo.Tanh},
+		},
 	}
 
 	// Create the configurer
@@ -863,6 +912,11 @@ func TestComparisons(t *testing.T) {
 			Key:      "name1|name2|compares-a",
 			Expected: []evo.Comparison{evo.ByFitness, evo.ByNovelty},
 		},
+		{
+			Desc:     "as decoded from JSON",
+			Key:      "compares-json",
+			Expected: []evo.Comparison{evo.ByFitness, evo.ByNovelty},
+		},
 	}
 
 	// Create the configurer
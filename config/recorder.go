@@ -0,0 +1,142 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Namer is a source with a name, such as the file it was loaded from, used when recording where
+// values came from
+type Namer interface {
+	Name() string
+}
+
+// Tracer is a composite source that reports which of its sources supplied a value
+type Tracer interface {
+	Trace(namespaces []string, key string) (x interface{}, name string)
+}
+
+// Lookup returns the value from the source for the namespace and key, along with the name of the
+// source that supplied it. The name is the source's own if it is a Namer and its type otherwise.
+func Lookup(src Source, ns []string, k string) (x interface{}, name string) {
+	if t, ok := src.(Tracer); ok {
+		return t.Trace(ns, k)
+	}
+	if n, ok := src.(Namer); ok {
+		name = n.Name()
+	} else {
+		name = fmt.Sprintf("%T", src)
+	}
+	return src.Value(ns, k), name
+}
+
+// Record of a key resolved by the configurer
+type Record struct {
+	Key    string      `json:"key"`             // Key requested by the helper
+	Match  string      `json:"match,omitempty"` // Key, with the namespace at which it was found, that supplied the value
+	Value  interface{} `json:"value"`           // Resolved value or nil if the key was not found
+	Source string      `json:"source"`          // Name of the source that supplied the value, "default" for a registered default or empty if not found
+}
+
+// Recorder keeps the keys resolved by a configurer so that the effective configuration of a run
+// can be saved and compared with others
+type Recorder struct {
+	mu      sync.Mutex
+	records map[string]Record
+}
+
+// NewRecorder returns a new, empty recorder
+func NewRecorder() *Recorder {
+	return &Recorder{records: make(map[string]Record, 100)}
+}
+
+// Record the resolution of a key. A key resolved again replaces the earlier record.
+func (r *Recorder) Record(rec Record) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.records == nil {
+		r.records = make(map[string]Record, 100)
+	}
+	r.records[rec.Key] = rec
+}
+
+// Records returns the records ordered by key
+func (r *Recorder) Records() []Record {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	recs := make([]Record, 0, len(r.records))
+	for _, rec := range r.records {
+		recs = append(recs, rec)
+	}
+	sort.Slice(recs, func(i, j int) bool { return recs[i].Key < recs[j].Key })
+	return recs
+}
+
+// Write the records to the writer as JSON
+func (r *Recorder) Write(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "\t")
+	return enc.Encode(r.Records())
+}
+
+// ReadRecords reads records written by a recorder. The records are returned ordered by key.
+func ReadRecords(r io.Reader) (recs []Record, err error) {
+	if err = json.NewDecoder(r).Decode(&recs); err != nil {
+		return
+	}
+	sort.Slice(recs, func(i, j int) bool { return recs[i].Key < recs[j].Key })
+	return
+}
+
+// Difference between the records of two runs for a key. A record is nil if the key was not
+// resolved in that run.
+type Difference struct {
+	Key  string
+	A, B *Record
+}
+
+// Diff returns the keys whose values differ between the two sets of records, ordered by key. The
+// values are compared as written so records should be read from their files before comparing.
+func Diff(a, b []Record) (diffs []Difference) {
+	as := make(map[string]*Record, len(a))
+	bs := make(map[string]*Record, len(b))
+	keys := make([]string, 0, len(a)+len(b))
+	for i := range a {
+		as[a[i].Key] = &a[i]
+		keys = append(keys, a[i].Key)
+	}
+	for i := range b {
+		if _, ok := as[b[i].Key]; !ok {
+			keys = append(keys, b[i].Key)
+		}
+		bs[b[i].Key] = &b[i]
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		ra, rb := as[k], bs[k]
+		if ra == nil || rb == nil || !reflect.DeepEqual(ra.Value, rb.Value) {
+			diffs = append(diffs, Difference{Key: k, A: ra, B: rb})
+		}
+	}
+	return
+}
+
+// Find the value for the key in the source, returning the key with the namespace at which it was
+// found and the name of the source that supplied it
+func trace(src Source, key string) (x interface{}, match, name string) {
+	ns, k := split(key)
+	for i := len(ns); i > 0; i-- {
+		if x, name = Lookup(src, ns[:i], k); x != nil {
+			return x, strings.Join(ns[:i], "|") + "|" + k, name // value found
+		}
+	}
+	if x, name = Lookup(src, nil, k); x != nil { // try without a namespace
+		return x, k, name
+	}
+	return nil, "", ""
+}
@@ -0,0 +1,88 @@
+package config
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+// mockNamed is a source with a name
+type mockNamed struct {
+	mockSource
+	name string
+}
+
+func (m mockNamed) Name() string { return m.name }
+
+func TestConfigurerRecorder(t *testing.T) {
+
+	// Create the configurer with a registered default
+	cfg := &Configurer{
+		Source:   mockNamed{mockSource: mockSource{"a|size": 5, "rate": 0.5}, name: "file"},
+		Registry: NewRegistry(),
+		Recorder: NewRecorder(),
+	}
+	cfg.Declare(Key{Name: "a|b|steps", Type: Int, Default: 3})
+
+	// Resolve the keys
+	cfg.Int("a|b|size")
+	cfg.Float64("a|b|rate")
+	cfg.Int("a|b|steps")
+	cfg.String("a|b|missing")
+
+	expected := []Record{
+		{Key: "a|b|missing"},
+		{Key: "a|b|rate", Match: "rate", Value: 0.5, Source: "file"},
+		{Key: "a|b|size", Match: "a|size", Value: 5, Source: "file"},
+		{Key: "a|b|steps", Value: 3, Source: "default"},
+	}
+	if recs := cfg.Recorder.Records(); !reflect.DeepEqual(recs, expected) {
+		t.Errorf("incorrect records:\nexpected %+v\nactual   %+v", expected, recs)
+	}
+
+	// Write and read the records. Numbers are read back as float64.
+	b := &bytes.Buffer{}
+	if err := cfg.Recorder.Write(b); err != nil {
+		t.Fatalf("error not expected: %v", err)
+	}
+	recs, err := ReadRecords(b)
+	if err != nil {
+		t.Fatalf("error not expected: %v", err)
+	}
+	expected[2].Value = 5.0
+	expected[3].Value = 3.0
+	if !reflect.DeepEqual(recs, expected) {
+		t.Errorf("incorrect records read:\nexpected %+v\nactual   %+v", expected, recs)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	a := []Record{
+		{Key: "same", Value: 1.0, Source: "file"},
+		{Key: "changed", Value: 1.0, Source: "file"},
+		{Key: "only-a", Value: "x", Source: "flag"},
+		{Key: "same-list", Value: []interface{}{"a", "b"}, Source: "file"},
+		{Key: "moved", Value: 2.0, Source: "file"},
+	}
+	b := []Record{
+		{Key: "only-b", Value: true, Source: "environment"},
+		{Key: "same-list", Value: []interface{}{"a", "b"}, Source: "file"},
+		{Key: "moved", Value: 2.0, Source: "flag"},
+		{Key: "changed", Value: 2.0, Source: "flag"},
+		{Key: "same", Value: 1.0, Source: "file"},
+	}
+	diffs := Diff(a, b)
+	keys := make([]string, len(diffs))
+	for i, d := range diffs {
+		keys[i] = d.Key
+	}
+	if expected := []string{"changed", "only-a", "only-b"}; !reflect.DeepEqual(keys, expected) {
+		t.Fatalf("incorrect differences: expected %v, actual %v", expected, keys)
+	}
+	if diffs[0].A.Value != 1.0 || diffs[0].B.Value != 2.0 {
+		t.Errorf("incorrect changed values: expected 1 and 2, actual %v and %v", diffs[0].A.Value, diffs[0].B.Value)
+	}
+	if diffs[1].B != nil || diffs[2].A != nil {
+		t.Error("records missing from a run should be nil")
+	}
+}
@@ -0,0 +1,259 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Known errors
+var (
+	ErrUnknownKey   = errors.New("unknown key")
+	ErrMissingKey   = errors.New("missing required key")
+	ErrInvalidValue = errors.New("value cannot be parsed")
+	ErrOutOfRange   = errors.New("value out of range")
+)
+
+// Type of a configuration value
+type Type byte
+
+// Known types
+const (
+	Int Type = iota + 1
+	Ints
+	Float64
+	Float64s
+	Bool
+	Bools
+	String
+	Strings
+	Activation
+	Activations
+	Comparison
+	Comparisons
+)
+
+var typeNames = map[Type]string{
+	Int:         "int",
+	Ints:        "[]int",
+	Float64:     "float64",
+	Float64s:    "[]float64",
+	Bool:        "bool",
+	Bools:       "[]bool",
+	String:      "string",
+	Strings:     "[]string",
+	Activation:  "activation",
+	Activations: "[]activation",
+	Comparison:  "comparison",
+	Comparisons: "[]comparison",
+}
+
+func (t Type) String() string {
+	if s, ok := typeNames[t]; ok {
+		return s
+	}
+	return "unknown"
+}
+
+// Key describes a known configuration key
+type Key struct {
+	Name        string      // Fully namespaced name such as "neat|selector|survival-rate"
+	Type        Type        // Type of the value
+	Default     interface{} // Value used when the key is not found in the source. Optional.
+	Min, Max    float64     // Range of numeric values, inclusive. Ignored if both are zero.
+	Required    bool        // The key must be found in the source if there is no default
+	Description string
+}
+
+// Lister is a source that can list its keys, each with its full namespace. Only the keys of
+// listers can be checked for unknown keys.
+type Lister interface {
+	Keys() []string
+}
+
+// KeyError is a problem with the configuration of a single key
+type KeyError struct {
+	Key   string      // Name of the key
+	Value interface{} // Value found in the source, if any
+	Err   error       // One of the known errors
+}
+
+func (e *KeyError) Error() string {
+	if e.Value == nil {
+		return fmt.Sprintf("%s: %v", e.Key, e.Err)
+	}
+	return fmt.Sprintf("%s: %v: %v", e.Key, e.Err, e.Value)
+}
+
+// Unwrap returns the underlying known error
+func (e *KeyError) Unwrap() error { return e.Err }
+
+// Errors combines the problems found while validating a configuration
+type Errors []error
+
+func (e Errors) Error() string {
+	s := make([]string, len(e))
+	for i, err := range e {
+		s[i] = err.Error()
+	}
+	return strings.Join(s, "\n")
+}
+
+// Unwrap returns the combined errors
+func (e Errors) Unwrap() []error { return e }
+
+// Registry of known configuration keys. Helpers declare the keys they use, usually in their
+// constructors, so that a configuration can be checked strictly and its keys documented.
+type Registry struct {
+	mu   sync.RWMutex
+	keys map[string]Key
+}
+
+// NewRegistry returns a new, empty registry
+func NewRegistry() *Registry {
+	return &Registry{keys: make(map[string]Key, 100)}
+}
+
+// Register the keys. A key declared again replaces the earlier declaration.
+func (r *Registry) Register(keys ...Key) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.keys == nil {
+		r.keys = make(map[string]Key, 100)
+	}
+	for _, k := range keys {
+		r.keys[k.Name] = k
+	}
+}
+
+// Lookup returns the key with the name, if registered
+func (r *Registry) Lookup(name string) (k Key, ok bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	k, ok = r.keys[name]
+	return
+}
+
+// Keys returns the registered keys ordered by name
+func (r *Registry) Keys() []Key {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	keys := make([]Key, 0, len(r.keys))
+	for _, k := range r.keys {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].Name < keys[j].Name })
+	return keys
+}
+
+// Validate the source against the registered keys. The source's keys are reported if unknown, when
+// it is a lister, along with required keys that are missing and values that cannot be parsed as
+// their key's type or are out of range. All problems are combined into a single Errors value.
+func (r *Registry) Validate(src Source) error {
+	var errs Errors
+	keys := r.Keys()
+
+	// Check for unknown keys. As a key is searched for in its namespace and then each enclosing
+	// one, the source's key is known if its namespace begins some registered key's namespace.
+	if l, ok := src.(Lister); ok {
+		names := l.Keys()
+		sort.Strings(names)
+		for _, name := range names {
+			if !known(keys, name) {
+				errs = append(errs, &KeyError{Key: name, Err: ErrUnknownKey})
+			}
+		}
+	}
+
+	// Check the registered keys
+	for _, k := range keys {
+		x := find(src, k.Name)
+		if x == nil {
+			if k.Required && k.Default == nil {
+				errs = append(errs, &KeyError{Key: k.Name, Err: ErrMissingKey})
+			}
+			continue
+		}
+		nums, ok := parse(k.Type, x)
+		if !ok {
+			errs = append(errs, &KeyError{Key: k.Name, Value: x, Err: ErrInvalidValue})
+			continue
+		}
+		if k.Min != 0.0 || k.Max != 0.0 {
+			for _, v := range nums {
+				if v < k.Min || v > k.Max {
+					errs = append(errs, &KeyError{Key: k.Name, Value: x, Err: ErrOutOfRange})
+					break
+				}
+			}
+		}
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+// Known returns true if the source key would be found when looking up a registered key
+func known(keys []Key, name string) bool {
+	ns, k := split(name)
+	for _, key := range keys {
+		kns, kk := split(key.Name)
+		if kk != k || len(ns) > len(kns) {
+			continue
+		}
+		match := true
+		for i := range ns {
+			if ns[i] != kns[i] {
+				match = false
+				break
+			}
+		}
+		if match {
+			return true
+		}
+	}
+	return false
+}
+
+// Parse the value as the type, returning any numeric values for range checking
+func parse(t Type, x interface{}) (nums []float64, ok bool) {
+	switch t {
+	case Int:
+		var z int
+		z, ok = toInt(x)
+		nums = []float64{float64(z)}
+	case Ints:
+		var z []int
+		z, ok = toInts(x)
+		for _, v := range z {
+			nums = append(nums, float64(v))
+		}
+	case Float64:
+		var z float64
+		z, ok = toFloat64(x)
+		nums = []float64{z}
+	case Float64s:
+		nums, ok = toFloat64s(x)
+	case Bool:
+		_, ok = toBool(x)
+	case Bools:
+		_, ok = toBools(x)
+	case String:
+		_, ok = toString(x)
+	case Strings:
+		_, ok = toStrings(x)
+	case Activation:
+		_, ok = toActivation(x)
+	case Activations:
+		_, ok = toActivations(x)
+	case Comparison:
+		_, ok = toComparison(x)
+	case Comparisons:
+		_, ok = toComparisons(x)
+	}
+	return
+}
@@ -0,0 +1,160 @@
+package config
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/klokare/evo"
+)
+
+// mockLister is a source that can list its keys
+type mockLister struct{ mockSource }
+
+func (m mockLister) Keys() []string {
+	keys := make([]string, 0, len(m.mockSource))
+	for k := range m.mockSource {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+var testKeys = []Key{
+	{Name: "a|b|size", Type: Int, Min: 1.0, Max: math.Inf(1), Required: true},
+	{Name: "a|b|rate", Type: Float64, Min: 0.0, Max: 1.0},
+	{Name: "a|c|activation", Type: Activation},
+	{Name: "a|c|comparisons", Type: Comparisons},
+	{Name: "a|c|steps", Type: Int, Default: 3, Required: true},
+}
+
+func TestRegistryValidate(t *testing.T) {
+	var cases = []struct {
+		Desc     string
+		Source   Source
+		Expected []string // keys with errors
+		Errs     []error
+	}{
+		{
+			Desc:   "valid",
+			Source: mockLister{mockSource{"a|b|size": 10, "rate": 0.5, "a|activation": "sigmoid", "a|c|comparisons": []interface{}{"fitness", 2.0}}},
+		},
+		{
+			Desc:     "unknown keys",
+			Source:   mockLister{mockSource{"a|b|size": 10, "a|b|rates": 0.5, "b|rate": 0.5}},
+			Expected: []string{"a|b|rates", "b|rate"},
+			Errs:     []error{ErrUnknownKey, ErrUnknownKey},
+		},
+		{
+			Desc:     "unknown keys are not checked if the source cannot list them",
+			Source:   mockSource{"a|b|size": 10, "a|b|rates": 0.5},
+			Expected: nil,
+		},
+		{
+			Desc:     "missing required key",
+			Source:   mockLister{mockSource{"rate": 0.5}},
+			Expected: []string{"a|b|size"},
+			Errs:     []error{ErrMissingKey},
+		},
+		{
+			Desc:     "invalid values",
+			Source:   mockLister{mockSource{"size": "ten", "rate": 1, "activation": "bogus", "comparisons": []string{"fitness", "bogus"}}},
+			Expected: []string{"a|b|rate", "a|b|size", "a|c|activation", "a|c|comparisons"},
+			Errs:     []error{ErrInvalidValue, ErrInvalidValue, ErrInvalidValue, ErrInvalidValue},
+		},
+		{
+			Desc:     "out of range",
+			Source:   mockLister{mockSource{"size": 0, "rate": "1.5"}},
+			Expected: []string{"a|b|rate", "a|b|size"},
+			Errs:     []error{ErrOutOfRange, ErrOutOfRange},
+		},
+	}
+
+	r := NewRegistry()
+	r.Register(testKeys...)
+	for _, c := range cases {
+		t.Run(c.Desc, func(t *testing.T) {
+			err := r.Validate(c.Source)
+			if len(c.Expected) == 0 {
+				if err != nil {
+					t.Errorf("error not expected: %v", err)
+				}
+				return
+			}
+			errs, ok := err.(Errors)
+			if !ok {
+				t.Fatalf("incorrect error type: %v", err)
+			}
+			if len(errs) != len(c.Expected) {
+				t.Fatalf("incorrect number of errors: expected %d, actual %d: %v", len(c.Expected), len(errs), err)
+			}
+			for i, e := range errs {
+				ke, ok := e.(*KeyError)
+				if !ok {
+					t.Fatalf("incorrect error type at %d: %v", i, e)
+				}
+				if ke.Key != c.Expected[i] {
+					t.Errorf("incorrect key at %d: expected %s, actual %s", i, c.Expected[i], ke.Key)
+				}
+				if !errors.Is(ke, c.Errs[i]) {
+					t.Errorf("incorrect error at %d: expected %v, actual %v", i, c.Errs[i], ke.Err)
+				}
+			}
+			if !errors.Is(err, c.Errs[0]) {
+				t.Errorf("combined error should match %v", c.Errs[0])
+			}
+			if !strings.Contains(err.Error(), c.Expected[0]) {
+				t.Errorf("combined error message should include %s: %v", c.Expected[0], err)
+			}
+		})
+	}
+}
+
+func TestConfigurerRegistry(t *testing.T) {
+
+	// Without a registry, declarations are ignored and everything is valid
+	cfg := &Configurer{Source: mockSource{}}
+	cfg.Declare(testKeys...)
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("error not expected: %v", err)
+	}
+	if x := cfg.Int("a|c|steps"); x != 0 {
+		t.Errorf("default not expected without a registry: actual %d", x)
+	}
+
+	// With a registry, missing keys take their defaults
+	cfg = &Configurer{Source: mockSource{"size": 5}, Registry: NewRegistry()}
+	cfg.Declare(testKeys...)
+	if x := cfg.Int("a|c|steps"); x != 3 {
+		t.Errorf("incorrect default: expected 3, actual %d", x)
+	}
+	if x := cfg.Int("a|b|size"); x != 5 {
+		t.Errorf("incorrect value: expected 5, actual %d", x)
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("error not expected: %v", err)
+	}
+
+	// Later declarations replace earlier ones
+	cfg.Declare(Key{Name: "a|c|steps", Type: Int, Default: 4})
+	if x := cfg.Int("a|c|steps"); x != 4 {
+		t.Errorf("incorrect default after redeclaring: expected 4, actual %d", x)
+	}
+
+	// Keys are listed in order
+	names := make([]string, 0, 5)
+	for _, k := range cfg.Registry.Keys() {
+		names = append(names, k.Name)
+	}
+	expected := []string{"a|b|rate", "a|b|size", "a|c|activation", "a|c|comparisons", "a|c|steps"}
+	if !reflect.DeepEqual(names, expected) {
+		t.Errorf("incorrect keys: expected %v, actual %v", expected, names)
+	}
+
+	// Defaults are returned by the accessors as any other value
+	cfg.Declare(Key{Name: "a|c|activation", Type: Activation, Default: "tanh"})
+	if x := cfg.Activation("a|c|activation"); x != evo.Tanh {
+		t.Errorf("incorrect default activation: expected %v, actual %v", evo.Tanh, x)
+	}
+}
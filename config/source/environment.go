@@ -8,6 +8,9 @@ import (
 // Environment provides a configuration source from the environment variables
 type Environment struct{}
 
+// Name of the source used when recording where values came from
+func (e Environment) Name() string { return "environment" }
+
 // Value returns value of the environment variable which matches the key, if any. If not found, a
 // second try is made using an upper-case version, substituting underscores for hypens.
 func (e Environment) Value(ns []string, k string) interface{} {
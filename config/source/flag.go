@@ -8,6 +8,9 @@ import (
 // Flag provides a configuration source from the command-line flags
 type Flag struct{}
 
+// Name of the source used when recording where values came from
+func (f Flag) Name() string { return "flag" }
+
 // Value returns value of the flag which matches the key, if any. If not found, a second try is
 // made using a lower-case version, substituting hypens for underscores
 func (f Flag) Value(ns []string, k string) interface{} {
@@ -1,6 +1,9 @@
 package source
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
 // Known errors
 var (
@@ -10,6 +13,9 @@ var (
 // Map defines a source based on a standard map of interfaces with a string key.
 type Map map[string]interface{}
 
+// Name of the source used when recording where values came from
+func (m Map) Name() string { return "map" }
+
 // Value returns the value from the map source representd by the namespace and key; otherwise,
 // returns nil.
 func (m Map) Value(ns []string, k string) interface{} {
@@ -59,3 +65,58 @@ func (m Map) SetValue(ns []string, k string, x interface{}) (err error) {
 	m1[k] = x
 	return nil
 }
+
+// Keys returns the keys in the map with their namespaces, such as "neat|selector|elitism"
+func (m Map) Keys() []string {
+	keys := make([]string, 0, len(m))
+	var walk func(prefix string, m1 map[string]interface{})
+	walk = func(prefix string, m1 map[string]interface{}) {
+		for k, x := range m1 {
+			if m2, ok := x.(map[string]interface{}); ok {
+				walk(prefix+k+"|", m2)
+			} else {
+				keys = append(keys, prefix+k)
+			}
+		}
+	}
+	walk("", m)
+	return keys
+}
+
+// Normalize converts a decoded value to the types produced by decoding JSON so that every map
+// source has the same semantics: nested maps are map[string]interface{}, lists are []interface{}
+// and numbers are float64.
+func normalize(x interface{}) interface{} {
+	switch y := x.(type) {
+	case map[string]interface{}:
+		for k, v := range y {
+			y[k] = normalize(v)
+		}
+		return y
+	case map[interface{}]interface{}:
+		z := make(map[string]interface{}, len(y))
+		for k, v := range y {
+			z[fmt.Sprint(k)] = normalize(v)
+		}
+		return z
+	case []map[string]interface{}:
+		z := make([]interface{}, len(y))
+		for i, v := range y {
+			z[i] = normalize(v)
+		}
+		return z
+	case []interface{}:
+		for i, v := range y {
+			y[i] = normalize(v)
+		}
+		return y
+	case int:
+		return float64(y)
+	case int64:
+		return float64(y)
+	case uint64:
+		return float64(y)
+	default:
+		return x
+	}
+}
@@ -15,3 +15,32 @@ func (m Multi) Value(ns []string, k string) interface{} {
 	}
 	return nil
 }
+
+// Trace returns the first non-nil, if any, value from the composite sources along with the name
+// of the source that supplied it
+func (m Multi) Trace(ns []string, k string) (x interface{}, name string) {
+	for _, s := range m {
+		if x, name = config.Lookup(s, ns, k); x != nil {
+			return
+		}
+	}
+	return nil, ""
+}
+
+// Keys returns the keys of the composite sources that can list them. Sources that cannot, such as
+// the environment and flags, are skipped.
+func (m Multi) Keys() []string {
+	var keys []string
+	seen := make(map[string]bool)
+	for _, s := range m {
+		if l, ok := s.(config.Lister); ok {
+			for _, k := range l.Keys() {
+				if !seen[k] {
+					seen[k] = true
+					keys = append(keys, k)
+				}
+			}
+		}
+	}
+	return keys
+}
@@ -0,0 +1,21 @@
+package source
+
+import "github.com/klokare/evo/config"
+
+// Named gives a source a name, such as the file it was loaded from, so that its values can be told
+// apart from those of other sources when recorded
+type Named struct {
+	config.Source
+	Label string
+}
+
+// Name returns the source's label
+func (n Named) Name() string { return n.Label }
+
+// Keys returns the keys of the underlying source if it can list them
+func (n Named) Keys() []string {
+	if l, ok := n.Source.(config.Lister); ok {
+		return l.Keys()
+	}
+	return nil
+}
@@ -0,0 +1,202 @@
+package source
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/klokare/evo"
+	"github.com/klokare/evo/config"
+)
+
+// The same configuration in each format
+var documents = map[string]string{
+	"json": `{
+		"population-size": 150,
+		"neat": {
+			"mutator": {
+				"complexify": {"add-node-probability": 0.03, "hidden-activation": "steepened-sigmoid"},
+				"weight": {"max-weight": 8}
+			},
+			"selector": {"comparison": ["fitness", "novelty"]}
+		},
+		"activations": [1, "tanh"],
+		"solved": true
+	}`,
+	"yaml": `
+population-size: 150
+neat:
+  mutator:
+    complexify:
+      add-node-probability: 0.03
+      hidden-activation: steepened-sigmoid
+    weight:
+      max-weight: 8
+  selector:
+    comparison: [fitness, novelty]
+activations:
+  - 1
+  - tanh
+solved: true
+`,
+	"toml": `
+population-size = 150
+activations = [1, "tanh"]
+solved = true
+
+[neat.mutator.complexify]
+add-node-probability = 0.03
+hidden-activation = "steepened-sigmoid"
+
+[neat.mutator.weight]
+max-weight = 8
+
+[neat.selector]
+comparison = ["fitness", "novelty"]
+`,
+}
+
+func TestSources(t *testing.T) {
+
+	// Create the sources from readers and files
+	dir, err := ioutil.TempDir("", "source")
+	if err != nil {
+		t.Fatalf("error not expected: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	var cases = []struct {
+		Desc   string
+		Format string
+		Create func(string) (Map, error)
+	}{
+		{Desc: "json", Format: "json", Create: func(s string) (Map, error) { return NewJSON(strings.NewReader(s)) }},
+		{Desc: "json file", Format: "json", Create: NewJSONFromFile},
+		{Desc: "yaml", Format: "yaml", Create: func(s string) (Map, error) { return NewYAML(strings.NewReader(s)) }},
+		{Desc: "yaml file", Format: "yaml", Create: NewYAMLFromFile},
+		{Desc: "toml", Format: "toml", Create: func(s string) (Map, error) { return NewTOML(strings.NewReader(s)) }},
+		{Desc: "toml file", Format: "toml", Create: NewTOMLFromFile},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Desc, func(t *testing.T) {
+
+			// Create the source, writing the document to a file for the file variants
+			arg := documents[c.Format]
+			if strings.HasSuffix(c.Desc, "file") {
+				arg = filepath.Join(dir, "config."+c.Format)
+				if err := ioutil.WriteFile(arg, []byte(documents[c.Format]), 0644); err != nil {
+					t.Fatalf("error not expected: %v", err)
+				}
+			}
+			m, err := c.Create(arg)
+			if err != nil {
+				t.Fatalf("error not expected: %v", err)
+			}
+
+			// Every format should give the same values
+			cfg := &config.Configurer{Source: m}
+			if x := cfg.Int("neat|populator|population-size"); x != 150 {
+				t.Errorf("incorrect population size: expected 150, actual %d", x)
+			}
+			if x := cfg.Float64("neat|mutator|complexify|add-node-probability"); x != 0.03 {
+				t.Errorf("incorrect add node probability: expected 0.03, actual %f", x)
+			}
+			if x := cfg.Float64("neat|mutator|weight|max-weight"); x != 8.0 {
+				t.Errorf("incorrect max weight: expected 8, actual %f", x)
+			}
+			if x := cfg.Activation("neat|mutator|complexify|hidden-activation"); x != evo.SteepenedSigmoid {
+				t.Errorf("incorrect hidden activation: expected %v, actual %v", evo.SteepenedSigmoid, x)
+			}
+			if x := cfg.Activation("neat|mutator|weight|hidden-activation"); x != 0 {
+				t.Errorf("hidden activation not expected outside of its namespace: actual %v", x)
+			}
+			if x := cfg.Activations("activations"); !reflect.DeepEqual(x, []evo.Activation{evo.Direct, evo.Tanh}) {
+				t.Errorf("incorrect activations: expected [direct tanh], actual %v", x)
+			}
+			if x := cfg.Comparisons("neat|selector|comparison"); !reflect.DeepEqual(x, []evo.Comparison{evo.ByFitness, evo.ByNovelty}) {
+				t.Errorf("incorrect comparisons: expected [fitness novelty], actual %v", x)
+			}
+			if x := cfg.Bool("neat|experiment|solved"); !x {
+				t.Error("incorrect solved: expected true, actual false")
+			}
+		})
+	}
+
+	// Invalid documents
+	if _, err := NewYAML(strings.NewReader("neat: [")); err == nil {
+		t.Error("expected error not found for invalid yaml")
+	}
+	if _, err := NewTOML(strings.NewReader("[neat")); err == nil {
+		t.Error("expected error not found for invalid toml")
+	}
+	if _, err := NewYAMLFromFile(filepath.Join(dir, "missing.yaml")); err == nil {
+		t.Error("expected error not found for missing file")
+	}
+}
+
+func TestKeys(t *testing.T) {
+	m, err := NewJSON(strings.NewReader(documents["json"]))
+	if err != nil {
+		t.Fatalf("error not expected: %v", err)
+	}
+	expected := []string{
+		"activations",
+		"neat|mutator|complexify|add-node-probability",
+		"neat|mutator|complexify|hidden-activation",
+		"neat|mutator|weight|max-weight",
+		"neat|selector|comparison",
+		"population-size",
+		"solved",
+	}
+
+	// Map lists its keys with their namespaces
+	keys := m.Keys()
+	sort.Strings(keys)
+	if !reflect.DeepEqual(keys, expected) {
+		t.Errorf("incorrect keys:\nexpected %v\nactual   %v", expected, keys)
+	}
+
+	// Multi lists the keys of the sources that can list them, once each
+	keys = Multi{Environment{}, m, Map{"solved": false, "other": 1.0}}.Keys()
+	sort.Strings(keys)
+	expected = append(expected, "other")
+	sort.Strings(expected)
+	if !reflect.DeepEqual(keys, expected) {
+		t.Errorf("incorrect keys:\nexpected %v\nactual   %v", expected, keys)
+	}
+}
+
+func TestTrace(t *testing.T) {
+	src := Multi{
+		Map{"neat": map[string]interface{}{"elitism": 0.1}},
+		Named{Source: Map{"elitism": 0.2, "survival-rate": 0.3}, Label: "config.json"},
+	}
+	var cases = []struct {
+		Desc   string
+		Key    string
+		Value  interface{}
+		Source string
+	}{
+		{Desc: "first source", Key: "neat|selector|elitism", Value: 0.1, Source: "map"},
+		{Desc: "named source", Key: "neat|selector|survival-rate", Value: 0.3, Source: "config.json"},
+		{Desc: "not found", Key: "neat|selector|population-size", Value: nil, Source: ""},
+	}
+	for _, c := range cases {
+		t.Run(c.Desc, func(t *testing.T) {
+			cfg := &config.Configurer{Source: src, Recorder: config.NewRecorder()}
+			cfg.Float64(c.Key)
+			recs := cfg.Recorder.Records()
+			if len(recs) != 1 {
+				t.Fatalf("incorrect number of records: expected 1, actual %d", len(recs))
+			}
+			if recs[0].Value != c.Value || recs[0].Source != c.Source {
+				t.Errorf("incorrect record: expected %v from %q, actual %v from %q", c.Value, c.Source, recs[0].Value, recs[0].Source)
+			}
+		})
+	}
+}
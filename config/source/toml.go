@@ -0,0 +1,51 @@
+package source
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+
+	"github.com/BurntSushi/toml"
+)
+
+// NewTOML returns a new map source based on the TOML data stored in the reader. Tables become
+// namespaces so that, for example, the key "neat|mutator|complexify|add-node-probability" may be
+// written as
+//
+//	[neat.mutator.complexify]
+//	add-node-probability = 0.03
+func NewTOML(r io.Reader) (m Map, err error) {
+
+	// Decode the data in the reader
+	var b []byte
+	if b, err = ioutil.ReadAll(r); err != nil {
+		return
+	}
+	var x map[string]interface{}
+	if err = toml.Unmarshal(b, &x); err != nil {
+		return
+	}
+	m = normalize(x).(map[string]interface{})
+	return
+}
+
+// NewTOMLFromFile is a convenience function that creates a new TOML source from the file specified
+// by filename.
+func NewTOMLFromFile(filename string) (m Map, err error) {
+
+	// Open the file
+	var f *os.File
+	if f, err = os.Open(filename); err != nil {
+		return
+	}
+
+	// Decode the data
+	if m, err = NewTOML(f); err != nil {
+		f.Close() // ignore error as it would overwrite the decoding one
+		return
+	}
+
+	// Close the file and return
+	err = f.Close()
+	return
+}
@@ -0,0 +1,53 @@
+package source
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// NewYAML returns a new map source based on the YAML data stored in the reader. Nested mappings
+// become namespaces so that, for example, the key "neat|mutator|complexify|add-node-probability"
+// may be written as
+//
+//	neat:
+//	  mutator:
+//	    complexify:
+//	      add-node-probability: 0.03
+func NewYAML(r io.Reader) (m Map, err error) {
+
+	// Decode the data in the reader
+	var b []byte
+	if b, err = ioutil.ReadAll(r); err != nil {
+		return
+	}
+	var x map[string]interface{}
+	if err = yaml.Unmarshal(b, &x); err != nil {
+		return
+	}
+	m = normalize(x).(map[string]interface{})
+	return
+}
+
+// NewYAMLFromFile is a convenience function that creates a new YAML source from the file specified
+// by filename.
+func NewYAMLFromFile(filename string) (m Map, err error) {
+
+	// Open the file
+	var f *os.File
+	if f, err = os.Open(filename); err != nil {
+		return
+	}
+
+	// Decode the data
+	if m, err = NewYAML(f); err != nil {
+		f.Close() // ignore error as it would overwrite the decoding one
+		return
+	}
+
+	// Close the file and return
+	err = f.Close()
+	return
+}
@@ -8,6 +8,24 @@ type Conn struct {
 	Weight         float64  // The connection weight
 	Enabled        bool     // True if this connection should be used to create a synapse
 	Locked         bool     // Locked connections cannot be removed or split
+	Hebbian                 // The rule by which a plastic network adapts the weight
+}
+
+// Hebbian describes the generalised (ABCD) Hebbian rule by which a plastic network adapts a
+// connection's weight after each activation. The change in weight is
+//
+//	Rate * (A*pre*post + B*pre + C*post + D)
+//
+// where pre and post are the values of the source and target neurons. A zero rate leaves the weight
+// fixed.
+type Hebbian struct {
+	A, B, C, D float64 // Coefficients of the correlation, presynaptic, postsynaptic and constant terms
+	Rate       float64 // The learning rate
+}
+
+// Delta returns the change in weight for the values of the source and target neurons
+func (h Hebbian) Delta(pre, post float64) float64 {
+	return h.Rate * (h.A*pre*post + h.B*pre + h.C*post + h.D)
 }
 
 // String returns a description of the connection
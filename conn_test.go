@@ -1,6 +1,7 @@
 package evo
 
 import (
+	"math"
 	"strings"
 	"testing"
 )
@@ -108,3 +109,23 @@ func TestConnCompare(t *testing.T) {
 		})
 	}
 }
+
+func TestHebbianDelta(t *testing.T) {
+	var cases = []struct {
+		Desc      string
+		Hebbian   Hebbian
+		Pre, Post float64
+		Expected  float64
+	}{
+		{Desc: "zero rate", Hebbian: Hebbian{A: 1.0, B: 1.0, C: 1.0, D: 1.0}, Pre: 0.5, Post: 0.5, Expected: 0.0},
+		{Desc: "plain hebbian", Hebbian: Hebbian{A: 1.0, Rate: 0.1}, Pre: 0.5, Post: 0.8, Expected: 0.04},
+		{Desc: "all terms", Hebbian: Hebbian{A: 1.0, B: -0.5, C: 0.25, D: 0.1, Rate: 2.0}, Pre: 0.5, Post: 0.8, Expected: 2.0 * (0.4 - 0.25 + 0.2 + 0.1)},
+	}
+	for _, c := range cases {
+		t.Run(c.Desc, func(t *testing.T) {
+			if actual := c.Hebbian.Delta(c.Pre, c.Post); math.Abs(actual-c.Expected) > 1e-12 {
+				t.Errorf("incorrect delta: expected %f, actual %f", c.Expected, actual)
+			}
+		})
+	}
+}
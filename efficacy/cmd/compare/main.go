@@ -0,0 +1,314 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"html/template"
+	"io"
+	"log"
+	"math"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/klokare/evo/efficacy"
+)
+
+func main() {
+
+	// Create and parse the flags
+	var (
+		series1    = flag.String("series1", "", "name to use for series 1")
+		series2    = flag.String("series2", "", "name to use for series 2")
+		method     = flag.String("method", "best", "method used for aggregation of the population fields")
+		solved     = flag.Bool("solved", false, "compare the fields of solved cases only")
+		resamples  = flag.Int("resamples", 10000, "number of bootstrap resamples for the confidence intervals")
+		confidence = flag.Float64("confidence", 0.95, "confidence level of the intervals and tests")
+		format     = flag.String("format", "text", "format of the report: text, markdown or html")
+		output     = flag.String("output", "", "filename for the report or standard output if empty")
+		seed       = flag.Int64("seed", 0, "seed for the bootstrap resamples; zero for unseeded")
+	)
+	flag.Parse()
+	args := flag.Args()
+	if len(args) != 2 {
+		log.Fatal("you must specify two result files")
+	}
+	if *confidence <= 0.0 || *confidence >= 1.0 {
+		log.Fatal("confidence must be between 0 and 1")
+	}
+
+	// Validate the method and format
+	var err error
+	var m efficacy.Method
+	if m, err = validateMethod(*method); err != nil {
+		log.Fatal(err)
+	}
+
+	var write func(io.Writer, report) error
+	switch strings.ToLower(*format) {
+	case "text":
+		write = writeText
+	case "markdown", "md":
+		write = writeMarkdown
+	case "html":
+		write = writeHTML
+	default:
+		log.Fatalf("unknown report format %s", *format)
+	}
+
+	// Load the data
+	var a, b series
+	if a, err = makeSeries(*series1, args[0]); err != nil {
+		log.Fatal(err)
+	}
+	if b, err = makeSeries(*series2, args[1]); err != nil {
+		log.Fatal(err)
+	}
+
+	// Compare the series
+	if *seed == 0 {
+		*seed = time.Now().UnixNano()
+	}
+	rep := makeReport(rand.New(rand.NewSource(*seed)), a, b, m, *solved, *resamples, *confidence)
+
+	// Write the report
+	var w io.Writer = os.Stdout
+	if *output != "" {
+		var f *os.File
+		if f, err = os.Create(*output); err != nil {
+			log.Fatal(err)
+		}
+		defer f.Close()
+		w = f
+	}
+	if err = write(w, rep); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func validateMethod(name string) (efficacy.Method, error) {
+	switch strings.ToLower(name) {
+	case "min":
+		return efficacy.Min, nil
+	case "max":
+		return efficacy.Max, nil
+	case "mean":
+		return efficacy.Mean, nil
+	case "median":
+		return efficacy.Median, nil
+	case "best":
+		return efficacy.Best, nil
+	default:
+		return 0, fmt.Errorf("unknown method name %s", name)
+	}
+}
+
+func label(f efficacy.Field, m efficacy.Method) string {
+	switch f {
+	case efficacy.Generations, efficacy.Evaluations, efficacy.Seconds:
+		return f.String()
+	default:
+		return strings.ToLower(m.String()) + " " + f.String()
+	}
+}
+
+// A series is the named set of samples from a result file
+type series struct {
+	name    string
+	samples []efficacy.Sample
+}
+
+func makeSeries(name, filename string) (s series, err error) {
+
+	// Load the data from the file
+	var f *os.File
+	if f, err = os.Open(filename); err != nil {
+		return
+	}
+	defer f.Close()
+	if s.samples, err = efficacy.ReadSamples(f); err != nil {
+		return
+	}
+	if len(s.samples) == 0 {
+		err = fmt.Errorf("no samples found in %s", filename)
+		return
+	}
+
+	// Set the name
+	s.name = name
+	if s.name == "" {
+		s.name = strings.TrimSuffix(filepath.Base(filename), filepath.Ext(filename))
+	}
+	return
+}
+
+// A report holds the values presented in each format
+type report struct {
+	A, B       string // Names of the series
+	RunsA      int
+	RunsB      int
+	SolvedA    efficacy.Interval
+	SolvedB    efficacy.Interval
+	Rows       []row
+	Solved     bool // Fields compare solved cases only
+	Confidence float64
+	Resamples  int
+}
+
+type row struct {
+	Label       string
+	A, B        efficacy.Interval
+	U, P        float64
+	Significant bool
+}
+
+func makeReport(rng *rand.Rand, a, b series, m efficacy.Method, solved bool, resamples int, confidence float64) report {
+	rep := report{
+		A:          a.name,
+		B:          b.name,
+		RunsA:      len(a.samples),
+		RunsB:      len(b.samples),
+		SolvedA:    efficacy.Proportion(count(a.samples), len(a.samples), confidence),
+		SolvedB:    efficacy.Proportion(count(b.samples), len(b.samples), confidence),
+		Solved:     solved,
+		Confidence: confidence,
+		Resamples:  resamples,
+	}
+	sa, sb := a.samples, b.samples
+	if solved {
+		sa, sb = filter(sa), filter(sb)
+	}
+	for _, c := range efficacy.Compare(rng, sa, sb, m, resamples, confidence) {
+		rep.Rows = append(rep.Rows, row{
+			Label:       label(c.Field, c.Method),
+			A:           c.A,
+			B:           c.B,
+			U:           c.U,
+			P:           c.P,
+			Significant: c.Significant(1.0 - confidence),
+		})
+	}
+	return rep
+}
+
+func count(ss []efficacy.Sample) (n int) {
+	for _, s := range ss {
+		if s.Solved {
+			n++
+		}
+	}
+	return
+}
+
+func filter(ss []efficacy.Sample) []efficacy.Sample {
+	x := make([]efficacy.Sample, 0, len(ss))
+	for _, s := range ss {
+		if s.Solved {
+			x = append(x, s)
+		}
+	}
+	return x
+}
+
+func percent(iv efficacy.Interval) string {
+	if math.IsNaN(iv.Estimate) {
+		return "n/a"
+	}
+	return fmt.Sprintf("%.1f%% [%.1f%%, %.1f%%]", iv.Estimate*100.0, iv.Lower*100.0, iv.Upper*100.0)
+}
+
+func median(iv efficacy.Interval) string {
+	if math.IsNaN(iv.Estimate) {
+		return "n/a"
+	}
+	return fmt.Sprintf("%.4g [%.4g, %.4g]", iv.Estimate, iv.Lower, iv.Upper)
+}
+
+func pvalue(p float64, sig bool) string {
+	if math.IsNaN(p) {
+		return "n/a"
+	}
+	if sig {
+		return fmt.Sprintf("%.4f *", p)
+	}
+	return fmt.Sprintf("%.4f", p)
+}
+
+func (r report) note(mark string) string {
+	s := fmt.Sprintf("Medians with %g%% bootstrap confidence intervals from %d resamples. ", r.Confidence*100.0, r.Resamples)
+	s += fmt.Sprintf("Mann-Whitney U of %s against %s; %s marks p < %.3g.", r.A, r.B, mark, 1.0-r.Confidence)
+	if r.Solved {
+		s += " Fields compare solved cases only."
+	}
+	return s
+}
+
+func writeText(w io.Writer, r report) error {
+	fmt.Fprintf(w, "Comparison of %s (%d runs) and %s (%d runs)\n\n", r.A, r.RunsA, r.B, r.RunsB)
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(tw, "field\t%s\t%s\tU\tp\n", r.A, r.B)
+	fmt.Fprintf(tw, "success rate\t%s\t%s\t\t\n", percent(r.SolvedA), percent(r.SolvedB))
+	for _, x := range r.Rows {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%g\t%s\n", x.Label, median(x.A), median(x.B), x.U, pvalue(x.P, x.Significant))
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintf(w, "\n%s\n", r.note("*"))
+	return err
+}
+
+func writeMarkdown(w io.Writer, r report) error {
+	fmt.Fprintf(w, "## Comparison of %s (%d runs) and %s (%d runs)\n\n", r.A, r.RunsA, r.B, r.RunsB)
+	fmt.Fprintf(w, "| field | %s | %s | U | p |\n", r.A, r.B)
+	fmt.Fprintf(w, "|---|---|---|---:|---:|\n")
+	fmt.Fprintf(w, "| success rate | %s | %s | | |\n", percent(r.SolvedA), percent(r.SolvedB))
+	for _, x := range r.Rows {
+		p := pvalue(x.P, false)
+		if x.Significant {
+			p = "**" + p + "**"
+		}
+		fmt.Fprintf(w, "| %s | %s | %s | %g | %s |\n", x.Label, median(x.A), median(x.B), x.U, p)
+	}
+	_, err := fmt.Fprintf(w, "\n%s\n", r.note("bold"))
+	return err
+}
+
+var page = template.Must(template.New("report").Funcs(template.FuncMap{
+	"percent": percent,
+	"median":  median,
+	"pvalue":  pvalue,
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Comparison of {{.A}} and {{.B}}</title>
+<style>
+body { font-family: sans-serif; }
+table { border-collapse: collapse; }
+th, td { border: 1px solid #ccc; padding: 4px 8px; text-align: right; }
+th:first-child, td:first-child { text-align: left; }
+.significant { font-weight: bold; background: #ffd; }
+</style>
+</head>
+<body>
+<h2>Comparison of {{.A}} ({{.RunsA}} runs) and {{.B}} ({{.RunsB}} runs)</h2>
+<table>
+<tr><th>field</th><th>{{.A}}</th><th>{{.B}}</th><th>U</th><th>p</th></tr>
+<tr><td>success rate</td><td>{{percent .SolvedA}}</td><td>{{percent .SolvedB}}</td><td></td><td></td></tr>
+{{range .Rows}}<tr{{if .Significant}} class="significant"{{end}}><td>{{.Label}}</td><td>{{median .A}}</td><td>{{median .B}}</td><td>{{.U}}</td><td>{{pvalue .P false}}</td></tr>
+{{end}}</table>
+<p>{{.Note}}</p>
+</body>
+</html>
+`))
+
+func writeHTML(w io.Writer, r report) error {
+	return page.Execute(w, struct {
+		report
+		Note string
+	}{r, r.note("highlighting")})
+}
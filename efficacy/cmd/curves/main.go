@@ -0,0 +1,282 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"image/color"
+	"log"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/klokare/evo/efficacy"
+	"gonum.org/v1/gonum/stat/distuv"
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/plotter"
+	"gonum.org/v1/plot/plotutil"
+	"gonum.org/v1/plot/vg"
+	"gonum.org/v1/plot/vg/draw"
+)
+
+func main() {
+
+	// Create and parse the flags
+	var (
+		names      = flag.String("names", "", "comma-separated names to use for the series")
+		field      = flag.String("field", "fitness", "data field to report")
+		method     = flag.String("method", "best", "method used for aggregation")
+		confidence = flag.Float64("confidence", 0.95, "confidence level of the band around the mean")
+		output     = flag.String("output", "curves.png", "filename for output chart")
+		height     = flag.Float64("height", 9, "chart height in centimeters")
+		width      = flag.Float64("width", 15, "chart weight in centimeters")
+	)
+	flag.Parse()
+	args := flag.Args()
+	if len(args) == 0 {
+		log.Fatal("you must specify at least one time series file")
+	}
+	if *confidence <= 0.0 || *confidence >= 1.0 {
+		log.Fatal("confidence must be between 0 and 1")
+	}
+
+	// Validate the field and method
+	var err error
+	var f efficacy.Field
+	if f, err = validateField(*field); err != nil {
+		log.Fatal(err)
+	}
+
+	var m efficacy.Method
+	if m, err = validateMethod(*method); err != nil {
+		log.Fatal(err)
+	}
+
+	// Load the data, one series per file
+	var labels []string
+	if *names != "" {
+		labels = strings.Split(*names, ",")
+	}
+	series := make([]curve, len(args))
+	for i, filename := range args {
+		var name string
+		if i < len(labels) {
+			name = labels[i]
+		}
+		if series[i], err = makeCurve(name, filename, f, m, *confidence); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	// Make the chart
+	if err := makeChart(*output, ylabel(f, m), *confidence, series, vg.Length(*height), vg.Length(*width)); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func validateField(name string) (efficacy.Field, error) {
+	switch strings.ToLower(name) {
+	case "evaluations":
+		return efficacy.Evaluations, nil
+	case "seconds":
+		return efficacy.Seconds, nil
+	case "fitness":
+		return efficacy.Fitness, nil
+	case "novelty":
+		return efficacy.Novelty, nil
+	case "encoded":
+		return efficacy.Encoded, nil
+	case "encoded-nodes":
+		return efficacy.EncodedNodes, nil
+	case "encoded-conns":
+		return efficacy.EncodedConns, nil
+	case "decoded":
+		return efficacy.Decoded, nil
+	case "decoded-nodes":
+		return efficacy.DecodedNodes, nil
+	case "decoded-conns":
+		return efficacy.DecodedConns, nil
+	case "species":
+		return efficacy.SpeciesCount, nil
+	case "compatibility-threshold":
+		return efficacy.CompatibilityThreshold, nil
+	default:
+		return 0, fmt.Errorf("unknown field name %s", name)
+	}
+}
+
+func validateMethod(name string) (efficacy.Method, error) {
+	switch strings.ToLower(name) {
+	case "min":
+		return efficacy.Min, nil
+	case "max":
+		return efficacy.Max, nil
+	case "mean":
+		return efficacy.Mean, nil
+	case "median":
+		return efficacy.Median, nil
+	case "best":
+		return efficacy.Best, nil
+	default:
+		return 0, fmt.Errorf("unknown method name %s", name)
+	}
+}
+
+func ylabel(f efficacy.Field, m efficacy.Method) string {
+	switch f {
+	case efficacy.Evaluations, efficacy.Seconds, efficacy.SpeciesCount, efficacy.CompatibilityThreshold:
+		return f.String()
+	default:
+		return m.String() + " " + f.String()
+	}
+}
+
+// A curve is the mean, across runs, of a value at each generation along with the bounds of its
+// confidence interval
+type curve struct {
+	name               string
+	mean, lower, upper plotter.XYs
+}
+
+func makeCurve(name, filename string, fld efficacy.Field, met efficacy.Method, confidence float64) (c curve, err error) {
+
+	// Load the points from the file. The format is determined by the file's extension.
+	format := efficacy.JSONLines
+	if strings.ToLower(filepath.Ext(filename)) == ".csv" {
+		format = efficacy.CSV
+	}
+	var f *os.File
+	if f, err = os.Open(filename); err != nil {
+		return
+	}
+	defer f.Close()
+
+	var ps []efficacy.Point
+	if ps, err = efficacy.ReadPoints(f, format); err != nil {
+		return
+	}
+	if len(ps) == 0 {
+		err = fmt.Errorf("no points found in %s", filename)
+		return
+	}
+
+	// Collect the values of each run by generation
+	runs := make(map[int]map[int]float64, 100)
+	last := 0
+	for _, p := range ps {
+		if _, ok := runs[p.BatchNumber]; !ok {
+			runs[p.BatchNumber] = make(map[int]float64, 100)
+		}
+		runs[p.BatchNumber][p.Generation] = p.Value(fld, met)
+		if p.Generation > last {
+			last = p.Generation
+		}
+	}
+
+	// Line up the runs. A run that ends early, usually because it was solved, carries its final
+	// value forward so that the mean is not taken over only the runs still going.
+	values := make([][]float64, last+1)
+	for _, run := range runs {
+		gens := make([]int, 0, len(run))
+		for g := range run {
+			gens = append(gens, g)
+		}
+		sort.Ints(gens)
+		x, j := math.NaN(), 0
+		for g := gens[0]; g <= last; g++ {
+			if j < len(gens) && gens[j] == g {
+				x = run[g]
+				j++
+			}
+			values[g] = append(values[g], x)
+		}
+	}
+
+	// Calculate the mean and confidence interval for each generation
+	for g, xs := range values {
+		if len(xs) == 0 {
+			continue
+		}
+		mean, margin := interval(xs, confidence)
+		c.mean = append(c.mean, plotter.XY{X: float64(g), Y: mean})
+		c.lower = append(c.lower, plotter.XY{X: float64(g), Y: mean - margin})
+		c.upper = append(c.upper, plotter.XY{X: float64(g), Y: mean + margin})
+	}
+
+	// Set the name
+	c.name = name
+	if c.name == "" {
+		c.name = strings.TrimSuffix(filepath.Base(filename), filepath.Ext(filename))
+	}
+	c.name = fmt.Sprintf("%s (%d runs)", c.name, len(runs))
+	return
+}
+
+// Returns the mean and the margin of error at the confidence level using Student's t distribution
+func interval(xs []float64, confidence float64) (mean, margin float64) {
+	n := float64(len(xs))
+	for _, x := range xs {
+		mean += x
+	}
+	mean /= n
+	if len(xs) < 2 {
+		return
+	}
+	var ss float64
+	for _, x := range xs {
+		ss += (x - mean) * (x - mean)
+	}
+	se := math.Sqrt(ss/(n-1.0)) / math.Sqrt(n)
+	t := distuv.StudentsT{Mu: 0.0, Sigma: 1.0, Nu: n - 1.0}.Quantile(1.0 - (1.0-confidence)/2.0)
+	margin = t * se
+	return
+}
+
+func makeChart(filename, yname string, confidence float64, series []curve, h, w vg.Length) error {
+
+	// Create the plot
+	p, err := plot.New()
+	if err != nil {
+		return err
+	}
+	p.Title.Text = fmt.Sprintf("Convergence (mean with %g%% confidence band)", confidence*100.0)
+	p.X.Label.Text = "generation"
+	p.Y.Label.Text = yname
+
+	// Add each series as a band with the mean drawn through it
+	for i, c := range series {
+		clr := plotutil.Color(i)
+		r, g, b, _ := clr.RGBA()
+
+		// The confidence band traces the upper bound forward and the lower bound back
+		pts := make(plotter.XYs, 0, len(c.upper)+len(c.lower))
+		pts = append(pts, c.upper...)
+		for j := len(c.lower) - 1; j >= 0; j-- {
+			pts = append(pts, c.lower[j])
+		}
+		band, err := plotter.NewPolygon(pts)
+		if err != nil {
+			return err
+		}
+		band.Color = color.NRGBA{R: uint8(r >> 8), G: uint8(g >> 8), B: uint8(b >> 8), A: 64}
+		band.LineStyle.Width = 0
+		p.Add(band)
+
+		line, err := plotter.NewLine(c.mean)
+		if err != nil {
+			return err
+		}
+		line.LineStyle = draw.LineStyle{Color: clr, Width: vg.Points(1), Dashes: []vg.Length{}, DashOffs: 0}
+		p.Add(line)
+		p.Legend.Add(c.name, line)
+	}
+
+	// Add legend
+	p.Legend.Top = true
+	p.Legend.Left = true
+	p.Legend.TextStyle.Font.Size = vg.Points(10)
+
+	// Save the file
+	return p.Save(w*vg.Centimeter, h*vg.Centimeter, filename)
+}
@@ -0,0 +1,175 @@
+package efficacy
+
+import (
+	"encoding/json"
+	"io"
+	"math"
+	"sort"
+
+	"github.com/klokare/evo"
+	"github.com/klokare/evo/internal/float"
+)
+
+// ReadSamples reads the samples written by a sampler
+func ReadSamples(rd io.Reader) (ss []Sample, err error) {
+	dec := json.NewDecoder(rd)
+	for {
+		var s Sample
+		if err = dec.Decode(&s); err == io.EOF {
+			return ss, nil
+		} else if err != nil {
+			return nil, err
+		}
+		ss = append(ss, s)
+	}
+}
+
+// Interval is an estimate with the bounds of its confidence interval
+type Interval struct {
+	Estimate     float64
+	Lower, Upper float64
+}
+
+// Bootstrap returns the median of the values with its confidence interval, estimated from the
+// percentiles of the medians of the given number of resamples
+func Bootstrap(rng evo.Random, xs []float64, resamples int, confidence float64) Interval {
+	if len(xs) == 0 {
+		return Interval{Estimate: math.NaN(), Lower: math.NaN(), Upper: math.NaN()}
+	}
+	est := float.Median(xs)
+	if len(xs) == 1 || resamples < 1 {
+		return Interval{Estimate: est, Lower: est, Upper: est}
+	}
+	ms := make([]float64, resamples)
+	r := make([]float64, len(xs))
+	for i := range ms {
+		for j := range r {
+			r[j] = xs[rng.Intn(len(xs))]
+		}
+		ms[i] = float.Median(r)
+	}
+	sort.Float64s(ms)
+	alpha := (1.0 - confidence) / 2.0
+	return Interval{
+		Estimate: est,
+		Lower:    percentile(ms, alpha),
+		Upper:    percentile(ms, 1.0-alpha),
+	}
+}
+
+// Proportion returns the proportion of successes with its Wilson score interval at the confidence
+// level
+func Proportion(successes, n int, confidence float64) Interval {
+	if n == 0 {
+		return Interval{Estimate: math.NaN(), Lower: math.NaN(), Upper: math.NaN()}
+	}
+	p := float64(successes) / float64(n)
+	z := math.Sqrt2 * math.Erfinv(confidence)
+	d := 1.0 + z*z/float64(n)
+	c := (p + z*z/(2.0*float64(n))) / d
+	m := z * math.Sqrt(p*(1.0-p)/float64(n)+z*z/(4.0*float64(n*n))) / d
+	return Interval{Estimate: p, Lower: math.Max(0.0, c-m), Upper: math.Min(1.0, c+m)}
+}
+
+// MannWhitney returns the U statistic of the first set of values against the second and the
+// two-sided p-value of the test that neither tends to be larger than the other. The p-value uses
+// the normal approximation with corrections for ties and continuity so the sets should have more
+// than a handful of values each.
+func MannWhitney(a, b []float64) (u, p float64) {
+	n1, n2 := float64(len(a)), float64(len(b))
+	if n1 == 0 || n2 == 0 {
+		return 0.0, math.NaN()
+	}
+
+	// Rank the combined values, giving ties the average of their ranks
+	type item struct {
+		x     float64
+		first bool
+	}
+	xs := make([]item, 0, len(a)+len(b))
+	for _, x := range a {
+		xs = append(xs, item{x: x, first: true})
+	}
+	for _, x := range b {
+		xs = append(xs, item{x: x})
+	}
+	sort.Slice(xs, func(i, j int) bool { return xs[i].x < xs[j].x })
+
+	var r1, ties float64
+	for i := 0; i < len(xs); {
+		j := i + 1
+		for j < len(xs) && xs[j].x == xs[i].x {
+			j++
+		}
+		rank := float64(i+j+1) / 2.0 // average of ranks i+1 through j
+		for k := i; k < j; k++ {
+			if xs[k].first {
+				r1 += rank
+			}
+		}
+		t := float64(j - i)
+		ties += t*t*t - t
+		i = j
+	}
+
+	// Calculate the statistic and its approximate significance
+	u = r1 - n1*(n1+1.0)/2.0
+	n := n1 + n2
+	mu := n1 * n2 / 2.0
+	sigma := math.Sqrt(n1 * n2 / 12.0 * ((n + 1.0) - ties/(n*(n-1.0))))
+	if sigma == 0.0 {
+		return u, 1.0 // all values are the same
+	}
+	z := math.Max(math.Abs(u-mu)-0.5, 0.0) / sigma
+	return u, math.Erfc(z / math.Sqrt2)
+}
+
+// Returns the value at the percentile of the sorted values, interpolating between neighbours
+func percentile(sorted []float64, q float64) float64 {
+	h := q * float64(len(sorted)-1)
+	i := int(math.Floor(h))
+	if i >= len(sorted)-1 {
+		return sorted[len(sorted)-1]
+	}
+	return sorted[i] + (h-float64(i))*(sorted[i+1]-sorted[i])
+}
+
+// Comparison of a field between two sets of samples
+type Comparison struct {
+	Field  Field
+	Method Method
+	A, B   Interval // Medians of each set with their confidence intervals
+	U, P   float64  // Mann-Whitney U statistic of the first set and the p-value of the test
+}
+
+// Significant returns true if the difference between the sets is significant at the level
+func (c Comparison) Significant(alpha float64) bool { return c.P < alpha }
+
+// Compare the two sets of samples. The generations, evaluations and seconds are compared along with
+// the method's value of each summarised field. Samples which do not carry a field are left out of
+// its comparison.
+func Compare(rng evo.Random, a, b []Sample, m Method, resamples int, confidence float64) []Comparison {
+	fields := append([]Field{Generations, Evaluations, Seconds}, summaryFields...)
+	cs := make([]Comparison, len(fields))
+	for i, f := range fields {
+		xa, xb := make([]float64, 0, len(a)), make([]float64, 0, len(b))
+		for _, s := range a {
+			if x := s.Value(f, m); !math.IsNaN(x) {
+				xa = append(xa, x)
+			}
+		}
+		for _, s := range b {
+			if x := s.Value(f, m); !math.IsNaN(x) {
+				xb = append(xb, x)
+			}
+		}
+		cs[i] = Comparison{
+			Field:  f,
+			Method: m,
+			A:      Bootstrap(rng, xa, resamples, confidence),
+			B:      Bootstrap(rng, xb, resamples, confidence),
+		}
+		cs[i].U, cs[i].P = MannWhitney(xa, xb)
+	}
+	return cs
+}
@@ -0,0 +1,140 @@
+package efficacy
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestMannWhitney(t *testing.T) {
+	var cases = []struct {
+		Desc string
+		A, B []float64
+		U, P float64
+	}{
+		{Desc: "separated", A: []float64{1, 2, 3, 4, 5}, B: []float64{6, 7, 8, 9, 10}, U: 0.0, P: 0.012186},
+		{Desc: "reversed", A: []float64{6, 7, 8, 9, 10}, B: []float64{1, 2, 3, 4, 5}, U: 25.0, P: 0.012186},
+		{Desc: "interleaved", A: []float64{1, 3, 5, 7, 9}, B: []float64{2, 4, 6, 8, 10}, U: 10.0, P: 0.676103},
+		{Desc: "ties", A: []float64{1, 2, 2, 3}, B: []float64{2, 3, 3, 4}, U: 3.0, P: 0.172034},
+		{Desc: "identical", A: []float64{1, 1, 1}, B: []float64{1, 1}, U: 3.0, P: 1.0},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Desc, func(t *testing.T) {
+			u, p := MannWhitney(c.A, c.B)
+			if u != c.U {
+				t.Errorf("incorrect U: expected %f, actual %f", c.U, u)
+			}
+			if math.Abs(p-c.P) > 1e-6 {
+				t.Errorf("incorrect p-value: expected %f, actual %f", c.P, p)
+			}
+		})
+	}
+}
+
+func TestBootstrap(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+
+	// Constant values have no spread
+	iv := Bootstrap(rng, []float64{2, 2, 2, 2}, 100, 0.95)
+	if iv.Estimate != 2.0 || iv.Lower != 2.0 || iv.Upper != 2.0 {
+		t.Errorf("incorrect interval for constant values: %+v", iv)
+	}
+
+	// The interval surrounds the median
+	xs := make([]float64, 101)
+	for i := range xs {
+		xs[i] = float64(i)
+	}
+	iv = Bootstrap(rng, xs, 1000, 0.95)
+	if iv.Estimate != 50.0 {
+		t.Errorf("incorrect median: expected 50, actual %f", iv.Estimate)
+	}
+	if iv.Lower >= iv.Estimate || iv.Upper <= iv.Estimate || iv.Lower < 30.0 || iv.Upper > 70.0 {
+		t.Errorf("incorrect interval: %+v", iv)
+	}
+
+	// No values
+	if iv = Bootstrap(rng, nil, 100, 0.95); !math.IsNaN(iv.Estimate) {
+		t.Errorf("expected NaN estimate for no values, actual %f", iv.Estimate)
+	}
+}
+
+func TestProportion(t *testing.T) {
+	var cases = []struct {
+		Desc         string
+		Successes, N int
+		Expected     Interval
+	}{
+		{Desc: "half", Successes: 5, N: 10, Expected: Interval{Estimate: 0.5, Lower: 0.236593, Upper: 0.763407}},
+		{Desc: "none", Successes: 0, N: 10, Expected: Interval{Estimate: 0.0, Lower: 0.0, Upper: 0.277533}},
+		{Desc: "all", Successes: 10, N: 10, Expected: Interval{Estimate: 1.0, Lower: 0.722467, Upper: 1.0}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Desc, func(t *testing.T) {
+			iv := Proportion(c.Successes, c.N, 0.95)
+			if math.Abs(iv.Estimate-c.Expected.Estimate) > 1e-6 || math.Abs(iv.Lower-c.Expected.Lower) > 1e-6 || math.Abs(iv.Upper-c.Expected.Upper) > 1e-6 {
+				t.Errorf("incorrect interval: expected %+v, actual %+v", c.Expected, iv)
+			}
+		})
+	}
+}
+
+func TestCompare(t *testing.T) {
+
+	// Write and read the samples
+	var a, b []Sample
+	for i := 0; i < 20; i++ {
+		a = append(a, Sample{BatchNumber: i, Generations: 10 + i, Evaluations: 100 * (10 + i), Values: map[Field]map[Method]float64{Fitness: {Best: float64(i)}}})
+		b = append(b, Sample{BatchNumber: i, Generations: 40 + i, Evaluations: 100 * (40 + i), Values: map[Field]map[Method]float64{Fitness: {Best: float64(i)}}})
+	}
+	buf := new(bytes.Buffer)
+	enc := json.NewEncoder(buf)
+	for _, s := range a {
+		if err := enc.Encode(s); err != nil {
+			t.Fatalf("error not expected: %v", err)
+		}
+	}
+	ra, err := ReadSamples(buf)
+	if err != nil {
+		t.Fatalf("error not expected: %v", err)
+	}
+	if len(ra) != len(a) {
+		t.Fatalf("incorrect number of samples: expected %d, actual %d", len(a), len(ra))
+	}
+
+	// Compare the sets
+	cs := Compare(rand.New(rand.NewSource(1)), ra, b, Best, 200, 0.95)
+	if len(cs) != 3+len(summaryFields) {
+		t.Fatalf("incorrect number of comparisons: expected %d, actual %d", 3+len(summaryFields), len(cs))
+	}
+	for _, c := range cs {
+		switch c.Field {
+		case Generations, Evaluations:
+			if !c.Significant(0.05) {
+				t.Errorf("%v should differ significantly: p=%f", c.Field, c.P)
+			}
+			if c.A.Estimate >= c.B.Estimate {
+				t.Errorf("%v median of first set should be smaller: %f vs %f", c.Field, c.A.Estimate, c.B.Estimate)
+			}
+		case Fitness:
+			if c.Significant(0.05) {
+				t.Errorf("%v should not differ significantly: p=%f", c.Field, c.P)
+			}
+		case Novelty:
+			if !math.IsNaN(c.A.Estimate) || !math.IsNaN(c.P) {
+				t.Errorf("%v is not carried by the samples so should not be compared: estimate %f, p=%f", c.Field, c.A.Estimate, c.P)
+			}
+		}
+	}
+
+	// Fields a sample does not carry have no value
+	for _, f := range []Field{Novelty, SpeciesCount, CompatibilityThreshold} {
+		if x := a[0].Value(f, Best); !math.IsNaN(x) {
+			t.Errorf("incorrect value for %v: expected NaN, actual %f", f, x)
+		}
+	}
+}
@@ -0,0 +1,278 @@
+package efficacy
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/klokare/evo"
+)
+
+// Known errors
+var (
+	ErrUnknownFormat = errors.New("unknown time series format")
+	ErrInvalidCSV    = errors.New("invalid time series csv")
+)
+
+// Format of the time series file
+type Format byte
+
+// Known formats
+const (
+	JSONLines Format = iota // One JSON-encoded point per line
+	CSV                     // One point per row after a header row
+)
+
+// Formats maps the names of the formats to their values
+var Formats = map[string]Format{
+	"json": JSONLines,
+	"csv":  CSV,
+}
+
+// The fields and methods summarised for each point
+var (
+	summaryFields  = []Field{Fitness, Novelty, Encoded, EncodedNodes, EncodedConns, Decoded, DecodedNodes, DecodedConns}
+	summaryMethods = []Method{Min, Max, Mean, Median, Best}
+)
+
+// Point is the state of a run's population after a generation is evaluated
+type Point struct {
+	BatchNumber            int
+	Generation             int
+	Evaluations            int     // Number of genomes evaluated in the run so far
+	Seconds                float64 // Time since the run began
+	Solved                 bool
+	Species                int
+	CompatibilityThreshold float64
+	Values                 map[Field]map[Method]float64
+}
+
+// Value returns the point's value for the field. The method is ignored for fields with a single
+// value. NaN is returned for fields the point does not carry.
+func (p Point) Value(f Field, m Method) float64 {
+	switch f {
+	case Generations:
+		return float64(p.Generation)
+	case Evaluations:
+		return float64(p.Evaluations)
+	case Seconds:
+		return p.Seconds
+	case SpeciesCount:
+		return float64(p.Species)
+	case CompatibilityThreshold:
+		return p.CompatibilityThreshold
+	default:
+		return summary(p.Values, f, m)
+	}
+}
+
+// Recorder writes a point for each generation of an experiment's runs so that their convergence
+// can be followed
+type Recorder struct {
+	Format
+	w      io.WriteCloser
+	mu     sync.Mutex
+	enc    *json.Encoder
+	csv    *csv.Writer
+	header bool
+}
+
+// NewRecorder creates a new time series recorder writing to the file in the format
+func NewRecorder(filename string, format Format) (*Recorder, error) {
+	if format != JSONLines && format != CSV {
+		return nil, ErrUnknownFormat
+	}
+	var err error
+	r := &Recorder{Format: format}
+	if r.w, err = os.Create(filename); err != nil {
+		return nil, err
+	}
+	if format == CSV {
+		r.csv = csv.NewWriter(r.w)
+	} else {
+		r.enc = json.NewEncoder(r.w)
+	}
+	return r, nil
+}
+
+// Close the underlying writer returning any error from that action
+func (r *Recorder) Close() error {
+	if r.csv != nil {
+		r.csv.Flush()
+		if err := r.csv.Error(); err != nil {
+			r.w.Close()
+			return err
+		}
+	}
+	return r.w.Close()
+}
+
+// Record appends the point to the file. Points from concurrent runs may be recorded safely.
+func (r *Recorder) Record(p Point) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.csv == nil {
+		return r.enc.Encode(p)
+	}
+	if !r.header {
+		r.header = true
+		if err := r.csv.Write(csvHeader()); err != nil {
+			return err
+		}
+	}
+	return r.csv.Write(csvRow(p))
+}
+
+// Callbacks returns the callbacks for the given batch number. The first should be subscribed to
+// the Started event and the second to the Evaluated event. The threshold function, if not nil,
+// returns the current compatibility threshold of the experiment's speciator.
+func (r *Recorder) Callbacks(num int, threshold func() float64) (start, evaluated evo.Callback) {
+	began := time.Now()
+	var evaluations int
+
+	// Callback which starts the timer
+	start = func(evo.Population) error {
+		began = time.Now()
+		evaluations = 0
+		return nil
+	}
+
+	// Callback which records the point
+	evaluated = func(pop evo.Population) error {
+		if len(pop.Genomes) == 0 {
+			return nil
+		}
+		evaluations += len(pop.Genomes)
+		p := Point{
+			BatchNumber: num,
+			Generation:  pop.Generation,
+			Evaluations: evaluations,
+			Seconds:     float64(time.Since(began)) / float64(time.Second),
+		}
+		var best evo.Genome
+		best, p.Values = summarise(pop)
+		p.Solved = best.Solved
+		species := make(map[int]bool, 20)
+		for _, g := range pop.Genomes {
+			species[g.Species] = true
+		}
+		p.Species = len(species)
+		if threshold != nil {
+			p.CompatibilityThreshold = threshold()
+		}
+		return r.Record(p)
+	}
+	return
+}
+
+// ReadPoints reads the points written by a recorder in the format
+func ReadPoints(rd io.Reader, format Format) (ps []Point, err error) {
+	switch format {
+	case JSONLines:
+		dec := json.NewDecoder(rd)
+		for {
+			var p Point
+			if err = dec.Decode(&p); err == io.EOF {
+				return ps, nil
+			} else if err != nil {
+				return nil, err
+			}
+			ps = append(ps, p)
+		}
+	case CSV:
+		var rows [][]string
+		if rows, err = csv.NewReader(rd).ReadAll(); err != nil {
+			return nil, err
+		}
+		if len(rows) == 0 {
+			return nil, nil
+		}
+		if strings.Join(rows[0], ",") != strings.Join(csvHeader(), ",") {
+			return nil, ErrInvalidCSV
+		}
+		ps = make([]Point, 0, len(rows)-1)
+		for _, row := range rows[1:] {
+			var p Point
+			if p, err = csvPoint(row); err != nil {
+				return nil, err
+			}
+			ps = append(ps, p)
+		}
+		return ps, nil
+	default:
+		return nil, ErrUnknownFormat
+	}
+}
+
+// The names of the columns in the csv format
+func csvHeader() []string {
+	h := []string{"batch", "generation", "evaluations", "seconds", "solved", "species", "compatibility-threshold"}
+	for _, f := range summaryFields {
+		for _, m := range summaryMethods {
+			h = append(h, strings.Replace(f.String(), " ", "-", -1)+"-"+strings.ToLower(m.String()))
+		}
+	}
+	return h
+}
+
+// The values of the point in the csv format
+func csvRow(p Point) []string {
+	row := []string{
+		strconv.Itoa(p.BatchNumber),
+		strconv.Itoa(p.Generation),
+		strconv.Itoa(p.Evaluations),
+		strconv.FormatFloat(p.Seconds, 'g', -1, 64),
+		strconv.FormatBool(p.Solved),
+		strconv.Itoa(p.Species),
+		strconv.FormatFloat(p.CompatibilityThreshold, 'g', -1, 64),
+	}
+	for _, f := range summaryFields {
+		for _, m := range summaryMethods {
+			row = append(row, strconv.FormatFloat(p.Values[f][m], 'g', -1, 64))
+		}
+	}
+	return row
+}
+
+// The point from its values in the csv format
+func csvPoint(row []string) (p Point, err error) {
+	if len(row) != 7+len(summaryFields)*len(summaryMethods) {
+		return p, ErrInvalidCSV
+	}
+	ints := []*int{&p.BatchNumber, &p.Generation, &p.Evaluations}
+	for i, x := range ints {
+		if *x, err = strconv.Atoi(row[i]); err != nil {
+			return
+		}
+	}
+	if p.Seconds, err = strconv.ParseFloat(row[3], 64); err != nil {
+		return
+	}
+	if p.Solved, err = strconv.ParseBool(row[4]); err != nil {
+		return
+	}
+	if p.Species, err = strconv.Atoi(row[5]); err != nil {
+		return
+	}
+	if p.CompatibilityThreshold, err = strconv.ParseFloat(row[6], 64); err != nil {
+		return
+	}
+	p.Values = make(map[Field]map[Method]float64, len(summaryFields))
+	i := 7
+	for _, f := range summaryFields {
+		p.Values[f] = make(map[Method]float64, len(summaryMethods))
+		for _, m := range summaryMethods {
+			if p.Values[f][m], err = strconv.ParseFloat(row[i], 64); err != nil {
+				return
+			}
+			i++
+		}
+	}
+	return
+}
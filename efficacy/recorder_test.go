@@ -0,0 +1,81 @@
+package efficacy
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/klokare/evo"
+)
+
+func TestRecorder(t *testing.T) {
+	dir, err := os.MkdirTemp("", "efficacy")
+	if err != nil {
+		t.Fatalf("error not expected: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	// Two generations of a population with two species
+	pops := []evo.Population{
+		{Generation: 1, Genomes: []evo.Genome{{ID: 1, Species: 1, Fitness: 1.0}, {ID: 2, Species: 2, Fitness: 3.0}}},
+		{Generation: 2, Genomes: []evo.Genome{{ID: 3, Species: 1, Fitness: 2.0}, {ID: 4, Species: 2, Fitness: 4.0, Solved: true}}},
+	}
+
+	for name, format := range Formats {
+		t.Run(name, func(t *testing.T) {
+
+			// Record the generations
+			filename := filepath.Join(dir, "series."+name)
+			r, err := NewRecorder(filename, format)
+			if err != nil {
+				t.Fatalf("error not expected: %v", err)
+			}
+			start, evaluated := r.Callbacks(7, func() float64 { return 3.5 })
+			start(evo.Population{})
+			for _, pop := range pops {
+				if err = evaluated(pop); err != nil {
+					t.Fatalf("error not expected: %v", err)
+				}
+			}
+			if err = r.Close(); err != nil {
+				t.Fatalf("error not expected: %v", err)
+			}
+
+			// Read the points back
+			f, err := os.Open(filename)
+			if err != nil {
+				t.Fatalf("error not expected: %v", err)
+			}
+			defer f.Close()
+			ps, err := ReadPoints(f, format)
+			if err != nil {
+				t.Fatalf("error not expected: %v", err)
+			}
+			if len(ps) != 2 {
+				t.Fatalf("incorrect number of points: expected 2, actual %d", len(ps))
+			}
+			for i, p := range ps {
+				if p.BatchNumber != 7 || p.Generation != i+1 || p.Evaluations != 2*(i+1) || p.Species != 2 || p.CompatibilityThreshold != 3.5 {
+					t.Errorf("incorrect point %d: %+v", i, p)
+				}
+				if x := p.Value(Fitness, Mean); x != float64(2+i) {
+					t.Errorf("incorrect mean fitness for point %d: expected %f, actual %f", i, float64(2+i), x)
+				}
+				if x := p.Value(Fitness, Best); x != float64(3+i) {
+					t.Errorf("incorrect best fitness for point %d: expected %f, actual %f", i, float64(3+i), x)
+				}
+				if x := p.Value(SpeciesCount, Mean); x != 2.0 {
+					t.Errorf("incorrect species for point %d: expected 2, actual %f", i, x)
+				}
+			}
+			if ps[0].Solved || !ps[1].Solved {
+				t.Errorf("incorrect solved: expected [false true], actual [%v %v]", ps[0].Solved, ps[1].Solved)
+			}
+		})
+	}
+
+	// Unknown format
+	if _, err := NewRecorder(filepath.Join(dir, "x"), Format(9)); err != ErrUnknownFormat {
+		t.Errorf("incorrect error: expected %v, actual %v", ErrUnknownFormat, err)
+	}
+}
@@ -3,6 +3,7 @@ package efficacy
 import (
 	"encoding/json"
 	"io"
+	"math"
 	"os"
 	"time"
 
@@ -30,6 +31,8 @@ const (
 	Decoded
 	DecodedNodes
 	DecodedConns
+	SpeciesCount
+	CompatibilityThreshold
 )
 
 // String returns a text description of the field
@@ -65,6 +68,10 @@ func (f Field) String() string {
 		return "decoded nodes"
 	case DecodedConns:
 		return "decoded conns"
+	case SpeciesCount:
+		return "species"
+	case CompatibilityThreshold:
+		return "compatibility threshold"
 	default:
 		return "unknown field"
 	}
@@ -112,6 +119,29 @@ type Sample struct {
 	Values      map[Field]map[Method]float64
 }
 
+// Value returns the sample's value for the field. The method is ignored for fields with a single
+// value. NaN is returned for fields the sample does not carry.
+func (s Sample) Value(f Field, m Method) float64 {
+	switch f {
+	case Generations:
+		return float64(s.Generations)
+	case Evaluations:
+		return float64(s.Evaluations)
+	case Seconds:
+		return s.Seconds
+	default:
+		return summary(s.Values, f, m)
+	}
+}
+
+// Returns the summarised value of the field for the method or NaN if it was not summarised
+func summary(values map[Field]map[Method]float64, f Field, m Method) float64 {
+	if x, ok := values[f][m]; ok {
+		return x
+	}
+	return math.NaN()
+}
+
 // Sampler records the results of an experiment's run
 type Sampler struct {
 	w   io.WriteCloser
@@ -162,102 +192,112 @@ func (s *Sampler) Callbacks(num int) (start, complete evo.Callback) {
 		sample.Generations = pop.Generation
 		sample.Evaluations = pop.Generation * len(pop.Genomes) // TODO: should this be changed to an OnIterations listener that sums the number of genomes per iteration?
 
-		// Identify the best genome
-		genomes := make([]evo.Genome, len(pop.Genomes)) // work with a copy so as not to affect other callbacks
-		copy(genomes, pop.Genomes)
-		evo.SortBy(genomes, evo.BySolved, evo.ByFitness, evo.ByComplexity, evo.ByAge)
-		best := genomes[len(genomes)-1]
+		// Identify the best genome and summarise the population
+		var best evo.Genome
+		best, sample.Values = summarise(pop)
 		sample.Solved = best.Solved
 
-		// Create the data
-		n := len(pop.Genomes)
-		fit := make([]float64, n)
-		nov := make([]float64, n)
-		enc := make([]float64, n)
-		encn := make([]float64, n)
-		encc := make([]float64, n)
-		dec := make([]float64, n)
-		decn := make([]float64, n)
-		decc := make([]float64, n)
-
-		for i, g := range genomes {
-			fit[i] = g.Fitness
-			nov[i] = g.Novelty
-			encn[i] = float64(len(g.Encoded.Nodes))
-			encc[i] = float64(len(g.Encoded.Conns))
-			enc[i] = encn[i] + encc[i]
-			decn[i] = float64(len(g.Decoded.Nodes))
-			decc[i] = float64(len(g.Decoded.Conns))
-			dec[i] = decn[i] + decc[i]
-		}
-
-		// Store the data
-		sample.Values[Fitness] = map[Method]float64{
-			Min:    float.Min(fit),
-			Max:    float.Max(fit),
-			Mean:   float.Mean(fit),
-			Median: float.Median(fit),
-			Best:   best.Fitness,
-		}
-
-		sample.Values[Novelty] = map[Method]float64{
-			Min:    float.Min(nov),
-			Max:    float.Max(nov),
-			Mean:   float.Mean(nov),
-			Median: float.Median(nov),
-			Best:   best.Novelty,
-		}
-
-		sample.Values[Encoded] = map[Method]float64{
-			Min:    float.Min(enc),
-			Max:    float.Max(enc),
-			Mean:   float.Mean(enc),
-			Median: float.Median(enc),
-			Best:   float64(best.Encoded.Complexity()),
-		}
-
-		sample.Values[EncodedNodes] = map[Method]float64{
-			Min:    float.Min(encn),
-			Max:    float.Max(encn),
-			Mean:   float.Mean(encn),
-			Median: float.Median(encn),
-			Best:   float64(len(best.Encoded.Nodes)),
-		}
-
-		sample.Values[EncodedConns] = map[Method]float64{
-			Min:    float.Min(encc),
-			Max:    float.Max(encc),
-			Mean:   float.Mean(encc),
-			Median: float.Median(encc),
-			Best:   float64(len(best.Encoded.Conns)),
-		}
-
-		sample.Values[Decoded] = map[Method]float64{
-			Min:    float.Min(dec),
-			Max:    float.Max(dec),
-			Mean:   float.Mean(dec),
-			Median: float.Median(dec),
-			Best:   float64(best.Decoded.Complexity()),
-		}
-
-		sample.Values[DecodedNodes] = map[Method]float64{
-			Min:    float.Min(decn),
-			Max:    float.Max(decn),
-			Mean:   float.Mean(decn),
-			Median: float.Median(decn),
-			Best:   float64(len(best.Decoded.Nodes)),
-		}
-
-		sample.Values[DecodedConns] = map[Method]float64{
-			Min:    float.Min(decc),
-			Max:    float.Max(decc),
-			Mean:   float.Mean(decc),
-			Median: float.Median(decc),
-			Best:   float64(len(best.Decoded.Conns)),
-		}
-
 		// Record the sample
 		return s.Record(*sample)
 	}
 	return
 }
+
+// Summarise the population, returning its best genome and the aggregate values of each field
+func summarise(pop evo.Population) (best evo.Genome, values map[Field]map[Method]float64) {
+
+	// Identify the best genome
+	genomes := make([]evo.Genome, len(pop.Genomes)) // work with a copy so as not to affect other callbacks
+	copy(genomes, pop.Genomes)
+	evo.SortBy(genomes, evo.BySolved, evo.ByFitness, evo.ByComplexity, evo.ByAge)
+	best = genomes[len(genomes)-1]
+
+	// Create the data
+	n := len(pop.Genomes)
+	fit := make([]float64, n)
+	nov := make([]float64, n)
+	enc := make([]float64, n)
+	encn := make([]float64, n)
+	encc := make([]float64, n)
+	dec := make([]float64, n)
+	decn := make([]float64, n)
+	decc := make([]float64, n)
+
+	for i, g := range genomes {
+		fit[i] = g.Fitness
+		nov[i] = g.Novelty
+		encn[i] = float64(len(g.Encoded.Nodes))
+		encc[i] = float64(len(g.Encoded.Conns))
+		enc[i] = encn[i] + encc[i]
+		decn[i] = float64(len(g.Decoded.Nodes))
+		decc[i] = float64(len(g.Decoded.Conns))
+		dec[i] = decn[i] + decc[i]
+	}
+
+	// Store the data
+	values = make(map[Field]map[Method]float64, 10)
+	values[Fitness] = map[Method]float64{
+		Min:    float.Min(fit),
+		Max:    float.Max(fit),
+		Mean:   float.Mean(fit),
+		Median: float.Median(fit),
+		Best:   best.Fitness,
+	}
+
+	values[Novelty] = map[Method]float64{
+		Min:    float.Min(nov),
+		Max:    float.Max(nov),
+		Mean:   float.Mean(nov),
+		Median: float.Median(nov),
+		Best:   best.Novelty,
+	}
+
+	values[Encoded] = map[Method]float64{
+		Min:    float.Min(enc),
+		Max:    float.Max(enc),
+		Mean:   float.Mean(enc),
+		Median: float.Median(enc),
+		Best:   float64(best.Encoded.Complexity()),
+	}
+
+	values[EncodedNodes] = map[Method]float64{
+		Min:    float.Min(encn),
+		Max:    float.Max(encn),
+		Mean:   float.Mean(encn),
+		Median: float.Median(encn),
+		Best:   float64(len(best.Encoded.Nodes)),
+	}
+
+	values[EncodedConns] = map[Method]float64{
+		Min:    float.Min(encc),
+		Max:    float.Max(encc),
+		Mean:   float.Mean(encc),
+		Median: float.Median(encc),
+		Best:   float64(len(best.Encoded.Conns)),
+	}
+
+	values[Decoded] = map[Method]float64{
+		Min:    float.Min(dec),
+		Max:    float.Max(dec),
+		Mean:   float.Mean(dec),
+		Median: float.Median(dec),
+		Best:   float64(best.Decoded.Complexity()),
+	}
+
+	values[DecodedNodes] = map[Method]float64{
+		Min:    float.Min(decn),
+		Max:    float.Max(decn),
+		Mean:   float.Mean(decn),
+		Median: float.Median(decn),
+		Best:   float64(len(best.Decoded.Nodes)),
+	}
+
+	values[DecodedConns] = map[Method]float64{
+		Min:    float.Min(decc),
+		Max:    float.Max(decc),
+		Mean:   float.Mean(decc),
+		Median: float.Median(decc),
+		Best:   float64(len(best.Decoded.Conns)),
+	}
+	return
+}
@@ -71,7 +71,7 @@ func main() {
 		eval := boxes.NewEvaluator(cfg.Int("boxes|resolution"))
 
 		// Initialise the template
-		exp.Transcriber.SetTemplate(boxes.Template(cfg.Int("boxes|resolution"), *hidden))
+		exp.SetTemplate(boxes.Template(cfg.Int("boxes|resolution"), *hidden))
 
 		// Add additional subscriptions
 		if s == nil {
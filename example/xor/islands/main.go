@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+
+	"github.com/klokare/evo"
+	"github.com/klokare/evo/config"
+	"github.com/klokare/evo/config/source"
+	"github.com/klokare/evo/example"
+	"github.com/klokare/evo/example/xor"
+	"github.com/klokare/evo/island"
+	"github.com/klokare/evo/neat"
+)
+
+func main() {
+
+	// Parse the command-line flags
+	var (
+		iter  = flag.Int("iterations", 100, "number of iterations for the first island")
+		cpath = flag.String("config", "../neat/xor.json", "path to the configuration file")
+		isls  = flag.Int("islands", 4, "number of islands exchanging genomes by migration")
+		seed  = flag.Int64("seed", 0, "seed for a reproducible run; zero for an unseeded run")
+	)
+	flag.Parse()
+
+	// Load the configuration
+	src, err := source.NewJSONFromFile(*cpath)
+	if err != nil {
+		log.Fatalf("%+v\n", err)
+	}
+	cfg := config.Configurer{Source: source.Multi([]config.Source{
+		source.Flag{},                            // Check flags  first
+		source.Environment{},                     // Then check environment variables
+		source.Named{Source: src, Label: *cpath}, // Lastly, consult the configuration file
+	})}
+
+	// Seed the run so that it can be reproduced, if requested
+	if *seed != 0 {
+		evo.SetSeed(*seed)
+	}
+
+	// Create the runner and the islands. Every island is configured the same way and the runner's
+	// subscriptions are added to each of them.
+	r := island.NewRunner(cfg)
+	exps := make([]evo.Experiment, *isls)
+	for i := range exps {
+		exps[i] = neat.NewExperiment(cfg)
+	}
+
+	// Show each island's best genome upon completion
+	r.AddSubscription(island.Subscription{Event: evo.Completed, Callback: func(i int, pop evo.Population) error {
+		log.Printf("island %d\n", i)
+		return example.ShowBest(pop)
+	}})
+
+	// Run the islands until the first has completed a set number of iterations
+	ctx, fn, cb := evo.WithIterations(context.Background(), *iter)
+	defer fn() // ensure the context cancels
+	r.AddSubscription(island.Subscription{Event: evo.Evaluated, Callback: func(i int, pop evo.Population) error {
+		if i == 0 {
+			return cb(pop)
+		}
+		return nil
+	}})
+
+	// Stop the islands if any of them has a solution
+	ctx, fn, cb = evo.WithSolution(ctx)
+	defer fn() // ensure the context cancels
+	r.AddSubscription(island.Subscription{Event: evo.Evaluated, Callback: func(_ int, pop evo.Population) error { return cb(pop) }})
+
+	// Execute the experiments
+	if _, err = r.Run(ctx, exps, xor.Evaluator{}); err != nil {
+		log.Fatalf("%+v\n", err)
+	}
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+
+	"github.com/klokare/evo"
+	"github.com/klokare/evo/config"
+	"github.com/klokare/evo/config/source"
+	"github.com/klokare/evo/example"
+	"github.com/klokare/evo/example/xor"
+	"github.com/klokare/evo/mapelites"
+	"github.com/klokare/evo/neat"
+)
+
+func main() {
+
+	// Parse the command-line flags
+	var (
+		iter  = flag.Int("iterations", 100, "number of iterations for the experiment")
+		cpath = flag.String("config", "../neat/xor.json", "path to the configuration file")
+		seed  = flag.Int64("seed", 0, "seed for a reproducible run; zero for an unseeded run")
+	)
+	flag.Parse()
+
+	// Load the configuration
+	src, err := source.NewJSONFromFile(*cpath)
+	if err != nil {
+		log.Fatalf("%+v\n", err)
+	}
+	cfg := config.Configurer{Source: source.Multi([]config.Source{
+		source.Flag{},                            // Check flags  first
+		source.Environment{},                     // Then check environment variables
+		source.Named{Source: src, Label: *cpath}, // Lastly, consult the configuration file
+	})}
+
+	// Seed the run so that it can be reproduced, if requested
+	if *seed != 0 {
+		evo.SetSeed(*seed)
+	}
+
+	// Create the experiment and the runner with its empty archive
+	exp := neat.NewExperiment(cfg)
+	r := mapelites.NewRunner(cfg)
+
+	// Show summary upon completion
+	exp.AddSubscription(evo.Subscription{Event: evo.Completed, Callback: example.ShowBest})
+
+	// Run the experiment for a set number of iterations
+	ctx, fn, cb := evo.WithIterations(context.Background(), *iter)
+	defer fn() // ensure the context cancels
+	exp.AddSubscription(evo.Subscription{Event: evo.Evaluated, Callback: cb})
+
+	// Stop the experiment if there is a solution
+	ctx, fn, cb = evo.WithSolution(ctx)
+	defer fn() // ensure the context cancels
+	exp.AddSubscription(evo.Subscription{Event: evo.Evaluated, Callback: cb})
+
+	// Illuminate the behavior space
+	if _, err = r.Run(ctx, exp, xor.Evaluator{}); err != nil {
+		log.Fatalf("%+v\n", err)
+	}
+	log.Printf("map-elites archive has %d elites covering %.1f%% of the grid\n", r.Len(), r.Coverage()*100.0)
+}
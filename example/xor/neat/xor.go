@@ -4,14 +4,21 @@ import (
 	"context"
 	"flag"
 	"log"
+	"os"
+	"path/filepath"
+	"strings"
 
 	"github.com/klokare/evo"
+	"github.com/klokare/evo/codec"
 	"github.com/klokare/evo/config"
 	"github.com/klokare/evo/config/source"
 	"github.com/klokare/evo/efficacy"
 	"github.com/klokare/evo/example"
 	"github.com/klokare/evo/example/xor"
+	"github.com/klokare/evo/lineage"
 	"github.com/klokare/evo/neat"
+	"github.com/klokare/evo/searcher/process"
+	"github.com/klokare/evo/searcher/remote"
 )
 
 // Define flags to override configuration file settings
@@ -28,6 +35,14 @@ func main() {
 		iter  = flag.Int("iterations", 100, "number of iterations for experiment")
 		cpath = flag.String("config", "xor.json", "path to the configuration file")
 		epath = flag.String("efficacy", "xor-samples.txt", "path for efficacy sample file")
+		kpath = flag.String("checkpoint", "", "path for checkpoint file on single run")
+		rpath = flag.String("resume", "", "path of checkpoint file from which to resume a single run")
+		bpath = flag.String("best", "", "path for the best genome, saved in JSON upon completion of a single run")
+		rmt   = flag.String("remote", "", "comma-separated URLs of remote XOR workers to evaluate the phenomes")
+		seed  = flag.Int64("seed", 0, "seed for reproducible runs, each run using the next value; zero for unseeded runs")
+		fpath = flag.String("effective", "", "path for the effective configuration, with the source of each value, written upon completion")
+		lpath = flag.String("lineage", "", "path for the champion's lineage, written upon completion of a single run as DOT if the extension is .dot and otherwise as Newick following first parents only")
+		tpath = flag.String("series", "", "path for the per-generation time series, written as CSV if the extension is .csv and JSON lines otherwise")
 	)
 	flag.Parse()
 
@@ -37,11 +52,28 @@ func main() {
 		log.Fatalf("%+v\n", err)
 	}
 	cfg := config.Configurer{Source: source.Multi([]config.Source{
-		source.Flag{},        // Check flags  first
-		source.Environment{}, // Then check environment variables
-		src,                  // Lastly, consult the configuration file
+		source.Flag{},                            // Check flags  first
+		source.Environment{},                     // Then check environment variables
+		source.Named{Source: src, Label: *cpath}, // Lastly, consult the configuration file
 	})}
 
+	// Record the effective configuration, if requested
+	if *fpath != "" {
+		cfg.Recorder = config.NewRecorder()
+		defer func() {
+			f, err := os.Create(*fpath)
+			if err == nil {
+				err = cfg.Recorder.Write(f)
+				if cerr := f.Close(); err == nil {
+					err = cerr
+				}
+			}
+			if err != nil {
+				log.Printf("could not write the effective configuration: %v\n", err)
+			}
+		}()
+	}
+
 	// Create a sample file if performing multiple runs
 	var s *efficacy.Sampler
 	if *runs > 1 {
@@ -51,20 +83,62 @@ func main() {
 		defer s.Close()
 	}
 
+	// Create a time series file, if requested
+	var ts *efficacy.Recorder
+	if *tpath != "" {
+		format := efficacy.JSONLines
+		if strings.ToLower(filepath.Ext(*tpath)) == ".csv" {
+			format = efficacy.CSV
+		}
+		if ts, err = efficacy.NewRecorder(*tpath, format); err != nil {
+			log.Fatalf("%+v\n", err)
+		}
+		defer ts.Close()
+	}
+
 	// Iterate the runs
 	for r := 0; r < *runs; r++ {
 
+		// Seed the run so that it can be reproduced, if requested
+		if *seed != 0 {
+			evo.SetSeed(*seed + int64(r))
+		}
+
 		// Create the experiment
 		exp := neat.NewExperiment(cfg)
 
+		// Send the phenomes to remote workers, started with the xor/worker command, if requested
+		if *rmt != "" {
+			exp.Translator = process.Translator{}
+			exp.Searcher = &remote.Searcher{Workers: strings.Split(*rmt, ",")}
+		}
+
 		// Add additional subscriptions
 		if s == nil {
 			exp.AddSubscription(evo.Subscription{Event: evo.Completed, Callback: example.ShowBest}) // Show summary upon completion
+			if *bpath != "" {
+				exp.AddSubscription(evo.Subscription{Event: evo.Completed, Callback: codec.SaveBest(*bpath, codec.JSON)}) // Save the best genome
+			}
+			if *lpath != "" {
+				format := lineage.Newick
+				if strings.ToLower(filepath.Ext(*lpath)) == ".dot" {
+					format = lineage.DOT
+				}
+				store := lineage.NewStore()
+				exp.AddSubscription(evo.Subscription{Event: evo.Started, Callback: store.Record})                         // Record the initial genomes
+				exp.AddSubscription(evo.Subscription{Event: evo.Evaluated, Callback: store.Record})                       // Track the ancestry
+				exp.AddSubscription(evo.Subscription{Event: evo.Completed, Callback: store.SaveChampion(*lpath, format)}) // Save the champion's lineage
+			}
 		} else {
 			c0, c1 := s.Callbacks(r)
 			exp.AddSubscription(evo.Subscription{Event: evo.Started, Callback: c0})   // Begin the efficacy sample
 			exp.AddSubscription(evo.Subscription{Event: evo.Completed, Callback: c1}) // End the efficacy sample
 		}
+		if ts != nil {
+			c0, c1 := ts.Callbacks(r, func() float64 { return exp.CompatibilityThreshold })
+			exp.AddSubscription(evo.Subscription{Event: evo.Started, Callback: c0})   // Begin the time series
+			exp.AddSubscription(evo.Subscription{Event: evo.Evaluated, Callback: c1}) // Record each generation
+		}
 
 		// Run the experiment for a set number of iterations
 		ctx, fn, cb := evo.WithIterations(context.Background(), *iter)
@@ -76,7 +150,22 @@ func main() {
 		defer fn() // ensure the context cancels
 		exp.AddSubscription(evo.Subscription{Event: evo.Evaluated, Callback: cb})
 
-		// Execute the experiment
+		// Save checkpoints so the run can be resumed
+		if s == nil && *kpath != "" {
+			exp.SetCheckpointer(evo.FileCheckpointer{Filename: *kpath})
+		}
+
+		// Execute the experiment, resuming from a checkpoint if one was provided
+		if s == nil && *rpath != "" {
+			var cp evo.Checkpoint
+			if cp, err = evo.ReadCheckpointFromFile(*rpath); err != nil {
+				log.Fatalf("%+v\n", err)
+			}
+			if _, err = evo.Resume(ctx, exp, xor.Evaluator{}, cp); err != nil {
+				log.Fatalf("%+v\n", err)
+			}
+			continue
+		}
 		if _, err = evo.Run(ctx, exp, xor.Evaluator{}); err != nil {
 			log.Fatalf("%+v\n", err)
 		}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+
+	"github.com/klokare/evo"
+	"github.com/klokare/evo/config"
+	"github.com/klokare/evo/config/source"
+	"github.com/klokare/evo/example"
+	"github.com/klokare/evo/example/xor"
+	"github.com/klokare/evo/neat"
+	"github.com/klokare/evo/realtime"
+)
+
+func main() {
+
+	// Parse the command-line flags
+	var (
+		iter  = flag.Int("iterations", 100, "number of intervals for the experiment")
+		cpath = flag.String("config", "../neat/xor.json", "path to the configuration file")
+		kpath = flag.String("checkpoint", "", "path for the checkpoint file")
+		rpath = flag.String("resume", "", "path of checkpoint file from which to resume the run")
+		seed  = flag.Int64("seed", 0, "seed for a reproducible run; zero for an unseeded run")
+	)
+	flag.Parse()
+
+	// Load the configuration
+	src, err := source.NewJSONFromFile(*cpath)
+	if err != nil {
+		log.Fatalf("%+v\n", err)
+	}
+	cfg := config.Configurer{Source: source.Multi([]config.Source{
+		source.Flag{},                            // Check flags  first
+		source.Environment{},                     // Then check environment variables
+		source.Named{Source: src, Label: *cpath}, // Lastly, consult the configuration file
+	})}
+
+	// Seed the run so that it can be reproduced, if requested
+	if *seed != 0 {
+		evo.SetSeed(*seed)
+	}
+
+	// Create the experiment and runner
+	exp := neat.NewExperiment(cfg)
+	r := realtime.NewRunner(cfg)
+
+	// Show summary upon completion
+	exp.AddSubscription(evo.Subscription{Event: evo.Completed, Callback: example.ShowBest})
+
+	// Run the experiment for a set number of intervals
+	ctx, fn, cb := evo.WithIterations(context.Background(), *iter)
+	defer fn() // ensure the context cancels
+	exp.AddSubscription(evo.Subscription{Event: evo.Evaluated, Callback: cb})
+
+	// Stop the experiment if there is a solution
+	ctx, fn, cb = evo.WithSolution(ctx)
+	defer fn() // ensure the context cancels
+	exp.AddSubscription(evo.Subscription{Event: evo.Evaluated, Callback: cb})
+
+	// Save checkpoints so the run can be resumed
+	if *kpath != "" {
+		exp.SetCheckpointer(evo.FileCheckpointer{Filename: *kpath})
+	}
+
+	// Execute the experiment, resuming from a checkpoint if one was provided
+	if *rpath != "" {
+		var cp evo.Checkpoint
+		if cp, err = evo.ReadCheckpointFromFile(*rpath); err != nil {
+			log.Fatalf("%+v\n", err)
+		}
+		if _, err = r.Resume(ctx, exp, xor.Evaluator{}, cp); err != nil {
+			log.Fatalf("%+v\n", err)
+		}
+		return
+	}
+	if _, err = r.Run(ctx, exp, xor.Evaluator{}); err != nil {
+		log.Fatalf("%+v\n", err)
+	}
+}
@@ -0,0 +1,22 @@
+package main
+
+import (
+	"flag"
+	"log"
+
+	"github.com/klokare/evo/example/xor"
+	"github.com/klokare/evo/searcher/remote"
+)
+
+func main() {
+
+	// Parse the command-line flags
+	addr := flag.String("addr", ":8080", "address on which to serve evaluation requests")
+	flag.Parse()
+
+	// Serve the XOR evaluator until the worker is stopped
+	log.Printf("serving XOR evaluations on %s\n", *addr)
+	if err := remote.ListenAndServe(*addr, remote.Worker{Evaluator: xor.Evaluator{}}); err != nil {
+		log.Fatalf("%+v\n", err)
+	}
+}
@@ -4,7 +4,6 @@ import (
 	"context"
 	"errors"
 	"sort"
-	"sync/atomic"
 
 	"github.com/klokare/evo/internal/workers"
 )
@@ -15,6 +14,13 @@ var (
 	ErrNoSeedGenomes                = errors.New("seeder produced no genomes")
 )
 
+// Keys identifying the random streams used when running an experiment
+const (
+	populateStream int64 = iota + 1
+	selectStream
+	offspringStream
+)
+
 // An Experiment comprises the helpers necessary for creating, evaluating, and advancing a
 // population in the search of a solution (or simply a better solver) of a particular problem.
 type Experiment interface {
@@ -34,20 +40,15 @@ type Experiment interface {
 func Run(ctx context.Context, exp Experiment, eval Evaluator) (pop Population, err error) {
 
 	// The experiment provides subscribers so subscribe them
-	listeners := make(map[Event][]Callback, 10)
-	if sx, ok := exp.(SubscriptionProvider); ok {
-		for _, s := range sx.Subscriptions() {
-			var ls []Callback
-			if ls, ok = listeners[s.Event]; !ok {
-				ls = make([]Callback, 0, 10)
-			}
-			ls = append(ls, s.Callback)
-			listeners[s.Event] = ls
-		}
-	}
+	listeners := Subscribe(exp)
 
 	// Create the initial population
-	if pop, err = exp.Populate(); err != nil {
+	if px, ok := exp.(RandomPopulator); ok {
+		pop, err = px.PopulateWith(NewStream(populateStream))
+	} else {
+		pop, err = exp.Populate()
+	}
+	if err != nil {
 		return
 	}
 	if len(pop.Genomes) == 0 {
@@ -62,17 +63,71 @@ func Run(ctx context.Context, exp Experiment, eval Evaluator) (pop Population, e
 	}
 
 	// Inform listeners that the population has started
-	if err = publish(listeners, Started, pop); err != nil {
+	if err = listeners.Publish(Started, pop); err != nil {
+		return
+	}
+
+	// Iterate the experiment
+	err = iterate(ctx, exp, eval, listeners, &pop, lastGID)
+	return
+}
+
+// Resume continues an experiment from the checkpoint. The experiment should be configured as it was
+// when the checkpoint was taken. If it is Stateful, its helpers' internal state is restored before
+// the first iteration. The Started event is published before iterating so listeners can initialise
+// themselves just as they would with Run.
+func Resume(ctx context.Context, exp Experiment, eval Evaluator, cp Checkpoint) (pop Population, err error) {
+
+	// Check for errors
+	if cp.Version != CheckpointVersion {
+		err = ErrUnknownCheckpointVersion
+		return
+	}
+	if len(cp.Population.Genomes) == 0 {
+		err = ErrNoSeedGenomes
+		return
+	}
+
+	// Restore the helpers' internal state
+	if sx, ok := exp.(Stateful); ok && len(cp.State) > 0 {
+		if err = sx.Restore(cp.State); err != nil {
+			return
+		}
+	}
+
+	// The experiment provides subscribers so subscribe them
+	listeners := Subscribe(exp)
+
+	// Restore the population and genome ID sequence
+	pop = cp.Population
+	lastGID := setSequence(pop.Genomes)
+	if *lastGID < cp.LastGID {
+		*lastGID = cp.LastGID
+	}
+
+	// Inform listeners that the population has started
+	if err = listeners.Publish(Started, pop); err != nil {
 		return
 	}
 
 	// Iterate the experiment
+	err = iterate(ctx, exp, eval, listeners, &pop, lastGID)
+	return
+}
+
+// Iterate the experiment until the context is done or an error occurs
+func iterate(ctx context.Context, exp Experiment, eval Evaluator, listeners Listeners, pop *Population, lastGID *int64) (err error) {
 	for {
 
 		// Select the continuing genomes and those who will become parents
 		var continuing []Genome
 		var parents [][]Genome
-		if continuing, parents, err = exp.Select(pop); err != nil {
+		if sx, ok := exp.(RandomSelector); ok {
+			continuing, parents, err = sx.SelectWith(NewStream(selectStream, int64(pop.Generation)), *pop)
+		} else {
+			continuing, parents, err = exp.Select(*pop)
+		}
+		if err != nil {
 			return
 		}
 
@@ -88,7 +143,7 @@ func Run(ctx context.Context, exp Experiment, eval Evaluator) (pop Population, e
 
 		// Create the population
 		var offspring []Genome
-		if offspring, err = createOffspring(exp, lastGID, parents); err != nil {
+		if offspring, err = createOffspring(exp, lastGID, pop.Generation, parents); err != nil {
 			return
 		}
 		pop.Genomes = make([]Genome, 0, len(continuing)+len(offspring))
@@ -96,12 +151,12 @@ func Run(ctx context.Context, exp Experiment, eval Evaluator) (pop Population, e
 		pop.Genomes = append(pop.Genomes, offspring...)
 
 		// Speciate the genomes
-		if err = exp.Speciate(&pop); err != nil {
+		if err = exp.Speciate(pop); err != nil {
 			return
 		}
 
 		// Inform listeners that the population has been advanced
-		if err = publish(listeners, Advanced, pop); err != nil {
+		if err = listeners.Publish(Advanced, *pop); err != nil {
 			return
 		}
 
@@ -112,7 +167,7 @@ func Run(ctx context.Context, exp Experiment, eval Evaluator) (pop Population, e
 		}
 
 		// Inform listeners that decoding has completed
-		if err = publish(listeners, Decoded, pop); err != nil {
+		if err = listeners.Publish(Decoded, *pop); err != nil {
 			return
 		}
 
@@ -123,17 +178,28 @@ func Run(ctx context.Context, exp Experiment, eval Evaluator) (pop Population, e
 		}
 
 		// Update the population with the results
-		update(&pop, results)
+		update(pop, results)
 
 		// Inform listeners that evaluation has completed
-		if err = publish(listeners, Evaluated, pop); err != nil {
+		if err = listeners.Publish(Evaluated, *pop); err != nil {
 			return
 		}
 
+		// Take a checkpoint, if the experiment wants one
+		if cx, ok := exp.(Checkpointer); ok {
+			var cp Checkpoint
+			if cp, err = NewCheckpoint(exp, *pop, *lastGID); err != nil {
+				return
+			}
+			if err = cx.Checkpoint(cp); err != nil {
+				return
+			}
+		}
+
 		// Check for completion
 		select {
 		case <-ctx.Done():
-			err = publish(listeners, Completed, pop)
+			err = listeners.Publish(Completed, *pop)
 			return
 		default:
 			// continue to next iteration
@@ -159,55 +225,92 @@ type progenator interface {
 	Mutator
 }
 
-// Create the offspring from the parents, mutate the children, and set their IDs.
-func createOffspring(helper progenator, lastGID *int64, parents [][]Genome) (offspring []Genome, err error) {
-
-	// Receive offspring
-	offspring = make([]Genome, 0, len(parents))
-	ch := make(chan Genome, len(parents))
-	done := make(chan struct{})
-	go func(ch <-chan Genome, done chan struct{}) {
-		defer close(done)
-		for g := range ch {
-			offspring = append(offspring, g)
-		}
-	}(ch, done)
+// Create the offspring from the parents, mutate the children, and set their IDs and lineage. Each
+// child's ID follows from the position of its parents, and its random stream from its ID, so the
+// offspring do not depend on the order in which they are created.
+func createOffspring(helper progenator, lastGID *int64, generation int, parents [][]Genome) (offspring []Genome, err error) {
 
 	// Create the tasks
+	offspring = make([]Genome, len(parents))
 	tasks := make([]workers.Task, len(parents))
-	for i, pgrp := range parents {
-		tasks[i] = pgrp
+	for i := range parents {
+		tasks[i] = i
 	}
+	first := *lastGID + 1
+	*lastGID += int64(len(parents))
 
 	// Do the work
+	cx, rc := helper.(RandomCrosser)
+	mx, rm := helper.(RandomMutator)
 	err = workers.Do(tasks, func(wt workers.Task) (err error) {
-		pgrp := wt.([]Genome)
+		i := wt.(int)
+		id := first + int64(i)
+		rng := NewStream(offspringStream, id)
+
 		// Create the child
 		var child Genome
-		if child, err = helper.Cross(pgrp...); err != nil {
+		if rc {
+			child, err = cx.CrossWith(rng, parents[i]...)
+		} else {
+			child, err = helper.Cross(parents[i]...)
+		}
+		if err != nil {
 			return
 		}
-		child.ID = atomic.AddInt64(lastGID, 1) // Assign the next ID
+		child.ID = id // Assign the next ID
+		SetLineage(&child, generation, parents[i]...)
 
 		// Mutate the child and add to the list
-		if err = helper.Mutate(&child); err != nil {
+		if rm {
+			err = mx.MutateWith(rng, &child)
+		} else {
+			err = helper.Mutate(&child)
+		}
+		if err != nil {
 			return
 		}
-		ch <- child
+		offspring[i] = child
 		return
 	})
-	close(ch)
-	<-done
 	return
 }
 
-type decoder interface {
+// Decoder transcribes genomes' encoded substrates and translates the decoded ones into networks
+type Decoder interface {
 	Transcriber
 	Translator
 }
 
+// Decode the genome into a phenome. The encoded substrate is transcribed only if the genome has not
+// been decoded already and the decoded substrate is kept in the genome. Runners other than Run use
+// it to decode genomes in the same way.
+func Decode(dec Decoder, g *Genome) (p Phenome, err error) {
+
+	// Decode the encoded substrate
+	if g.Decoded.Complexity() == 0 {
+		if g.Decoded, err = dec.Transcribe(g.Encoded); err != nil {
+			return
+		}
+	}
+
+	// Create the neural network
+	var net Network
+	if net, err = dec.Translate(g.Decoded); err != nil {
+		return
+	}
+
+	// Create the phenome
+	p = Phenome{
+		ID:      g.ID,
+		Network: net,
+		Traits:  make([]float64, len(g.Traits)),
+	}
+	copy(p.Traits, g.Traits)
+	return
+}
+
 // Decode the genomes into phenomes
-func decodeGenomes(dec decoder, genomes []Genome) (phenomes []Phenome, err error) {
+func decodeGenomes(dec Decoder, genomes []Genome) (phenomes []Phenome, err error) {
 
 	// Receive phenomes
 	phenomes = make([]Phenome, 0, len(genomes))
@@ -228,28 +331,10 @@ func decodeGenomes(dec decoder, genomes []Genome) (phenomes []Phenome, err error
 
 	// Do the work
 	err = workers.Do(tasks, func(wt workers.Task) (err error) {
-
-		// Decode the encoded substrate
-		g := wt.(*Genome)
-		if g.Decoded.Complexity() == 0 {
-			if g.Decoded, err = dec.Transcribe(g.Encoded); err != nil {
-				return
-			}
-		}
-
-		// Create the neural network
-		var net Network
-		if net, err = dec.Translate(g.Decoded); err != nil {
+		var p Phenome
+		if p, err = Decode(dec, wt.(*Genome)); err != nil {
 			return
 		}
-
-		// Create the phenome and add to the list
-		p := Phenome{
-			ID:      g.ID,
-			Network: net,
-			Traits:  make([]float64, len(g.Traits)),
-		}
-		copy(p.Traits, g.Traits)
 		ch <- p
 		return
 	})
@@ -265,7 +350,9 @@ func update(pop *Population, results []Result) {
 		if idx < len(results) && results[idx].ID == g.ID {
 			g.Fitness = results[idx].Fitness
 			g.Novelty = results[idx].Novelty
+			g.Objectives = results[idx].Objectives
 			g.Solved = results[idx].Solved
+			g.Behavior = results[idx].Behavior
 		}
 		pop.Genomes[i] = g
 	}
@@ -3,6 +3,7 @@ package evo
 import (
 	"context"
 	"errors"
+	"reflect"
 	"testing"
 	"time"
 )
@@ -309,6 +310,45 @@ func TestExperimentRestorer(t *testing.T) {
 	}
 }
 
+func TestExperimentReproducible(t *testing.T) {
+
+	// Run the experiment with the same seed several times
+	var runs []Population
+	for i := 0; i < 3; i++ {
+		SetSeed(42)
+		exp := &mockRandomExperiment{mockExperiment: new(mockExperiment)}
+		ctx, fn, cb := WithIterations(context.Background(), 5)
+		exp.callbacks = []Subscription{{Event: Evaluated, Callback: cb}}
+		pop, err := Run(ctx, exp, &mockEvaluator{})
+		fn()
+		if err != nil {
+			t.Fatalf("error not expected: %v", err)
+		}
+		runs = append(runs, pop)
+	}
+
+	// The populations should be identical
+	for i := 1; i < len(runs); i++ {
+		if !reflect.DeepEqual(runs[0], runs[i]) {
+			t.Errorf("seeded runs produced different populations:\nexpected %+v\nactual   %+v", runs[0], runs[i])
+		}
+	}
+
+	// A different seed should produce a different population
+	SetSeed(43)
+	exp := &mockRandomExperiment{mockExperiment: new(mockExperiment)}
+	ctx, fn, cb := WithIterations(context.Background(), 5)
+	defer fn()
+	exp.callbacks = []Subscription{{Event: Evaluated, Callback: cb}}
+	pop, err := Run(ctx, exp, &mockEvaluator{})
+	if err != nil {
+		t.Fatalf("error not expected: %v", err)
+	}
+	if reflect.DeepEqual(runs[0], pop) {
+		t.Error("runs with different seeds produced the same population")
+	}
+}
+
 type mockExperiment struct {
 	mockCrosser
 	mockMutator
@@ -430,3 +470,37 @@ func (m *mockSpeciator) Speciate(*Population) error {
 	}
 	return nil
 }
+
+// mockRandomExperiment draws from the streams provided by the experiment
+type mockRandomExperiment struct {
+	*mockExperiment
+}
+
+func (m *mockRandomExperiment) PopulateWith(rng Random) (Population, error) {
+	p := Population{Genomes: make([]Genome, 10)}
+	for i := range p.Genomes {
+		p.Genomes[i] = Genome{ID: int64(i + 1), Traits: []float64{rng.Float64()}}
+	}
+	return p, nil
+}
+
+func (m *mockRandomExperiment) SelectWith(rng Random, pop Population) ([]Genome, [][]Genome, error) {
+	ps := make([][]Genome, len(pop.Genomes))
+	for i := range ps {
+		ps[i] = []Genome{pop.Genomes[rng.Intn(len(pop.Genomes))], pop.Genomes[rng.Intn(len(pop.Genomes))]}
+	}
+	return nil, ps, nil
+}
+
+func (m *mockRandomExperiment) CrossWith(rng Random, parents ...Genome) (Genome, error) {
+	x := rng.Float64()
+	for _, p := range parents {
+		x += p.Traits[0]
+	}
+	return Genome{Traits: []float64{x / float64(len(parents)+1)}}, nil
+}
+
+func (m *mockRandomExperiment) MutateWith(rng Random, g *Genome) error {
+	g.Traits[0] += rng.NormFloat64()
+	return nil
+}
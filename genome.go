@@ -3,15 +3,20 @@ package evo
 // A Genome is the encoded neural network and its last result when applied in evaluation.
 // For performance reasons, helpers should keep the nodes (by ID) and conns (by source and then target IDs) sorted though this is not required.
 type Genome struct {
-	ID      int64     // The genome's unique identifier
-	Species int       // The ID of the species
-	Age     int       // Number of generations genome has been alive
-	Fitness float64   // The genome's latest fitness score
-	Novelty float64   // The genome's latest novelty score, if any
-	Solved  bool      // True if the genome produced a solution in the last evaluation
-	Traits  []float64 // Additional information, encoded as floats, that will be passed to the evaluation function
-	Encoded Substrate // The encoded neural network layout
-	Decoded Substrate // The decoded neural network layout
+	ID         int64       // The genome's unique identifier
+	Species    int         // The ID of the species
+	Age        int         // Number of generations genome has been alive
+	Fitness    float64     // The genome's latest fitness score
+	Novelty    float64     // The genome's latest novelty score, if any
+	Objectives []float64   // The genome's latest objective scores, if any, where higher is better
+	Solved     bool        // True if the genome produced a solution in the last evaluation
+	Behavior   interface{} // The genome's latest behavior, if any, as described by the evaluator
+	Traits     []float64   // Additional information, encoded as floats, that will be passed to the evaluation function
+	Encoded    Substrate   // The encoded neural network layout
+	Decoded    Substrate   // The decoded neural network layout
+	Parents    []int64     // The IDs of the genomes from which this one was created, if any
+	Birth      int         // The generation in which the genome was created
+	Mutations  []string    // The mutation operations applied when the genome was created
 }
 
 // Complexity returns the number of nodes and connections in the genome
@@ -26,9 +31,24 @@ type Phenome struct {
 
 // Result describes the outcome of running the evaluation. ID and fitness are required properties. If an error occurs in the evaluation, this should be returned with the result.
 type Result struct {
-	ID       int64       // The unique ID of the genome from which the phenome was made
-	Solved   bool        // True if the network provided a winning solution
-	Fitness  float64     // A positive value indicating the fitness of this network after evaluation
-	Novelty  float64     // An optional value indicating the novelty of this network's decisions during evaluation
-	Behavior interface{} // An optional slice describing the novelty of the network's decisions
+	ID         int64       // The unique ID of the genome from which the phenome was made
+	Solved     bool        // True if the network provided a winning solution
+	Fitness    float64     // A positive value indicating the fitness of this network after evaluation
+	Novelty    float64     // An optional value indicating the novelty of this network's decisions during evaluation
+	Behavior   interface{} // An optional slice describing the novelty of the network's decisions
+	Objectives []float64   // Optional scores, where higher is better, for multi-objective selection
+}
+
+// SetLineage records the parents and birth generation of a newly created child and clears any
+// mutations carried over from them so that the mutator may record its own. A genome crossed with
+// itself is recorded as a single parent.
+func SetLineage(child *Genome, generation int, parents ...Genome) {
+	child.Parents = make([]int64, 0, len(parents))
+	for i, p := range parents {
+		if i == 0 || p.ID != child.Parents[len(child.Parents)-1] {
+			child.Parents = append(child.Parents, p.ID)
+		}
+	}
+	child.Birth = generation
+	child.Mutations = nil
 }
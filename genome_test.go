@@ -45,3 +45,32 @@ func TestGenomeComplexity(t *testing.T) {
 		})
 	}
 }
+
+func TestSetLineage(t *testing.T) {
+
+	// A child cloned from its parent carries the parent's lineage until it is set
+	p1 := Genome{ID: 3, Parents: []int64{1}, Birth: 2, Mutations: []string{"weight"}}
+	p2 := Genome{ID: 4}
+	child := p1
+	child.ID = 9
+	SetLineage(&child, 5, p1, p2)
+
+	if len(child.Parents) != 2 || child.Parents[0] != 3 || child.Parents[1] != 4 {
+		t.Errorf("incorrect parents: expected [3 4], actual %v", child.Parents)
+	}
+	if child.Birth != 5 {
+		t.Errorf("incorrect birth: expected 5, actual %d", child.Birth)
+	}
+	if len(child.Mutations) != 0 {
+		t.Errorf("mutations should be cleared: actual %v", child.Mutations)
+	}
+	if len(p1.Parents) != 1 || p1.Parents[0] != 1 {
+		t.Errorf("parent's lineage should not change: actual %v", p1.Parents)
+	}
+
+	// A genome crossed with itself is a single parent
+	SetLineage(&child, 5, p2, p2)
+	if len(child.Parents) != 1 || child.Parents[0] != 4 {
+		t.Errorf("incorrect parents: expected [4], actual %v", child.Parents)
+	}
+}
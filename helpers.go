@@ -61,12 +61,48 @@ type Translator interface {
 	Translate(Substrate) (Network, error)
 }
 
+// The following helpers draw their random numbers from a stream provided by the experiment rather
+// than creating their own generator. When the seed is set with SetSeed, Run derives each stream
+// from the seed and the generation or child's ID so that the same seed reproduces the run
+// regardless of the number of CPUs. Helpers that do not implement these are called as usual.
+
+// RandomCrosser is a crosser that draws its random numbers from the stream
+type RandomCrosser interface {
+	CrossWith(rng Random, parents ...Genome) (child Genome, err error)
+}
+
+// RandomMutator is a mutator that draws its random numbers from the stream
+type RandomMutator interface {
+	MutateWith(rng Random, g *Genome) error
+}
+
+// RandomPopulator is a populator that draws its random numbers from the stream
+type RandomPopulator interface {
+	PopulateWith(rng Random) (Population, error)
+}
+
+// RandomSeeder is a seeder that draws its random numbers from the stream
+type RandomSeeder interface {
+	SeedWith(rng Random) (Genome, error)
+}
+
+// RandomSelector is a selector that draws its random numbers from the stream
+type RandomSelector interface {
+	SelectWith(rng Random, pop Population) (continuing []Genome, parents [][]Genome, err error)
+}
+
 // Mutators collection which acts as a single mutator. Component mutators will be called in order
 // until the complexity of the genome changes.
 type Mutators []Mutator
 
 // Mutate the genome with the composite mutators
 func (m Mutators) Mutate(g *Genome) error {
+	return m.MutateWith(NewRandom(), g)
+}
+
+// MutateWith mutates the genome with the composite mutators, passing the stream to those that
+// accept one
+func (m Mutators) MutateWith(rng Random, g *Genome) error {
 
 	// Record the starting complexity
 	n := g.Complexity()
@@ -75,7 +111,13 @@ func (m Mutators) Mutate(g *Genome) error {
 	for _, x := range m {
 
 		// Use the current mutator on the genome
-		if err := x.Mutate(g); err != nil {
+		var err error
+		if rx, ok := x.(RandomMutator); ok {
+			err = rx.MutateWith(rng, g)
+		} else {
+			err = x.Mutate(g)
+		}
+		if err != nil {
 			return err
 		}
 
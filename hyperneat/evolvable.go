@@ -0,0 +1,403 @@
+package hyperneat
+
+import (
+	"errors"
+	"math"
+	"sort"
+
+	"github.com/klokare/evo"
+	"gonum.org/v1/gonum/mat"
+)
+
+// Known errors
+var (
+	ErrHasHiddenNodes         = errors.New("evolvable substrate template should not have hidden nodes")
+	ErrInvalidDepth           = errors.New("evolvable substrate requires an initial depth greater than zero and a max depth no less than the initial depth")
+	ErrInputsNotBeforeOutputs = errors.New("evolvable substrate template inputs must be in layers before the outputs")
+)
+
+// EvolvableTranscriber decodes an encoded substrate using the evolvable-substrate method (ES-HyperNEAT)
+// described by Risi and Stanley. Rather than connecting fixed template layers, the positions of
+// hidden nodes are discovered by recursively dividing the X-Y plane into a quadtree and keeping the
+// points where the Cppn's weight pattern has high variance. Hidden nodes discovered in each
+// iteration are placed on their own layer between the inputs and outputs so the decoded substrate
+// remains acyclic and can be consumed by the forward translator.
+type EvolvableTranscriber struct {
+
+	// Helpers
+	CppnTranscriber evo.Transcriber
+	CppnTranslator  evo.Translator
+	Inspector       // used to determine the weight of a connection and if it will be expressed. Optional.
+
+	// Properties
+	WeightPower       float64        // Power by which to multiply the weight's output value
+	BiasPower         float64        // Power by which to multiply the bias's output value
+	InitialDepth      int            // Quadtree depth to which the plane is always divided
+	MaxDepth          int            // Maximum quadtree depth
+	DivisionThreshold float64        // Variance above which a quadtree point is further divided
+	VarianceThreshold float64        // Variance above which a quadtree point's children are examined during extraction
+	BandThreshold     float64        // Minimum band value for a point to be expressed as a connection
+	IterationLevel    int            // Number of hidden-to-hidden iterations. Each adds another hidden layer.
+	HiddenActivation  evo.Activation // Activation for the discovered hidden nodes
+	Adaptive          bool           // Set each connection's learning rule from the Cppn, as in adaptive HyperNEAT
+	RatePower         float64        // Power by which to multiply the learning rate's output value
+	DisableSortCheck  bool           // Speed things up by disabling sort check on the template
+
+	// Internal structure
+	inputs, outputs []evo.Node
+}
+
+// SetTemplate sets the input and output nodes of the substrate. Hidden nodes are not allowed as they
+// will be discovered during transcription.
+func (t *EvolvableTranscriber) SetTemplate(tmpl evo.Substrate) (err error) {
+
+	// Check for errors
+	if len(tmpl.Conns) > 0 {
+		return ErrHasExistingConns
+	}
+
+	// Ensure the template is sorted
+	if !t.DisableSortCheck {
+		sort.Slice(tmpl.Nodes, func(i, j int) bool { return tmpl.Nodes[i].Compare(tmpl.Nodes[j]) < 0 })
+	}
+
+	// Separate the inputs and outputs
+	t.inputs = make([]evo.Node, 0, len(tmpl.Nodes))
+	t.outputs = make([]evo.Node, 0, len(tmpl.Nodes))
+	for _, n := range tmpl.Nodes {
+		switch n.Neuron {
+		case evo.Input:
+			t.inputs = append(t.inputs, n)
+		case evo.Output:
+			t.outputs = append(t.outputs, n)
+		default:
+			return ErrHasHiddenNodes
+		}
+	}
+
+	// Check for errors
+	if len(t.inputs) == 0 {
+		return ErrNoInputNodes
+	} else if len(t.outputs) == 0 {
+		return ErrNoOutputNodes
+	} else if t.inputs[len(t.inputs)-1].Layer >= t.outputs[0].Layer {
+		return ErrInputsNotBeforeOutputs
+	}
+	return
+}
+
+// Transcribe creates a cppn network from the encoded substrate and then uses it to discover the
+// hidden nodes and connections of the decoded substrate.
+func (t EvolvableTranscriber) Transcribe(enc evo.Substrate) (dec evo.Substrate, err error) {
+
+	// Check for known errors
+	if len(t.inputs) == 0 {
+		err = ErrTemplateNotSet
+		return
+	} else if t.WeightPower == 0.0 {
+		err = ErrInvalidWeightPower
+		return
+	} else if t.InitialDepth < 1 || t.MaxDepth < t.InitialDepth {
+		err = ErrInvalidDepth
+		return
+	} else if t.CppnTranscriber == nil {
+		err = ErrMissingCppnTranscriber
+		return
+	} else if t.CppnTranslator == nil {
+		err = ErrMissingCppnTranslator
+		return
+	}
+
+	// Transcribe the encoded substrate and translate into a Cppn
+	var net evo.Network
+	if dec, err = t.CppnTranscriber.Transcribe(enc); err != nil {
+		return
+	}
+	if net, err = t.CppnTranslator.Translate(dec); err != nil {
+		return
+	}
+
+	// Determine the layers of the hidden nodes. These are evenly spaced between the inputs and outputs.
+	n := t.IterationLevel + 1
+	lo, hi := t.inputs[len(t.inputs)-1].Layer, t.outputs[0].Layer
+	layers := make([]float64, n)
+	for i := 0; i < n; i++ {
+		layers[i] = lo + (hi-lo)*float64(i+1)/float64(n+1)
+	}
+
+	// Discover the hidden nodes, starting from the inputs and then exploring from each new layer of
+	// hidden nodes
+	hidden := make(map[evo.Position]bool, 100)
+	conns := make([]evo.Conn, 0, 100)
+	frontier := make([]evo.Position, len(t.inputs))
+	for i, node := range t.inputs {
+		frontier[i] = node.Position
+	}
+	for _, layer := range layers {
+		next := make([]evo.Position, 0, len(frontier))
+		for _, src := range frontier {
+			var cs []evo.Conn
+			if cs, err = t.search(net, src, layer, true); err != nil {
+				return
+			}
+			for _, c := range cs {
+				conns = append(conns, c)
+				if !hidden[c.Target] {
+					hidden[c.Target] = true
+					next = append(next, c.Target)
+				}
+			}
+		}
+		frontier = next
+	}
+
+	// Connect the outputs to the discovered hidden nodes
+	for _, tgt := range t.outputs {
+		for _, layer := range layers {
+			var cs []evo.Conn
+			if cs, err = t.search(net, tgt.Position, layer, false); err != nil {
+				return
+			}
+			for _, c := range cs {
+				if hidden[c.Source] {
+					conns = append(conns, c)
+				}
+			}
+		}
+	}
+
+	// Remove the hidden nodes, and their connections, that do not lie on a path from input to output
+	conns = prune(conns, t.inputs, t.outputs)
+	keep := make(map[evo.Position]bool, len(hidden))
+	for _, c := range conns {
+		keep[c.Source] = true
+		keep[c.Target] = true
+	}
+
+	// Create the decoded substrate
+	dec = evo.Substrate{
+		Nodes: make([]evo.Node, 0, len(t.inputs)+len(t.outputs)+len(keep)),
+		Conns: conns,
+	}
+	dec.Nodes = append(dec.Nodes, t.inputs...)
+	dec.Nodes = append(dec.Nodes, t.outputs...)
+	for p := range hidden {
+		if keep[p] {
+			dec.Nodes = append(dec.Nodes, evo.Node{Position: p, Neuron: evo.Hidden, Activation: t.HiddenActivation})
+		}
+	}
+	sort.Slice(dec.Nodes, func(i, j int) bool { return dec.Nodes[i].Compare(dec.Nodes[j]) < 0 })
+
+	// Set bias values for hidden and output nodes
+	row := make([]float64, 8)
+	inputs := mat.NewDense(len(dec.Nodes)-len(t.inputs), 8, nil)
+	r := 0
+	for _, node := range dec.Nodes {
+		if node.Neuron == evo.Input {
+			continue
+		}
+		row[0], row[1], row[2], row[3] = node.Layer, node.X, node.Y, node.Z
+		inputs.SetRow(r, row)
+		r++
+	}
+	var outputs evo.Matrix
+	if outputs, err = net.Activate(inputs); err != nil {
+		return
+	}
+	r = 0
+	for i, node := range dec.Nodes {
+		if node.Neuron == evo.Input {
+			continue
+		}
+		dec.Nodes[i].Bias = outputs.At(r, Bias) * t.BiasPower
+		r++
+	}
+
+	// Return the decoded substrate
+	sort.Slice(dec.Conns, func(i, j int) bool { return dec.Conns[i].Compare(dec.Conns[j]) < 0 })
+	return
+}
+
+// A quadPoint is a square region of the X-Y plane in the quadtree
+type quadPoint struct {
+	x, y, width float64
+	level       int
+	weight      float64      // the Cppn's weight output at the centre of this point
+	outputs     evo.Matrix   // the Cppn's outputs for this point's query
+	row         int          // the row of this point in the outputs
+	children    []*quadPoint // the subdivisions of this point, if any
+}
+
+// variance of the weights of the point's leaves
+func (p *quadPoint) variance() float64 {
+	if len(p.children) == 0 {
+		return 0.0
+	}
+	ws := p.leaves(make([]float64, 0, 4))
+	var m, v float64
+	for _, w := range ws {
+		m += w
+	}
+	m /= float64(len(ws))
+	for _, w := range ws {
+		v += (w - m) * (w - m)
+	}
+	return v / float64(len(ws))
+}
+
+// leaves returns the weights of the point's leaves
+func (p *quadPoint) leaves(ws []float64) []float64 {
+	if len(p.children) == 0 {
+		return append(ws, p.weight)
+	}
+	for _, c := range p.children {
+		ws = c.leaves(ws)
+	}
+	return ws
+}
+
+// search for the connections between the node and points on the layer. If outgoing is true, the
+// node is the source of the connections; otherwise it is the target.
+func (t EvolvableTranscriber) search(net evo.Network, node evo.Position, layer float64, outgoing bool) (conns []evo.Conn, err error) {
+
+	// Query the Cppn for the weights at the given coordinates
+	query := func(xs, ys []float64) (outputs evo.Matrix, err error) {
+		inputs := mat.NewDense(len(xs), 8, nil)
+		for i := range xs {
+			a := []float64{node.Layer, node.X, node.Y, node.Z}
+			b := []float64{layer, xs[i], ys[i], 0.0}
+			if !outgoing {
+				a, b = b, a
+			}
+			inputs.SetRow(i, append(a, b...))
+		}
+		return net.Activate(inputs)
+	}
+
+	// Divide the plane and initialise the quadtree
+	root := &quadPoint{x: 0.0, y: 0.0, width: 1.0, level: 1}
+	queue := []*quadPoint{root}
+	for len(queue) > 0 {
+		p := queue[0]
+		queue = queue[1:]
+
+		// Create the children
+		hw := p.width / 2.0
+		xs := []float64{p.x - hw, p.x - hw, p.x + hw, p.x + hw}
+		ys := []float64{p.y - hw, p.y + hw, p.y - hw, p.y + hw}
+		var outputs evo.Matrix
+		if outputs, err = query(xs, ys); err != nil {
+			return
+		}
+		p.children = make([]*quadPoint, 4)
+		for i := range p.children {
+			p.children[i] = &quadPoint{
+				x: xs[i], y: ys[i], width: hw, level: p.level + 1,
+				weight:  outputs.At(i, Weight),
+				outputs: outputs,
+				row:     i,
+			}
+		}
+
+		// Continue dividing to the initial depth or while there is variance
+		if p.level < t.InitialDepth || (p.level < t.MaxDepth && p.variance() > t.DivisionThreshold) {
+			queue = append(queue, p.children...)
+		}
+	}
+
+	// Prune and extract the connections
+	err = t.extract(root, query, func(c *quadPoint) {
+		w, e := c.outputs.At(c.row, Weight)*t.WeightPower, 1.0
+		if t.Inspector != nil {
+			w, e = t.WeightAndExpression(c.outputs, c.row, t.WeightPower)
+		}
+		if e <= 0 {
+			return
+		}
+		pos := evo.Position{Layer: layer, X: c.x, Y: c.y}
+		conn := evo.Conn{Source: pos, Target: node, Weight: w, Enabled: true}
+		if outgoing {
+			conn.Source, conn.Target = node, pos
+		}
+		if t.Adaptive {
+			conn.Hebbian = LearningRule(c.outputs, c.row, t.RatePower)
+		}
+		conns = append(conns, conn)
+	})
+	return
+}
+
+// extract the points from the quadtree whose variance is low enough and which lie within a band of
+// differing weights.
+func (t EvolvableTranscriber) extract(p *quadPoint, query func(xs, ys []float64) (evo.Matrix, error), emit func(*quadPoint)) (err error) {
+	for _, c := range p.children {
+
+		// Examine the children of high variance points
+		if len(c.children) > 0 && c.variance() >= t.VarianceThreshold {
+			if err = t.extract(c, query, emit); err != nil {
+				return
+			}
+			continue
+		}
+
+		// Determine if the point is in a band
+		var outputs evo.Matrix
+		xs := []float64{c.x - p.width, c.x + p.width, c.x, c.x}
+		ys := []float64{c.y, c.y, c.y - p.width, c.y + p.width}
+		if outputs, err = query(xs, ys); err != nil {
+			return
+		}
+		var d [4]float64
+		for i := range d {
+			d[i] = math.Abs(c.weight - outputs.At(i, Weight))
+		}
+		if band := math.Max(math.Min(d[0], d[1]), math.Min(d[2], d[3])); band > t.BandThreshold {
+			emit(c)
+		}
+	}
+	return
+}
+
+// prune the connections that do not lie on a path from an input to an output
+func prune(conns []evo.Conn, inputs, outputs []evo.Node) []evo.Conn {
+
+	// Map the connections in both directions
+	fwd := make(map[evo.Position][]evo.Position, len(conns))
+	bwd := make(map[evo.Position][]evo.Position, len(conns))
+	for _, c := range conns {
+		fwd[c.Source] = append(fwd[c.Source], c.Target)
+		bwd[c.Target] = append(bwd[c.Target], c.Source)
+	}
+
+	// Identify the positions reachable from the inputs and those that reach the outputs
+	reach := func(start []evo.Node, edges map[evo.Position][]evo.Position) map[evo.Position]bool {
+		seen := make(map[evo.Position]bool, len(edges))
+		queue := make([]evo.Position, 0, len(start))
+		for _, n := range start {
+			seen[n.Position] = true
+			queue = append(queue, n.Position)
+		}
+		for len(queue) > 0 {
+			p := queue[0]
+			queue = queue[1:]
+			for _, q := range edges[p] {
+				if !seen[q] {
+					seen[q] = true
+					queue = append(queue, q)
+				}
+			}
+		}
+		return seen
+	}
+	a := reach(inputs, fwd)
+	b := reach(outputs, bwd)
+
+	// Keep only those connections on a path
+	kept := make([]evo.Conn, 0, len(conns))
+	for _, c := range conns {
+		if a[c.Source] && b[c.Source] && a[c.Target] && b[c.Target] {
+			kept = append(kept, c)
+		}
+	}
+	return kept
+}
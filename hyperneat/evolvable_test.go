@@ -0,0 +1,179 @@
+package hyperneat
+
+import (
+	"testing"
+
+	"github.com/klokare/evo"
+	"github.com/klokare/evo/neat"
+	"github.com/klokare/evo/network/forward"
+)
+
+func TestEvolvableTranscriberSetTemplate(t *testing.T) {
+	var cases = []struct {
+		Desc     string
+		HasError bool
+		Template evo.Substrate
+	}{
+		{
+			Desc:     "existing connections",
+			HasError: true,
+			Template: evo.Substrate{
+				Nodes: []evo.Node{{Neuron: evo.Input}, {Position: evo.Position{Layer: 1.0}, Neuron: evo.Output}},
+				Conns: []evo.Conn{{Target: evo.Position{Layer: 1.0}}},
+			},
+		},
+		{
+			Desc:     "hidden nodes",
+			HasError: true,
+			Template: evo.Substrate{Nodes: []evo.Node{{Neuron: evo.Input}, {Position: evo.Position{Layer: 0.5}, Neuron: evo.Hidden}, {Position: evo.Position{Layer: 1.0}, Neuron: evo.Output}}},
+		},
+		{
+			Desc:     "no inputs",
+			HasError: true,
+			Template: evo.Substrate{Nodes: []evo.Node{{Position: evo.Position{Layer: 1.0}, Neuron: evo.Output}}},
+		},
+		{
+			Desc:     "no outputs",
+			HasError: true,
+			Template: evo.Substrate{Nodes: []evo.Node{{Neuron: evo.Input}}},
+		},
+		{
+			Desc:     "inputs not before outputs",
+			HasError: true,
+			Template: evo.Substrate{Nodes: []evo.Node{{Neuron: evo.Input}, {Neuron: evo.Output, Position: evo.Position{X: 1.0}}}},
+		},
+		{
+			Desc:     "valid template",
+			HasError: false,
+			Template: evo.Substrate{Nodes: []evo.Node{{Neuron: evo.Input}, {Position: evo.Position{Layer: 1.0}, Neuron: evo.Output}}},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Desc, func(t *testing.T) {
+			err := (&EvolvableTranscriber{}).SetTemplate(c.Template)
+			if c.HasError && err == nil {
+				t.Error("expected error not found")
+			} else if !c.HasError && err != nil {
+				t.Errorf("error not expected: %v", err)
+			}
+		})
+	}
+}
+
+func TestEvolvableTranscriberTranscribe(t *testing.T) {
+
+	// Create a Cppn whose weight output varies with the source and target X coordinates
+	var in [8]evo.Position
+	for i := range in {
+		in[i] = evo.Position{Layer: 0.0, X: float64(i) / 7.0}
+	}
+	wout := evo.Position{Layer: 1.0, X: 0.0}
+	enc := evo.Substrate{
+		Nodes: []evo.Node{
+			{Position: wout, Neuron: evo.Output, Activation: evo.Direct},
+			{Position: evo.Position{Layer: 1.0, X: 0.5}, Neuron: evo.Output, Activation: evo.Direct},
+			{Position: evo.Position{Layer: 1.0, X: 1.0}, Neuron: evo.Output, Activation: evo.Direct},
+		},
+		Conns: []evo.Conn{
+			{Source: in[1], Target: wout, Weight: 1.0, Enabled: true}, // source X
+			{Source: in[5], Target: wout, Weight: 1.0, Enabled: true}, // target X
+		},
+	}
+	for _, p := range in {
+		enc.Nodes = append(enc.Nodes, evo.Node{Position: p, Neuron: evo.Input, Activation: evo.Direct})
+	}
+
+	// Create the transcriber
+	tr := &EvolvableTranscriber{
+		CppnTranscriber:   neat.Transcriber{},
+		CppnTranslator:    forward.Translator{},
+		WeightPower:       1.0,
+		BiasPower:         1.0,
+		InitialDepth:      2,
+		MaxDepth:          2,
+		VarianceThreshold: 1e9, // never look deeper than the root's children
+		BandThreshold:     0.01,
+		HiddenActivation:  evo.Sigmoid,
+	}
+
+	// Transcribing without a template is an error
+	if _, err := tr.Transcribe(enc); err == nil {
+		t.Error("expected error for missing template not found")
+	}
+
+	// Set the template with 2 inputs and 1 output
+	err := tr.SetTemplate(evo.Substrate{
+		Nodes: []evo.Node{
+			{Position: evo.Position{Layer: 0.0, X: -1.0}, Neuron: evo.Input, Activation: evo.Direct},
+			{Position: evo.Position{Layer: 0.0, X: 1.0}, Neuron: evo.Input, Activation: evo.Direct},
+			{Position: evo.Position{Layer: 1.0, X: 0.0}, Neuron: evo.Output, Activation: evo.Sigmoid},
+		},
+	})
+	if err != nil {
+		t.Fatalf("error not expected: %v", err)
+	}
+
+	// Transcribe the Cppn. There should be 4 hidden nodes, one in each quadrant, fully connected to
+	// the inputs and output.
+	dec, err := tr.Transcribe(enc)
+	if err != nil {
+		t.Fatalf("error not expected: %v", err)
+	}
+	if len(dec.Nodes) != 7 {
+		t.Errorf("incorrect number of nodes: expected 7, actual %d", len(dec.Nodes))
+	}
+	if len(dec.Conns) != 12 {
+		t.Errorf("incorrect number of conns: expected 12, actual %d", len(dec.Conns))
+	}
+	for _, n := range dec.Nodes {
+		if n.Neuron != evo.Hidden {
+			continue
+		}
+		if n.Layer != 0.5 || (n.X != 0.5 && n.X != -0.5) || (n.Y != 0.5 && n.Y != -0.5) {
+			t.Errorf("incorrect hidden node position: %v", n.Position)
+		}
+		if n.Activation != evo.Sigmoid {
+			t.Errorf("incorrect hidden activation: expected %v, actual %v", evo.Sigmoid, n.Activation)
+		}
+	}
+
+	// The decoded substrate should be usable by the forward translator
+	if _, err = (forward.Translator{}).Translate(dec); err != nil {
+		t.Errorf("error not expected translating decoded substrate: %v", err)
+	}
+
+	// An invalid depth is an error
+	tr.InitialDepth = 0
+	if _, err = tr.Transcribe(enc); err == nil {
+		t.Error("expected error for invalid depth not found")
+	}
+}
+
+func TestEvolvablePrune(t *testing.T) {
+	var (
+		in   = evo.Node{Position: evo.Position{Layer: 0.0}, Neuron: evo.Input}
+		out  = evo.Node{Position: evo.Position{Layer: 1.0}, Neuron: evo.Output}
+		h1   = evo.Position{Layer: 0.5, X: -0.5}
+		h2   = evo.Position{Layer: 0.5, X: 0.5}
+		h3   = evo.Position{Layer: 0.75, X: 0.5}
+		h4   = evo.Position{Layer: 0.25, X: 0.5}
+		conn = func(a, b evo.Position) evo.Conn { return evo.Conn{Source: a, Target: b, Enabled: true} }
+	)
+
+	// h1 is on a path, h2 and h3 do not reach the output, and h4 is not reachable from the input
+	conns := prune([]evo.Conn{
+		conn(in.Position, h1), conn(h1, out.Position),
+		conn(in.Position, h2), conn(h2, h3),
+		conn(h4, out.Position),
+	}, []evo.Node{in}, []evo.Node{out})
+
+	if len(conns) != 2 {
+		t.Fatalf("incorrect number of conns: expected 2, actual %d", len(conns))
+	}
+	for _, c := range conns {
+		if c.Source != h1 && c.Target != h1 {
+			t.Errorf("unexpected connection kept: %v", c)
+		}
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/klokare/evo/neat"
 	"github.com/klokare/evo/neat/mutator"
 	"github.com/klokare/evo/network/forward"
+	"github.com/klokare/evo/network/plastic"
 )
 
 // Ensure the experiment struct implements the experiment interface
@@ -18,6 +19,28 @@ type Experiment struct {
 	neat.Experiment
 	Transcriber
 	Seeder
+
+	// Evolvable substrate (ES-HyperNEAT) transcriber. Optional. If set, it is used in place of the
+	// embedded transcriber.
+	Evolvable *EvolvableTranscriber
+}
+
+// Transcribe the encoded substrate using the evolvable substrate transcriber, if set, or the
+// HyperNEAT transcriber
+func (e *Experiment) Transcribe(enc evo.Substrate) (evo.Substrate, error) {
+	if e.Evolvable != nil {
+		return e.Evolvable.Transcribe(enc)
+	}
+	return e.Transcriber.Transcribe(enc)
+}
+
+// SetTemplate sets the template of the evolvable substrate transcriber, if set, or the HyperNEAT
+// transcriber
+func (e *Experiment) SetTemplate(tmpl evo.Substrate) error {
+	if e.Evolvable != nil {
+		return e.Evolvable.SetTemplate(tmpl)
+	}
+	return e.Transcriber.SetTemplate(tmpl)
 }
 
 // NewExperiment creates a new Hyper-NEAT experiment using the configuration. Configurations employ the
@@ -28,6 +51,10 @@ func NewExperiment(cfg config.Configurer) (exp *Experiment) {
 	// Create the HyperNEAT experiment
 	exp = new(Experiment)
 	exp.Experiment = *neat.NewExperiment(cfg) // backfill with the NEAT helpers
+	cfg.Declare(Keys...)                      // after the NEAT keys as some are replaced
+	if exp.Phased != nil {
+		exp.Phased.Selector = &exp.Experiment.Selector // the NEAT experiment's selector was copied
+	}
 	exp.Experiment.Populator = neat.Populator{
 		Seeder: Seeder{
 			NumTraits:         cfg.Int("neat|seeder|num-traits"),
@@ -36,6 +63,7 @@ func NewExperiment(cfg config.Configurer) (exp *Experiment) {
 			SeedLocalityX:     cfg.Bool("hyperneat|transcriber|seed-locality-x"),
 			SeedLocalityY:     cfg.Bool("hyperneat|transcriber|seed-locality-y"),
 			SeedLocalityZ:     cfg.Bool("hyperneat|transcriber|seed-locality-z"),
+			Adaptive:          cfg.Bool("hyperneat|transcriber|adaptive"),
 		},
 		PopulationSize: cfg.Int("neat|populator|population-size"),
 		WeightPower:    cfg.Float64("neat|populator|weight-power"),
@@ -44,14 +72,44 @@ func NewExperiment(cfg config.Configurer) (exp *Experiment) {
 		MaxBias:        cfg.Float64("neat|populator|max-bias"),
 	}
 
+	// Set the transcriber, adding the evolvable substrate (ES-HyperNEAT) version if configured
 	exp.Transcriber = Transcriber{
 		CppnTranscriber:  neat.Transcriber{DisableSortCheck: cfg.Bool("hyperneat|transcriber|cppn-transcriber|disable-sort-check")},
 		CppnTranslator:   forward.Translator{DisableSortCheck: cfg.Bool("forward|translator|disable-sort-check")},
 		Inspector:        LinkExpressionOutput{},
 		WeightPower:      cfg.Float64("hyperneat|transcriber|weight-power"),
 		BiasPower:        cfg.Float64("hyperneat|transcriber|bias-power"),
+		Adaptive:         cfg.Bool("hyperneat|transcriber|adaptive"),
+		RatePower:        cfg.Float64("hyperneat|transcriber|rate-power"),
 		DisableSortCheck: cfg.Bool("hyperneat|transcriber|disable-sort-check"),
 	}
+	if cfg.Bool("hyperneat|transcriber|evolvable-substrate") {
+		exp.Evolvable = &EvolvableTranscriber{
+			CppnTranscriber:   neat.Transcriber{DisableSortCheck: cfg.Bool("hyperneat|transcriber|cppn-transcriber|disable-sort-check")},
+			CppnTranslator:    forward.Translator{DisableSortCheck: cfg.Bool("forward|translator|disable-sort-check")},
+			Inspector:         LinkExpressionOutput{},
+			WeightPower:       cfg.Float64("hyperneat|transcriber|weight-power"),
+			BiasPower:         cfg.Float64("hyperneat|transcriber|bias-power"),
+			InitialDepth:      cfg.Int("hyperneat|transcriber|initial-depth"),
+			MaxDepth:          cfg.Int("hyperneat|transcriber|max-depth"),
+			DivisionThreshold: cfg.Float64("hyperneat|transcriber|division-threshold"),
+			VarianceThreshold: cfg.Float64("hyperneat|transcriber|variance-threshold"),
+			BandThreshold:     cfg.Float64("hyperneat|transcriber|band-threshold"),
+			IterationLevel:    cfg.Int("hyperneat|transcriber|iteration-level"),
+			HiddenActivation:  cfg.Activation("hyperneat|transcriber|hidden-activation"),
+			Adaptive:          cfg.Bool("hyperneat|transcriber|adaptive"),
+			RatePower:         cfg.Float64("hyperneat|transcriber|rate-power"),
+			DisableSortCheck:  cfg.Bool("hyperneat|transcriber|disable-sort-check"),
+		}
+	}
+
+	// In adaptive HyperNEAT, the Cppn also provides the learning rules used by plastic networks
+	if cfg.Bool("hyperneat|transcriber|adaptive") {
+		exp.Translator = plastic.Translator{
+			MaxWeight:        cfg.Float64("plastic|translator|max-weight"),
+			DisableSortCheck: cfg.Bool("plastic|translator|disable-sort-check"),
+		}
+	}
 
 	// Add additional mutator for activations
 	am := mutator.Activation{
@@ -0,0 +1,93 @@
+package hyperneat
+
+import (
+	"testing"
+
+	"github.com/klokare/evo"
+	"github.com/klokare/evo/config"
+	"github.com/klokare/evo/config/source"
+)
+
+func TestExperimentPhasedSelector(t *testing.T) {
+
+	// Configure phased search which puts the selector in mutate only mode once fitness stagnates
+	cfg := config.Configurer{Source: source.Map{
+		"neat": map[string]interface{}{
+			"phased": map[string]interface{}{
+				"stagnation-limit": 1,
+			},
+			"selector": map[string]interface{}{
+				"mutate-only-probability": 0.25,
+			},
+		},
+	}}
+	exp := NewExperiment(cfg)
+	if exp.Phased == nil {
+		t.Fatal("phased search not configured")
+	}
+
+	// The first population sets the floor and the second stagnates
+	pop := evo.Population{Genomes: []evo.Genome{{ID: 1, Fitness: 1.0}}}
+	for i := 0; i < 2; i++ {
+		if err := exp.Phased.Update(pop); err != nil {
+			t.Fatalf("error not expected: %v", err)
+		}
+	}
+	if !exp.Phased.Simplifying() {
+		t.Fatal("phased search should be simplifying")
+	}
+
+	// The experiment's own selector is in mutate only mode
+	if x := exp.Selector.MutateOnlyProbability; x != 1.0 {
+		t.Errorf("incorrect mutate only probability: expected 1.0, actual %f", x)
+	}
+}
+
+func TestExperimentEvolvable(t *testing.T) {
+
+	// A template with hidden nodes is accepted by the HyperNEAT transcriber but not by the evolvable
+	// substrate transcriber
+	tmpl := evo.Substrate{
+		Nodes: []evo.Node{
+			{Position: evo.Position{Layer: 0.0}, Neuron: evo.Input},
+			{Position: evo.Position{Layer: 0.5}, Neuron: evo.Hidden},
+			{Position: evo.Position{Layer: 1.0}, Neuron: evo.Output},
+		},
+	}
+
+	var cases = []struct {
+		Desc     string
+		Config   source.Map
+		Expected error
+	}{
+		{
+			Desc:   "hyperneat transcriber",
+			Config: source.Map{},
+		},
+		{
+			Desc: "evolvable substrate transcriber",
+			Config: source.Map{
+				"hyperneat": map[string]interface{}{
+					"transcriber": map[string]interface{}{
+						"evolvable-substrate": true,
+					},
+				},
+			},
+			Expected: ErrHasHiddenNodes,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Desc, func(t *testing.T) {
+			exp := NewExperiment(config.Configurer{Source: c.Config})
+			if err := exp.SetTemplate(tmpl); err != c.Expected {
+				t.Errorf("incorrect error: expected %v, actual %v", c.Expected, err)
+			}
+
+			// The embedded transcriber remains available
+			if err := exp.Transcriber.SetTemplate(tmpl); err != nil {
+				t.Errorf("error not expected from the embedded transcriber: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,34 @@
+package hyperneat
+
+import (
+	"math"
+
+	"github.com/klokare/evo/config"
+)
+
+// Keys used by the HyperNEAT experiment in addition to those of the NEAT experiment. The number of
+// inputs and outputs comes from the substrate template so those NEAT keys are no longer required.
+var Keys = []config.Key{
+	{Name: "neat|seeder|num-inputs", Type: config.Int, Min: 0.0, Max: math.Inf(1), Description: "Not used. The CPPN's inputs are set by the transcriber."},
+	{Name: "neat|seeder|num-outputs", Type: config.Int, Min: 0.0, Max: math.Inf(1), Description: "Not used. The CPPN's outputs are set by the transcriber."},
+	{Name: "hyperneat|transcriber|seed-locality-layer", Type: config.Bool, Description: "Seed the CPPN with a locality-seeking connection on the layer axis"},
+	{Name: "hyperneat|transcriber|seed-locality-x", Type: config.Bool, Description: "Seed the CPPN with a locality-seeking connection on the x axis"},
+	{Name: "hyperneat|transcriber|seed-locality-y", Type: config.Bool, Description: "Seed the CPPN with a locality-seeking connection on the y axis"},
+	{Name: "hyperneat|transcriber|seed-locality-z", Type: config.Bool, Description: "Seed the CPPN with a locality-seeking connection on the z axis"},
+	{Name: "hyperneat|transcriber|evolvable-substrate", Type: config.Bool, Description: "Use the evolvable substrate (ES-HyperNEAT) transcriber"},
+	{Name: "hyperneat|transcriber|cppn-transcriber|disable-sort-check", Type: config.Bool, Description: "Skip sorting the CPPN's genes before transcribing"},
+	{Name: "hyperneat|transcriber|weight-power", Type: config.Float64, Min: 0.0, Max: math.Inf(1), Description: "Scale of the connection weights produced by the CPPN"},
+	{Name: "hyperneat|transcriber|bias-power", Type: config.Float64, Min: 0.0, Max: math.Inf(1), Description: "Scale of the node biases produced by the CPPN"},
+	{Name: "hyperneat|transcriber|initial-depth", Type: config.Int, Min: 0.0, Max: math.Inf(1), Description: "Quadtree depth to which the plane is always divided"},
+	{Name: "hyperneat|transcriber|max-depth", Type: config.Int, Min: 0.0, Max: math.Inf(1), Description: "Maximum quadtree depth"},
+	{Name: "hyperneat|transcriber|division-threshold", Type: config.Float64, Min: 0.0, Max: math.Inf(1), Description: "Variance above which a quadtree point is further divided"},
+	{Name: "hyperneat|transcriber|variance-threshold", Type: config.Float64, Min: 0.0, Max: math.Inf(1), Description: "Variance above which a quadtree point's children are examined during extraction"},
+	{Name: "hyperneat|transcriber|band-threshold", Type: config.Float64, Min: 0.0, Max: math.Inf(1), Description: "Minimum band value for a point to be expressed as a connection"},
+	{Name: "hyperneat|transcriber|iteration-level", Type: config.Int, Min: 0.0, Max: math.Inf(1), Description: "Number of hidden-to-hidden iterations"},
+	{Name: "hyperneat|transcriber|hidden-activation", Type: config.Activation, Description: "Activation of the evolvable substrate's hidden nodes"},
+	{Name: "hyperneat|transcriber|adaptive", Type: config.Bool, Description: "Produce learning rules from the CPPN for plastic networks"},
+	{Name: "hyperneat|transcriber|rate-power", Type: config.Float64, Min: 0.0, Max: math.Inf(1), Description: "Scale of the learning rates produced by the CPPN"},
+	{Name: "hyperneat|transcriber|disable-sort-check", Type: config.Bool, Description: "Skip sorting the substrate before transcribing"},
+	{Name: "neat|mutator|activation|replace-activation-probability", Type: config.Float64, Min: 0.0, Max: 1.0, Description: "Probability that a node's activation is replaced"},
+	{Name: "neat|mutator|activation|mutate-activations", Type: config.Activations, Description: "Activations from which a replacement is chosen"},
+}
@@ -12,11 +12,18 @@ const (
 	Weight int = iota
 	Bias
 	LEO
+	HebbianA // The learning rule's outputs are used only in adaptive HyperNEAT
+	HebbianB
+	HebbianC
+	HebbianD
+	LearningRate
 )
 
 // Seeder creates the seed population geared towards Cppns. Each encoded substrate will have 8
 // inputs, one for each dimension of the source and target nodes, and 3 ouputs: output weight,
 // output enabled check (LEO), and bias value. The bias is used for hidden and output nodes only.
+// For adaptive HyperNEAT, 5 more outputs provide the coefficients and rate of each connection's
+// learning rule.
 type Seeder struct {
 	NumTraits         int
 	DisconnectRate    float64
@@ -25,21 +32,31 @@ type Seeder struct {
 	SeedLocalityX     bool
 	SeedLocalityY     bool
 	SeedLocalityZ     bool
+	Adaptive          bool // Add the learning rule's outputs
 }
 
 // Seed returns the seed genome for a HyperNEAT setup. If SeedLocality<Dim> is set to true then a
 // node is added and connected to the appropriate inputs and the LEO output.
 func (s Seeder) Seed() (g evo.Genome, err error) {
+	return s.SeedWith(evo.NewRandom())
+}
+
+// SeedWith returns the seed genome for a HyperNEAT setup, drawing from the random stream to
+// disconnect sensors
+func (s Seeder) SeedWith(rng evo.Random) (g evo.Genome, err error) {
 
 	// Create the seed genome using the NEAT seeder
 	ns := neat.Seeder{
 		NumInputs:        8,
-		NumOutputs:       3,
+		NumOutputs:       LEO + 1,
 		NumTraits:        s.NumTraits,
 		DisconnectRate:   s.DisconnectRate,
 		OutputActivation: evo.InverseAbs, // need a function that gives us [-1,1].
 	}
-	if g, err = ns.Seed(); err != nil {
+	if s.Adaptive {
+		ns.NumOutputs = LearningRate + 1
+	}
+	if g, err = ns.SeedWith(rng); err != nil {
 		return
 	}
 
@@ -70,8 +87,9 @@ func (s Seeder) Seed() (g evo.Genome, err error) {
 		// Create the new node
 		x0 := enc.Nodes[i].Position
 		x1 := enc.Nodes[i+4].Position
+		leo := enc.Nodes[8+LEO].Position
 		n := evo.Node{
-			Position:   evo.Midpoint(evo.Midpoint(x0, x1), enc.Nodes[10].Position),
+			Position:   evo.Midpoint(evo.Midpoint(x0, x1), leo),
 			Neuron:     evo.Hidden,
 			Activation: evo.Gauss,
 		}
@@ -91,7 +109,7 @@ func (s Seeder) Seed() (g evo.Genome, err error) {
 			},
 			evo.Conn{
 				Source:  n.Position,
-				Target:  evo.Position{Layer: 1.0, X: 1.0},
+				Target:  leo,
 				Enabled: true,
 			},
 		)
@@ -225,3 +225,57 @@ func TestSeederSeed(t *testing.T) {
 		})
 	}
 }
+
+func TestSeederAdaptive(t *testing.T) {
+
+	// The adaptive seeder adds the learning rule's outputs
+	s := &Seeder{Adaptive: true, SeedLocalityX: true}
+	g, err := s.Seed()
+	if err != nil {
+		t.Fatalf("error not expected: %v", err)
+	}
+	var outputs []evo.Node
+	for _, n := range g.Encoded.Nodes {
+		if n.Neuron == evo.Output {
+			outputs = append(outputs, n)
+		}
+	}
+	if len(outputs) != LearningRate+1 {
+		t.Fatalf("incorrect number of outputs: expected %d, actual %d", LearningRate+1, len(outputs))
+	}
+
+	// Locality is still connected to the LEO output
+	leo := outputs[LEO].Position
+	found := false
+	for _, c := range g.Encoded.Conns {
+		if c.Target == leo {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("locality node not connected to the LEO output at %v", leo)
+	}
+}
+
+func TestSeederSeedWith(t *testing.T) {
+
+	// Seeds from the same stream disconnect the same sensors
+	s := Seeder{DisconnectRate: 0.5, Adaptive: true}
+	var gs []evo.Genome
+	for i := 0; i < 2; i++ {
+		evo.SetSeed(42)
+		g, err := s.SeedWith(evo.NewStream(1))
+		if err != nil {
+			t.Fatalf("error not expected: %v", err)
+		}
+		gs = append(gs, g)
+	}
+	if len(gs[0].Encoded.Conns) != len(gs[1].Encoded.Conns) {
+		t.Fatalf("incorrect number of connections: expected %d, actual %d", len(gs[0].Encoded.Conns), len(gs[1].Encoded.Conns))
+	}
+	for i, c := range gs[0].Encoded.Conns {
+		if c.Compare(gs[1].Encoded.Conns[i]) != 0 || c.Enabled != gs[1].Encoded.Conns[i].Enabled {
+			t.Errorf("incorrect connection at %d: expected %v, actual %v", i, c, gs[1].Encoded.Conns[i])
+		}
+	}
+}
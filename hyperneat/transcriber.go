@@ -2,6 +2,7 @@ package hyperneat
 
 import (
 	"errors"
+	"math"
 	"sort"
 
 	"github.com/klokare/evo"
@@ -36,6 +37,8 @@ type Transcriber struct {
 	// Properties
 	WeightPower      float64 // Power by which to multiply the weight's output value
 	BiasPower        float64 // Power by which to multiply the weight's output value
+	Adaptive         bool    // Set each connection's learning rule from the Cppn, as in adaptive HyperNEAT. Requires the seeder's learning rule outputs.
+	RatePower        float64 // Power by which to multiply the learning rate's output value
 	DisableSortCheck bool    // Speed things up by disabling sort check on encoded substrate. Use only if sure the incoming substrate is already sorted.
 
 	// Internal structure
@@ -158,12 +161,16 @@ func (t Transcriber) Transcribe(enc evo.Substrate) (dec evo.Substrate, err error
 			for _, tn := range tnodes {
 				w, e := t.WeightAndExpression(outputs, r, t.WeightPower)
 				if e > 0 {
-					dec.Conns = append(dec.Conns, evo.Conn{
+					c := evo.Conn{
 						Source:  sn.Position,
 						Target:  tn.Position,
 						Weight:  w,
 						Enabled: true,
-					})
+					}
+					if t.Adaptive {
+						c.Hebbian = LearningRule(outputs, r, t.RatePower)
+					}
+					dec.Conns = append(dec.Conns, c)
 				}
 				r++
 			}
@@ -193,3 +200,16 @@ func (t Transcriber) Transcribe(enc evo.Substrate) (dec evo.Substrate, err error
 	sort.Slice(dec.Conns, func(i, j int) bool { return dec.Conns[i].Compare(dec.Conns[j]) < 0 })
 	return
 }
+
+// LearningRule returns the learning rule for the connection at row i of the Cppn's outputs, as used
+// in adaptive HyperNEAT. The coefficients are the outputs' values and the rate is the magnitude of
+// its output multiplied by the power.
+func LearningRule(outputs evo.Matrix, i int, ratePower float64) evo.Hebbian {
+	return evo.Hebbian{
+		A:    outputs.At(i, HebbianA),
+		B:    outputs.At(i, HebbianB),
+		C:    outputs.At(i, HebbianC),
+		D:    outputs.At(i, HebbianD),
+		Rate: math.Abs(outputs.At(i, LearningRate)) * ratePower,
+	}
+}
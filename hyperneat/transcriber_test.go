@@ -0,0 +1,93 @@
+package hyperneat
+
+import (
+	"math"
+	"testing"
+
+	"github.com/klokare/evo"
+	"github.com/klokare/evo/neat"
+	"github.com/klokare/evo/network/forward"
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestLearningRule(t *testing.T) {
+	outputs := mat.NewDense(2, 8, []float64{
+		0.1, 0.2, 0.3, 0.4, 0.5, 0.6, 0.7, 0.8,
+		0.1, 0.2, 0.3, -0.4, -0.5, -0.6, -0.7, -0.8,
+	})
+	var cases = []struct {
+		Desc     string
+		Row      int
+		Expected evo.Hebbian
+	}{
+		{Desc: "positive outputs", Row: 0, Expected: evo.Hebbian{A: 0.4, B: 0.5, C: 0.6, D: 0.7, Rate: 0.4}},
+		{Desc: "negative outputs", Row: 1, Expected: evo.Hebbian{A: -0.4, B: -0.5, C: -0.6, D: -0.7, Rate: 0.4}},
+	}
+	for _, c := range cases {
+		t.Run(c.Desc, func(t *testing.T) {
+			if actual := LearningRule(outputs, c.Row, 0.5); actual != c.Expected {
+				t.Errorf("incorrect rule: expected %+v, actual %+v", c.Expected, actual)
+			}
+		})
+	}
+}
+
+func TestTranscriberAdaptive(t *testing.T) {
+
+	// Create a Cppn whose outputs are their bias values
+	biases := []float64{0.3, 0.0, 1.0, 0.5, -0.5, 0.25, -0.25, -0.2}
+	var enc evo.Substrate
+	for i := 0; i < 8; i++ {
+		enc.Nodes = append(enc.Nodes, evo.Node{Position: evo.Position{Layer: 0.0, X: float64(i) / 7.0}, Neuron: evo.Input, Activation: evo.Direct})
+	}
+	for i, b := range biases {
+		enc.Nodes = append(enc.Nodes, evo.Node{Position: evo.Position{Layer: 1.0, X: float64(i) / 7.0}, Neuron: evo.Output, Activation: evo.Direct, Bias: b})
+	}
+
+	// Create the transcriber with a single connection in the template
+	tr := &Transcriber{
+		CppnTranscriber: neat.Transcriber{},
+		CppnTranslator:  forward.Translator{},
+		Inspector:       LinkExpressionOutput{},
+		WeightPower:     2.0,
+		BiasPower:       1.0,
+		Adaptive:        true,
+		RatePower:       0.5,
+	}
+	err := tr.SetTemplate(evo.Substrate{
+		Nodes: []evo.Node{
+			{Position: evo.Position{Layer: 0.0}, Neuron: evo.Input, Activation: evo.Direct},
+			{Position: evo.Position{Layer: 1.0}, Neuron: evo.Output, Activation: evo.Direct},
+		},
+	})
+	if err != nil {
+		t.Fatalf("error not expected: %v", err)
+	}
+
+	// The connection carries the learning rule
+	dec, err := tr.Transcribe(enc)
+	if err != nil {
+		t.Fatalf("error not expected: %v", err)
+	}
+	if len(dec.Conns) != 1 {
+		t.Fatalf("incorrect number of conns: expected 1, actual %d", len(dec.Conns))
+	}
+	c := dec.Conns[0]
+	if math.Abs(c.Weight-0.6) > 1e-9 {
+		t.Errorf("incorrect weight: expected 0.6, actual %f", c.Weight)
+	}
+	expected := evo.Hebbian{A: 0.5, B: -0.5, C: 0.25, D: -0.25, Rate: 0.1}
+	if math.Abs(c.A-expected.A) > 1e-9 || math.Abs(c.B-expected.B) > 1e-9 || math.Abs(c.C-expected.C) > 1e-9 ||
+		math.Abs(c.D-expected.D) > 1e-9 || math.Abs(c.Rate-expected.Rate) > 1e-9 {
+		t.Errorf("incorrect rule: expected %+v, actual %+v", expected, c.Hebbian)
+	}
+
+	// Without adaptation, the connection has no rule
+	tr.Adaptive = false
+	if dec, err = tr.Transcribe(enc); err != nil {
+		t.Fatalf("error not expected: %v", err)
+	}
+	if dec.Conns[0].Hebbian != (evo.Hebbian{}) {
+		t.Errorf("rule not expected: %+v", dec.Conns[0].Hebbian)
+	}
+}
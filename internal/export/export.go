@@ -0,0 +1,42 @@
+// Package export holds what the packages that write forward networks in other forms, such as Go
+// source or ONNX models, have in common: translating the decoded substrate and writing files.
+package export
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+
+	"github.com/klokare/evo"
+	"github.com/klokare/evo/network/forward"
+)
+
+// Known errors
+var (
+	ErrEmptyNetwork = errors.New("network requires an input and an output layer")
+)
+
+// Writer writes a forward network in another form
+type Writer interface {
+	Network(io.Writer, forward.Network) error
+}
+
+// Substrate writes the network translated from the decoded substrate with the writer
+func Substrate(w io.Writer, nw Writer, sub evo.Substrate) (err error) {
+	var net evo.Network
+	if net, err = (forward.Translator{}).Translate(sub); err != nil {
+		return
+	}
+	return nw.Network(w, net.(forward.Network))
+}
+
+// WriteFile writes the network translated from the decoded substrate to the file with the writer.
+// Nothing is written if the network cannot be.
+func WriteFile(filename string, nw Writer, sub evo.Substrate) (err error) {
+	b := new(bytes.Buffer)
+	if err = Substrate(b, nw, sub); err != nil {
+		return
+	}
+	return os.WriteFile(filename, b.Bytes(), 0644)
+}
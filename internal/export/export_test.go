@@ -0,0 +1,82 @@
+package export
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/klokare/evo"
+	"github.com/klokare/evo/network/forward"
+)
+
+// Writes the number of neurons in each layer
+type mockWriter struct{ HasError bool }
+
+func (m mockWriter) Network(w io.Writer, net forward.Network) error {
+	if m.HasError {
+		return errors.New("error in mock writer")
+	}
+	for _, l := range net.Layers {
+		fmt.Fprintf(w, "%d ", len(l.Activations))
+	}
+	return nil
+}
+
+func TestWriteFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "export")
+	if err != nil {
+		t.Fatalf("error not expected: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	sub := evo.Substrate{
+		Nodes: []evo.Node{
+			{Position: evo.Position{Layer: 0.0, X: 0.0}, Neuron: evo.Input, Activation: evo.Direct},
+			{Position: evo.Position{Layer: 0.0, X: 1.0}, Neuron: evo.Input, Activation: evo.Direct},
+			{Position: evo.Position{Layer: 1.0}, Neuron: evo.Output, Activation: evo.Sigmoid},
+		},
+		Conns: []evo.Conn{{Source: evo.Position{Layer: 0.0}, Target: evo.Position{Layer: 1.0}, Weight: 1.5, Enabled: true}},
+	}
+
+	var cases = []struct {
+		Desc      string
+		Writer    mockWriter
+		Substrate evo.Substrate
+		HasError  bool
+		Expected  string
+	}{
+		{Desc: "translated network is written", Substrate: sub, Expected: "2 1 "},
+		{Desc: "substrate cannot be translated", Substrate: evo.Substrate{}, HasError: true},
+		{Desc: "writer fails", Writer: mockWriter{HasError: true}, Substrate: sub, HasError: true},
+	}
+
+	for i, c := range cases {
+		t.Run(c.Desc, func(t *testing.T) {
+			filename := filepath.Join(dir, fmt.Sprintf("network-%d", i))
+			err := WriteFile(filename, c.Writer, c.Substrate)
+			if c.HasError {
+				if err == nil {
+					t.Error("expected error not found")
+				}
+				if _, err = os.Stat(filename); !os.IsNotExist(err) {
+					t.Error("file should not be written")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("error not expected: %v", err)
+			}
+			b, err := ioutil.ReadFile(filename)
+			if err != nil {
+				t.Fatalf("error not expected reading: %v", err)
+			}
+			if string(b) != c.Expected {
+				t.Errorf("incorrect file: expected %q, actual %q", c.Expected, string(b))
+			}
+		})
+	}
+}
@@ -85,14 +85,14 @@ func Median(values []float64) float64 {
 	case 0:
 		return 0
 	case 1:
-		return values[1]
+		return values[0]
 	default:
 		v2 := make([]float64, len(values)) // make a copy so we do not alter order of original slice
 		copy(v2, values)
 		sort.Float64s(v2)
 		i := n / 2
 		if n%2 == 0 {
-			return (v2[i] + v2[i+1]) / 2.0
+			return (v2[i-1] + v2[i]) / 2.0
 		}
 		return v2[i]
 	}
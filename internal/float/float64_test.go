@@ -0,0 +1,35 @@
+package float
+
+import "testing"
+
+func TestMedian(t *testing.T) {
+	var cases = []struct {
+		Desc     string
+		Values   []float64
+		Expected float64
+	}{
+		{Desc: "no values", Expected: 0.0},
+		{Desc: "one value", Values: []float64{3.0}, Expected: 3.0},
+		{Desc: "two values", Values: []float64{4.0, 1.0}, Expected: 2.5},
+		{Desc: "odd number of values", Values: []float64{5.0, 1.0, 3.0, 9.0, 2.0}, Expected: 3.0},
+		{Desc: "even number of values", Values: []float64{8.0, 1.0, 6.0, 2.0}, Expected: 4.0},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Desc, func(t *testing.T) {
+			orig := make([]float64, len(c.Values))
+			copy(orig, c.Values)
+			if x := Median(c.Values); x != c.Expected {
+				t.Errorf("incorrect median: expected %f, actual %f", c.Expected, x)
+			}
+
+			// The values should not be reordered
+			for i, x := range orig {
+				if c.Values[i] != x {
+					t.Errorf("values reordered: expected %v, actual %v", orig, c.Values)
+					break
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+// Package layout indexes the neurons of a substrate for the translators of networks whose
+// connections may point in any direction. Each neuron is the node at the same position in the
+// sorted nodes and each enabled connection is resolved to the indexes of its source and target.
+package layout
+
+import (
+	"errors"
+	"sort"
+
+	"github.com/klokare/evo"
+)
+
+// Known errors
+var (
+	ErrNoSensors         = errors.New("network requires at least 1 input neuron")
+	ErrNoOutputs         = errors.New("network requires at least 1 output neuron")
+	ErrUnknownConnSource = errors.New("connection source does not match a neuron")
+	ErrUnknownConnTarget = errors.New("connection target does not match a neuron")
+	ErrInputAsTarget     = errors.New("input neuron cannot be the target of a connection")
+)
+
+// Layout of the substrate's neurons and enabled connections
+type Layout struct {
+	Nodes    []evo.Node // The nodes in neuron order
+	Inputs   []int      // Indexes of the input neurons
+	Outputs  []int      // Indexes of the output neurons
+	Synapses []Synapse  // The enabled connections in substrate order
+}
+
+// Synapse is an enabled connection with the indexes of its source and target neurons
+type Synapse struct {
+	evo.Conn
+	Source int
+	Target int
+}
+
+// New returns the layout of the substrate. Unless the sort check is disabled, the nodes are sorted
+// first as the connections are located by searching them.
+func New(sub evo.Substrate, disableSortCheck bool) (l Layout, err error) {
+
+	// Sort the substrate to ensure proper ordering during translation
+	l.Nodes = make([]evo.Node, len(sub.Nodes))
+	copy(l.Nodes, sub.Nodes)
+	if !disableSortCheck {
+		sort.Slice(l.Nodes, func(i, j int) bool { return l.Nodes[i].Compare(l.Nodes[j]) < 0 })
+	}
+
+	// Identify the inputs and outputs
+	l.Inputs = make([]int, 0, len(l.Nodes))
+	l.Outputs = make([]int, 0, len(l.Nodes))
+	for i, node := range l.Nodes {
+		switch node.Neuron {
+		case evo.Input:
+			l.Inputs = append(l.Inputs, i)
+		case evo.Output:
+			l.Outputs = append(l.Outputs, i)
+		}
+	}
+
+	// Check for errors
+	if len(l.Inputs) == 0 {
+		err = ErrNoSensors
+		return
+	} else if len(l.Outputs) == 0 {
+		err = ErrNoOutputs
+		return
+	}
+
+	// Locate the source and target neurons of the enabled connections
+	l.Synapses = make([]Synapse, 0, len(sub.Conns))
+	for _, c := range sub.Conns {
+
+		// Skip disabled connections
+		if !c.Enabled {
+			continue
+		}
+
+		// Locate the source and target neurons
+		src, tgt := l.find(c.Source), l.find(c.Target)
+		if src < 0 {
+			err = ErrUnknownConnSource
+			return
+		} else if tgt < 0 {
+			err = ErrUnknownConnTarget
+			return
+		} else if l.Nodes[tgt].Neuron == evo.Input {
+			err = ErrInputAsTarget
+			return
+		}
+		l.Synapses = append(l.Synapses, Synapse{Conn: c, Source: src, Target: tgt})
+	}
+	return
+}
+
+// Returns the index of the neuron at the position or -1 if there is none
+func (l Layout) find(p evo.Position) int {
+	i := sort.Search(len(l.Nodes), func(i int) bool { return l.Nodes[i].Position.Compare(p) >= 0 })
+	if i < len(l.Nodes) && l.Nodes[i].Position.Compare(p) == 0 {
+		return i
+	}
+	return -1
+}
@@ -0,0 +1,84 @@
+package layout
+
+import (
+	"testing"
+
+	"github.com/klokare/evo"
+)
+
+func TestNew(t *testing.T) {
+	var (
+		in  = evo.Position{Layer: 0.0, X: 0.5}
+		hid = evo.Position{Layer: 0.5, X: 0.5}
+		out = evo.Position{Layer: 1.0, X: 0.5}
+	)
+	var cases = []struct {
+		Desc     string
+		Nodes    []evo.Node
+		Conns    []evo.Conn
+		Synapses [][2]int
+		Expected error
+	}{
+		{
+			Desc:     "no inputs",
+			Nodes:    []evo.Node{{Position: out, Neuron: evo.Output}},
+			Expected: ErrNoSensors,
+		},
+		{
+			Desc:     "no outputs",
+			Nodes:    []evo.Node{{Position: in, Neuron: evo.Input}},
+			Expected: ErrNoOutputs,
+		},
+		{
+			Desc:     "unknown source",
+			Nodes:    []evo.Node{{Position: out, Neuron: evo.Output}, {Position: in, Neuron: evo.Input}},
+			Conns:    []evo.Conn{{Source: hid, Target: out, Enabled: true}},
+			Expected: ErrUnknownConnSource,
+		},
+		{
+			Desc:     "unknown target",
+			Nodes:    []evo.Node{{Position: out, Neuron: evo.Output}, {Position: in, Neuron: evo.Input}},
+			Conns:    []evo.Conn{{Source: in, Target: hid, Enabled: true}},
+			Expected: ErrUnknownConnTarget,
+		},
+		{
+			Desc:     "input as target",
+			Nodes:    []evo.Node{{Position: out, Neuron: evo.Output}, {Position: in, Neuron: evo.Input}},
+			Conns:    []evo.Conn{{Source: out, Target: in, Enabled: true}},
+			Expected: ErrInputAsTarget,
+		},
+		{
+			Desc:  "sorted and indexed",
+			Nodes: []evo.Node{{Position: out, Neuron: evo.Output}, {Position: hid, Neuron: evo.Hidden}, {Position: in, Neuron: evo.Input}},
+			Conns: []evo.Conn{
+				{Source: in, Target: hid, Enabled: true},
+				{Source: in, Target: out, Enabled: false},
+				{Source: out, Target: hid, Enabled: true},
+			},
+			Synapses: [][2]int{{0, 1}, {2, 1}},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Desc, func(t *testing.T) {
+			l, err := New(evo.Substrate{Nodes: c.Nodes, Conns: c.Conns}, false)
+			if err != c.Expected {
+				t.Fatalf("incorrect error: expected %v, actual %v", c.Expected, err)
+			}
+			if err != nil {
+				return
+			}
+			if len(l.Inputs) != 1 || l.Inputs[0] != 0 || len(l.Outputs) != 1 || l.Outputs[0] != 2 {
+				t.Errorf("incorrect inputs and outputs: expected [0] and [2], actual %v and %v", l.Inputs, l.Outputs)
+			}
+			if len(l.Synapses) != len(c.Synapses) {
+				t.Fatalf("incorrect number of synapses: expected %d, actual %d", len(c.Synapses), len(l.Synapses))
+			}
+			for i, s := range c.Synapses {
+				if l.Synapses[i].Source != s[0] || l.Synapses[i].Target != s[1] {
+					t.Errorf("incorrect synapse %d: expected %v, actual %d to %d", i, s, l.Synapses[i].Source, l.Synapses[i].Target)
+				}
+			}
+		})
+	}
+}
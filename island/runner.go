@@ -0,0 +1,377 @@
+package island
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sync"
+
+	"github.com/klokare/evo"
+	"github.com/klokare/evo/config"
+)
+
+// Known errors
+var (
+	ErrNoIslands = errors.New("island runner requires at least one experiment")
+)
+
+// Key of the random streams from which islands choose their destinations, distinct from those used
+// by evo. Each island's stream is also keyed by its index.
+const migrationStream int64 = 0x69736c // "isl"
+
+// Callback functions are called when the event to which they are subscribed occurs on an island.
+// The index of the island, in the order the experiments were given to Run, is passed along with the
+// island's population.
+type Callback func(island int, pop evo.Population) error
+
+// Subscription pairs an island callback with its event
+type Subscription struct {
+	evo.Event
+	Callback
+}
+
+// Runner drives several experiments, or islands, concurrently and periodically migrates the best
+// genomes between them. Each island is run with evo.Run so its own helpers, subscriptions and
+// checkpoints are used as normal. The runner does not save the migrants waiting between islands so
+// a resumed island starts with an empty inbox.
+//
+// Migration happens when an island selects from a generation that is a multiple of the interval.
+// The island sends copies of its best genomes to its destinations and then takes in any migrants it
+// has received since its last migration. Each migrant takes the place of one of the worst
+// continuing genomes, keeping at least one, or else of the last parent group. It enters the next
+// generation as an offspring without parents, so that it is given the next ID from the island's
+// sequence and placed by the island's speciator, but it is neither crossed nor mutated. Islands do
+// not wait on each other so a slow island simply receives the latest migrants from its neighbours.
+type Runner struct {
+	Topology                  // Decides the destinations of each island's migrants. If zero, a ring is used.
+	Interval   int            // Number of generations between migrations. If zero, migration is disabled.
+	Migrants   int            // Number of genomes each island sends to each destination
+	Comparison evo.Comparison // Determines the best and worst genomes. If zero, genomes are compared by fitness.
+
+	// Subscriptions are added to each island in addition to the experiment's own
+	Subscriptions []Subscription
+}
+
+// Keys used by the island runner. NewRunner declares them with the configurer.
+var Keys = []config.Key{
+	{Name: "island|runner|topology", Type: config.String, Description: "Topology deciding the destinations of migrants: ring, full or random"},
+	{Name: "island|runner|interval", Type: config.Int, Min: 0.0, Max: math.Inf(1), Description: "Generations between migrations. Migration is disabled if zero."},
+	{Name: "island|runner|migrants", Type: config.Int, Min: 0.0, Max: math.Inf(1), Description: "Number of genomes each island sends to each destination"},
+	{Name: "island|runner|comparison", Type: config.Comparison, Description: "Comparison deciding the best and worst genomes"},
+}
+
+// NewRunner creates a new island runner using the configuration
+func NewRunner(cfg config.Configurer) *Runner {
+	cfg.Declare(Keys...)
+	return &Runner{
+		Topology:   Topologies[cfg.String("island|runner|topology")],
+		Interval:   cfg.Int("island|runner|interval"),
+		Migrants:   cfg.Int("island|runner|migrants"),
+		Comparison: cfg.Comparison("island|runner|comparison"),
+	}
+}
+
+// AddSubscription adds a per-island subscription to the runner
+func (r *Runner) AddSubscription(s Subscription) {
+	r.Subscriptions = append(r.Subscriptions, s)
+}
+
+// Run the experiments concurrently in the given context with the evaluator and return each
+// island's final population. If any island returns an error, the context given to the others is
+// cancelled and the first error is returned.
+func (r Runner) Run(ctx context.Context, exps []evo.Experiment, eval evo.Evaluator) (pops []evo.Population, err error) {
+
+	// Check for errors
+	if len(exps) == 0 {
+		err = ErrNoIslands
+		return
+	}
+
+	// Stop the other islands if one fails
+	ctx, fn := context.WithCancel(ctx)
+	defer fn()
+
+	// Create the islands and their inboxes
+	islands := make([]*island, len(exps))
+	for i, exp := range exps {
+		islands[i] = &island{Experiment: exp, runner: r, index: i, islands: islands, rng: evo.NewStream(migrationStream, int64(i))}
+	}
+
+	// Run the islands
+	pops = make([]evo.Population, len(exps))
+	errs := make([]error, len(exps))
+	var wg sync.WaitGroup
+	for i, isl := range islands {
+		wg.Add(1)
+		go func(i int, isl *island) {
+			defer wg.Done()
+			if pops[i], errs[i] = evo.Run(ctx, isl, eval); errs[i] != nil {
+				fn()
+			}
+		}(i, isl)
+	}
+	wg.Wait()
+
+	// Return the first error, if any
+	for _, e := range errs {
+		if e != nil {
+			err = e
+			break
+		}
+	}
+	return
+}
+
+// An island wraps an experiment to add migration and the runner's subscriptions
+type island struct {
+	evo.Experiment
+	runner  Runner
+	index   int
+	islands []*island
+	rng     evo.Random
+
+	sync.Mutex
+	inbox map[int][]evo.Genome // The latest migrants from each sending island
+
+	// Migrants placed in the current generation. Their parent groups are marked with negative IDs,
+	// which evo never assigns, so that Cross and Mutate can recognise them.
+	arrivals []evo.Genome
+}
+
+// Subscriptions returns the experiment's subscriptions and the runner's island subscriptions bound
+// to this island
+func (i *island) Subscriptions() []evo.Subscription {
+	var subs []evo.Subscription
+	if sx, ok := i.Experiment.(evo.SubscriptionProvider); ok {
+		subs = append(subs, sx.Subscriptions()...)
+	}
+	for _, s := range i.runner.Subscriptions {
+		cb := s.Callback
+		subs = append(subs, evo.Subscription{
+			Event:    s.Event,
+			Callback: func(pop evo.Population) error { return cb(i.index, pop) },
+		})
+	}
+	return subs
+}
+
+// PopulateWith creates the initial population, drawing from the random stream if the experiment
+// supports it
+func (i *island) PopulateWith(rng evo.Random) (evo.Population, error) {
+	if px, ok := i.Experiment.(evo.RandomPopulator); ok {
+		return px.PopulateWith(rng)
+	}
+	return i.Experiment.Populate()
+}
+
+// Select the continuing genomes and parents with the experiment's selector and then migrate
+func (i *island) Select(pop evo.Population) (continuing []evo.Genome, parents [][]evo.Genome, err error) {
+	if continuing, parents, err = i.Experiment.Select(pop); err != nil {
+		return
+	}
+	continuing, parents = i.migrate(pop, continuing, parents)
+	return
+}
+
+// SelectWith selects the continuing genomes and parents, drawing from the random stream if the
+// experiment supports it, and then migrates
+func (i *island) SelectWith(rng evo.Random, pop evo.Population) (continuing []evo.Genome, parents [][]evo.Genome, err error) {
+	if sx, ok := i.Experiment.(evo.RandomSelector); ok {
+		continuing, parents, err = sx.SelectWith(rng, pop)
+	} else {
+		continuing, parents, err = i.Experiment.Select(pop)
+	}
+	if err != nil {
+		return
+	}
+	continuing, parents = i.migrate(pop, continuing, parents)
+	return
+}
+
+// Cross the parents with the experiment's crosser. A migrant is returned as it is.
+func (i *island) Cross(parents ...evo.Genome) (evo.Genome, error) {
+	if m, ok := i.arrival(parents); ok {
+		return m, nil
+	}
+	return i.Experiment.Cross(parents...)
+}
+
+// CrossWith crosses the parents, drawing from the random stream if the experiment supports it. A
+// migrant is returned as it is.
+func (i *island) CrossWith(rng evo.Random, parents ...evo.Genome) (evo.Genome, error) {
+	if m, ok := i.arrival(parents); ok {
+		return m, nil
+	}
+	if cx, ok := i.Experiment.(evo.RandomCrosser); ok {
+		return cx.CrossWith(rng, parents...)
+	}
+	return i.Experiment.Cross(parents...)
+}
+
+// Mutate the genome with the experiment's mutator. A migrant is not mutated.
+func (i *island) Mutate(g *evo.Genome) error {
+	if arrived(g) {
+		return nil
+	}
+	return i.Experiment.Mutate(g)
+}
+
+// MutateWith mutates the genome, drawing from the random stream if the experiment supports it. A
+// migrant is not mutated.
+func (i *island) MutateWith(rng evo.Random, g *evo.Genome) error {
+	if arrived(g) {
+		return nil
+	}
+	if mx, ok := i.Experiment.(evo.RandomMutator); ok {
+		return mx.MutateWith(rng, g)
+	}
+	return i.Experiment.Mutate(g)
+}
+
+// State returns the experiment's internal state, if it is stateful
+func (i *island) State() ([]byte, error) {
+	if sx, ok := i.Experiment.(evo.Stateful); ok {
+		return sx.State()
+	}
+	return nil, nil
+}
+
+// Restore the experiment's internal state, if it is stateful
+func (i *island) Restore(b []byte) error {
+	if sx, ok := i.Experiment.(evo.Stateful); ok {
+		return sx.Restore(b)
+	}
+	return nil
+}
+
+// Checkpoint passes the checkpoint to the experiment, if it is a checkpointer
+func (i *island) Checkpoint(cp evo.Checkpoint) error {
+	if cx, ok := i.Experiment.(evo.Checkpointer); ok {
+		return cx.Checkpoint(cp)
+	}
+	return nil
+}
+
+// Migrate sends this island's best genomes to its destinations and puts the migrants it has
+// received in the place of its worst continuing genomes or last parent groups. Off the interval,
+// the selection is returned unchanged.
+func (i *island) migrate(pop evo.Population, continuing []evo.Genome, parents [][]evo.Genome) ([]evo.Genome, [][]evo.Genome) {
+	i.arrivals = i.arrivals[:0]
+	if i.runner.Interval <= 0 || i.runner.Migrants <= 0 || len(i.islands) < 2 ||
+		pop.Generation%i.runner.Interval != 0 || len(pop.Genomes) == 0 {
+		return continuing, parents
+	}
+
+	// Order the genomes from worst to best
+	cmp := i.runner.Comparison
+	if cmp == 0 {
+		cmp = evo.ByFitness
+	}
+	ordered := make([]evo.Genome, len(pop.Genomes))
+	copy(ordered, pop.Genomes)
+	evo.SortBy(ordered, evo.BySolved, cmp, evo.ByComplexity, evo.ByAge)
+
+	// Send copies of the best genomes
+	n := i.runner.Migrants
+	if n > len(ordered) {
+		n = len(ordered)
+	}
+	best := ordered[len(ordered)-n:]
+	for _, j := range i.runner.Topology.Destinations(i.rng, i.index, len(i.islands)) {
+		i.islands[j].receive(i.index, best)
+	}
+
+	// Order the continuing genomes from worst to best, without changing the caller's slice
+	migrants := i.collect()
+	if len(migrants) == 0 {
+		return continuing, parents
+	}
+	cs := make([]evo.Genome, len(continuing))
+	copy(cs, continuing)
+	evo.SortBy(cs, evo.BySolved, cmp, evo.ByComplexity, evo.ByAge)
+	ps := make([][]evo.Genome, len(parents))
+	copy(ps, parents)
+
+	// Replace the worst continuing genomes, leaving the best, and then the last parent groups
+	var arriving [][]evo.Genome
+	for _, m := range migrants {
+		if len(cs) > 1 {
+			cs = cs[1:]
+		} else if len(ps) > 0 {
+			ps = ps[:len(ps)-1]
+		} else {
+			break
+		}
+		m.Species, m.Age = 0, 0
+		i.arrivals = append(i.arrivals, m)
+		arriving = append(arriving, []evo.Genome{{ID: -int64(len(i.arrivals))}})
+	}
+	return cs, append(ps, arriving...)
+}
+
+// Returns the migrant if the parent group marks one
+func (i *island) arrival(parents []evo.Genome) (m evo.Genome, ok bool) {
+	if len(parents) != 1 || parents[0].ID >= 0 {
+		return
+	}
+	k := int(-parents[0].ID) - 1
+	if k >= len(i.arrivals) {
+		return
+	}
+	return clone(i.arrivals[k]), true
+}
+
+// Returns true if the genome is a migrant, clearing the mark left in its lineage. Migrants are
+// founders on their new island so they have no parents.
+func arrived(g *evo.Genome) bool {
+	if len(g.Parents) != 1 || g.Parents[0] >= 0 {
+		return false
+	}
+	g.Parents = nil
+	return true
+}
+
+// Receive copies of the migrants from the sending island, replacing any it sent previously
+func (i *island) receive(sender int, migrants []evo.Genome) {
+	ms := make([]evo.Genome, len(migrants))
+	for j, g := range migrants {
+		ms[j] = clone(g)
+	}
+	i.Lock()
+	defer i.Unlock()
+	if i.inbox == nil {
+		i.inbox = make(map[int][]evo.Genome, len(i.islands))
+	}
+	i.inbox[sender] = ms
+}
+
+// Collect and clear the migrants waiting in the inbox
+func (i *island) collect() (migrants []evo.Genome) {
+	i.Lock()
+	defer i.Unlock()
+	for j := range i.islands {
+		migrants = append(migrants, i.inbox[j]...)
+	}
+	i.inbox = nil
+	return
+}
+
+// Clone the genome so that islands do not share slices. The decoded substrate is cleared so the
+// migrant is transcribed by its new island.
+func clone(g evo.Genome) evo.Genome {
+	c := g
+	c.Encoded = evo.Substrate{
+		Nodes: make([]evo.Node, len(g.Encoded.Nodes)),
+		Conns: make([]evo.Conn, len(g.Encoded.Conns)),
+	}
+	copy(c.Encoded.Nodes, g.Encoded.Nodes)
+	copy(c.Encoded.Conns, g.Encoded.Conns)
+	c.Decoded = evo.Substrate{}
+	c.Traits = make([]float64, len(g.Traits))
+	copy(c.Traits, g.Traits)
+	if g.Objectives != nil {
+		c.Objectives = make([]float64, len(g.Objectives))
+		copy(c.Objectives, g.Objectives)
+	}
+	return c
+}
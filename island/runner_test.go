@@ -0,0 +1,247 @@
+package island
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/klokare/evo"
+)
+
+func TestRunnerRun(t *testing.T) {
+
+	// Create two islands whose genomes differ in their trait, which the evaluator uses as fitness
+	ctx, fn, cb := evo.WithIterations(context.Background(), 3)
+	defer fn()
+	exps := []evo.Experiment{
+		&mockExperiment{Trait: 10.0, subscriptions: []evo.Subscription{{Event: evo.Evaluated, Callback: cb}}},
+		&mockExperiment{Trait: 1.0},
+	}
+
+	// Count the per-island events
+	var mu sync.Mutex
+	completed := make(map[int]int)
+	r := Runner{Topology: Ring, Interval: 1, Migrants: 2}
+	r.AddSubscription(Subscription{Event: evo.Completed, Callback: func(i int, pop evo.Population) error {
+		mu.Lock()
+		defer mu.Unlock()
+		completed[i]++
+		return nil
+	}})
+
+	// Run the islands
+	pops, err := r.Run(ctx, exps, mockEvaluator{})
+	if err != nil {
+		t.Fatalf("error not expected: %v", err)
+	}
+	if len(pops) != 2 {
+		t.Fatalf("incorrect number of populations: expected 2, actual %d", len(pops))
+	}
+	if completed[0] != 1 || completed[1] != 1 {
+		t.Errorf("incorrect completed events: expected 1 for each island, actual %v", completed)
+	}
+
+	// The second island should have received migrants from the first with unique IDs
+	var migrants int
+	ids := make(map[int64]bool)
+	for _, g := range pops[1].Genomes {
+		if ids[g.ID] {
+			t.Errorf("duplicate genome ID %d", g.ID)
+		}
+		ids[g.ID] = true
+		if g.Traits[0] == 10.0 {
+			migrants++
+		}
+	}
+	if migrants == 0 {
+		t.Error("expected migrants on the second island")
+	}
+}
+
+func TestRunnerRunErrors(t *testing.T) {
+
+	// No islands
+	if _, err := (Runner{}).Run(context.Background(), nil, mockEvaluator{}); err == nil {
+		t.Error("expected error for no islands not found")
+	}
+
+	// A failing island stops the others
+	exps := []evo.Experiment{&mockExperiment{Trait: 1.0}, &mockExperiment{Trait: 1.0, HasError: true}}
+	if _, err := (Runner{Interval: 1, Migrants: 1}).Run(context.Background(), exps, mockEvaluator{}); err == nil {
+		t.Error("expected error from failing island not found")
+	}
+}
+
+func TestIslandMigrate(t *testing.T) {
+
+	// Create two islands with the first having a migrant waiting from the second
+	r := Runner{Interval: 2, Migrants: 1}
+	islands := make([]*island, 2)
+	islands[0] = &island{Experiment: &mockExperiment{}, runner: r, index: 0, islands: islands, rng: evo.NewRandom()}
+	islands[1] = &island{Experiment: &mockExperiment{}, runner: r, index: 1, islands: islands, rng: evo.NewRandom()}
+	islands[1].receive(0, nil)
+	islands[0].receive(1, []evo.Genome{{ID: 2, Species: 3, Fitness: 5.0, Traits: []float64{5.0}}})
+
+	// Migration only happens on the interval
+	pop := evo.Population{Generation: 1, Genomes: []evo.Genome{
+		{ID: 1, Species: 1, Fitness: 2.0, Traits: []float64{2.0}},
+		{ID: 2, Species: 2, Fitness: 1.0, Traits: []float64{1.0}},
+		{ID: 3, Species: 1, Fitness: 3.0, Traits: []float64{3.0}},
+	}}
+	continuing, parents, err := islands[0].Select(pop)
+	if err != nil {
+		t.Fatalf("error not expected: %v", err)
+	}
+	if len(continuing) != 2 || len(parents) != 1 {
+		t.Errorf("migration should not occur off the interval: %d continuing and %d parents", len(continuing), len(parents))
+	}
+
+	// The worst continuing genome makes way for the migrant, which joins the parents
+	pop.Generation = 2
+	if continuing, parents, err = islands[0].Select(pop); err != nil {
+		t.Fatalf("error not expected: %v", err)
+	}
+	if len(continuing) != 1 || continuing[0].ID != 3 {
+		t.Errorf("incorrect continuing genomes: expected genome 3, actual %v", continuing)
+	}
+	if len(parents) != 2 || parents[0][0].ID != 3 {
+		t.Fatalf("incorrect parents: expected genome 3 and the migrant, actual %v", parents)
+	}
+
+	// The migrant is neither crossed nor mutated and is left for the speciator
+	child, err := islands[0].CrossWith(evo.NewRandom(), parents[1]...)
+	if err != nil {
+		t.Fatalf("error not expected: %v", err)
+	}
+	child.ID = 4 // as evo assigns the next ID from the island's sequence
+	evo.SetLineage(&child, pop.Generation, parents[1]...)
+	if err = islands[0].MutateWith(evo.NewRandom(), &child); err != nil {
+		t.Fatalf("error not expected: %v", err)
+	}
+	if child.Species != 0 || child.Fitness != 5.0 || child.Traits[0] != 5.0 || len(child.Parents) != 0 {
+		t.Errorf("incorrect migrant: expected species 0, fitness 5.0 and no parents, actual %d, %f and %v", child.Species, child.Fitness, child.Parents)
+	}
+
+	// Other parents are crossed as usual
+	if child, err = islands[0].Cross(parents[0]...); err != nil {
+		t.Fatalf("error not expected: %v", err)
+	}
+	if child.Fitness != 0.0 || child.Traits[0] != 3.0 {
+		t.Errorf("incorrect offspring: expected fitness 0.0 and trait 3.0, actual %f and %f", child.Fitness, child.Traits[0])
+	}
+
+	// The best genome was sent to the second island
+	ms := islands[1].collect()
+	if len(ms) != 1 || ms[0].ID != 3 {
+		t.Errorf("incorrect migrants sent: %v", ms)
+	}
+}
+
+func TestIslandForwarding(t *testing.T) {
+
+	// The island forwards the optional interfaces to the experiment
+	exp := &mockStatefulExperiment{mockExperiment: &mockExperiment{}}
+	isl := &island{Experiment: exp}
+	if err := isl.Restore([]byte("abc")); err != nil {
+		t.Fatalf("error not expected: %v", err)
+	}
+	if b, err := isl.State(); err != nil || string(b) != "abc" {
+		t.Errorf("incorrect state: expected abc, actual %s (%v)", b, err)
+	}
+	if err := isl.Checkpoint(evo.Checkpoint{LastGID: 5}); err != nil {
+		t.Fatalf("error not expected: %v", err)
+	}
+	if exp.checkpoint.LastGID != 5 {
+		t.Errorf("checkpoint not forwarded: expected last GID 5, actual %d", exp.checkpoint.LastGID)
+	}
+	if _, err := isl.PopulateWith(evo.NewRandom()); err != nil {
+		t.Fatalf("error not expected: %v", err)
+	}
+	if !exp.seeded {
+		t.Error("random stream not forwarded to the populator")
+	}
+
+	// Experiments without them are left alone
+	isl = &island{Experiment: &mockExperiment{}}
+	if b, err := isl.State(); err != nil || b != nil {
+		t.Errorf("incorrect state: expected none, actual %s (%v)", b, err)
+	}
+	if err := isl.Checkpoint(evo.Checkpoint{}); err != nil {
+		t.Errorf("error not expected: %v", err)
+	}
+}
+
+type mockExperiment struct {
+	Trait    float64
+	HasError bool
+
+	subscriptions []evo.Subscription
+}
+
+func (m *mockExperiment) Subscriptions() []evo.Subscription { return m.subscriptions }
+
+func (m *mockExperiment) Populate() (pop evo.Population, err error) {
+	pop.Genomes = make([]evo.Genome, 5)
+	for i := range pop.Genomes {
+		pop.Genomes[i] = evo.Genome{ID: int64(i + 1), Traits: []float64{m.Trait}}
+	}
+	return
+}
+
+func (m *mockExperiment) Speciate(*evo.Population) error { return nil }
+
+// Select continues all but the worst genome and clones the best
+func (m *mockExperiment) Select(pop evo.Population) ([]evo.Genome, [][]evo.Genome, error) {
+	if m.HasError {
+		return nil, nil, errors.New("error in mock selector")
+	}
+	gs := make([]evo.Genome, len(pop.Genomes))
+	copy(gs, pop.Genomes)
+	evo.SortBy(gs, evo.ByFitness)
+	return gs[1:], [][]evo.Genome{{gs[len(gs)-1]}}, nil
+}
+
+func (m *mockExperiment) Cross(parents ...evo.Genome) (evo.Genome, error) {
+	return evo.Genome{Traits: []float64{parents[0].Traits[0]}}, nil
+}
+
+func (m *mockExperiment) Mutate(*evo.Genome) error { return nil }
+
+func (m *mockExperiment) Search(eval evo.Evaluator, phenomes []evo.Phenome) (results []evo.Result, err error) {
+	results = make([]evo.Result, len(phenomes))
+	for i, p := range phenomes {
+		if results[i], err = eval.Evaluate(p); err != nil {
+			return
+		}
+	}
+	return
+}
+
+func (m *mockExperiment) Transcribe(enc evo.Substrate) (evo.Substrate, error) { return enc, nil }
+
+func (m *mockExperiment) Translate(evo.Substrate) (evo.Network, error) { return nil, nil }
+
+type mockEvaluator struct{}
+
+func (mockEvaluator) Evaluate(p evo.Phenome) (evo.Result, error) {
+	return evo.Result{ID: p.ID, Fitness: p.Traits[0]}, nil
+}
+
+type mockStatefulExperiment struct {
+	*mockExperiment
+	state      []byte
+	checkpoint evo.Checkpoint
+	seeded     bool
+}
+
+func (m *mockStatefulExperiment) State() ([]byte, error) { return m.state, nil }
+
+func (m *mockStatefulExperiment) Restore(b []byte) error { m.state = b; return nil }
+
+func (m *mockStatefulExperiment) Checkpoint(cp evo.Checkpoint) error { m.checkpoint = cp; return nil }
+
+func (m *mockStatefulExperiment) PopulateWith(evo.Random) (evo.Population, error) {
+	m.seeded = true
+	return m.Populate()
+}
@@ -0,0 +1,61 @@
+package island
+
+import "github.com/klokare/evo"
+
+// Topology describes which islands receive another island's migrants
+type Topology byte
+
+// Known topologies
+const (
+	Ring   Topology = iota + 1 // Each island sends to the next, with the last sending to the first
+	Full                       // Each island sends to every other island
+	Random                     // Each island sends to one other island chosen at each migration
+)
+
+func (t Topology) String() string {
+	switch t {
+	case Ring:
+		return "ring"
+	case Full:
+		return "full"
+	case Random:
+		return "random"
+	default:
+		return "unknown topology"
+	}
+}
+
+// Topologies provides a map of topologies by name
+var (
+	Topologies = map[string]Topology{
+		"ring":   Ring,
+		"full":   Full,
+		"random": Random,
+	}
+)
+
+// Destinations returns the indexes of the islands to which island i of n sends its migrants. Unknown
+// topologies are treated as a ring.
+func (t Topology) Destinations(rng evo.Random, i, n int) []int {
+	if n < 2 {
+		return nil
+	}
+	switch t {
+	case Full:
+		dst := make([]int, 0, n-1)
+		for j := 0; j < n; j++ {
+			if j != i {
+				dst = append(dst, j)
+			}
+		}
+		return dst
+	case Random:
+		j := rng.Intn(n - 1)
+		if j >= i {
+			j++
+		}
+		return []int{j}
+	default: // ring
+		return []int{(i + 1) % n}
+	}
+}
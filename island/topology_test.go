@@ -0,0 +1,46 @@
+package island
+
+import (
+	"testing"
+
+	"github.com/klokare/evo"
+)
+
+func TestTopologyDestinations(t *testing.T) {
+	var cases = []struct {
+		Desc     string
+		Topology Topology
+		Island   int
+		Islands  int
+		Expected []int
+	}{
+		{Desc: "single island", Topology: Full, Island: 0, Islands: 1},
+		{Desc: "ring", Topology: Ring, Island: 1, Islands: 3, Expected: []int{2}},
+		{Desc: "ring wraps around", Topology: Ring, Island: 2, Islands: 3, Expected: []int{0}},
+		{Desc: "unknown is ring", Topology: 0, Island: 0, Islands: 3, Expected: []int{1}},
+		{Desc: "full", Topology: Full, Island: 1, Islands: 3, Expected: []int{0, 2}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Desc, func(t *testing.T) {
+			dst := c.Topology.Destinations(evo.NewRandom(), c.Island, c.Islands)
+			if len(dst) != len(c.Expected) {
+				t.Fatalf("incorrect number of destinations: expected %d, actual %d", len(c.Expected), len(dst))
+			}
+			for i, x := range c.Expected {
+				if dst[i] != x {
+					t.Errorf("incorrect destination at %d: expected %d, actual %d", i, x, dst[i])
+				}
+			}
+		})
+	}
+
+	// Random never sends to itself
+	rng := evo.NewRandom()
+	for i := 0; i < 100; i++ {
+		dst := Random.Destinations(rng, 1, 3)
+		if len(dst) != 1 || dst[0] == 1 || dst[0] < 0 || dst[0] > 2 {
+			t.Fatalf("incorrect random destination: %v", dst)
+		}
+	}
+}
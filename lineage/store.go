@@ -0,0 +1,247 @@
+// Package lineage tracks the ancestry of genomes. Each genome created by an experiment carries the
+// IDs of its parents, its birth generation and the mutations applied to it. The store keeps these
+// records as the experiment runs so that the lineage of a genome, usually the champion, can be
+// exported in the Newick or DOT formats. As crossover joins lines of descent, the full ancestry is
+// a directed acyclic graph rather than a tree: DOT describes it faithfully while Newick, which can
+// only describe a tree, follows each genome's first parent.
+package lineage
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/klokare/evo"
+	"github.com/klokare/evo/codec"
+)
+
+// Known errors
+var (
+	ErrUnknownGenome = errors.New("genome not found in the ancestry store")
+	ErrUnknownFormat = errors.New("unknown lineage format")
+)
+
+// Format of the exported lineage
+type Format byte
+
+// Known formats
+const (
+	Newick Format = iota // Tree rooted at the genome following the line of first parents
+	DOT                  // Graphviz directed graph with edges from parent to child
+)
+
+// Formats maps the names of the formats to their values
+var Formats = map[string]Format{
+	"newick": Newick,
+	"dot":    DOT,
+}
+
+// Record of a genome in the ancestry
+type Record struct {
+	ID        int64
+	Parents   []int64
+	Birth     int
+	Mutations []string
+	Species   int
+	Fitness   float64 // Fitness when the genome was last seen
+	Solved    bool
+}
+
+// Store keeps the records of the genomes seen by the experiment. Only the records of genomes in the
+// latest population and their ancestors are kept. Lines of descent which die out are removed, but
+// those which survive reach back to the initial population so the store still grows with the
+// length of the run. It is safe for concurrent use.
+type Store struct {
+	mu      sync.Mutex
+	records map[int64]Record
+}
+
+// NewStore returns a new, empty ancestry store
+func NewStore() *Store {
+	return &Store{records: make(map[int64]Record, 1000)}
+}
+
+// Add records the genomes, replacing any earlier records with the same IDs
+func (s *Store) Add(genomes ...evo.Genome) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.records == nil {
+		s.records = make(map[int64]Record, 1000)
+	}
+	for _, g := range genomes {
+		s.records[g.ID] = Record{
+			ID:        g.ID,
+			Parents:   g.Parents,
+			Birth:     g.Birth,
+			Mutations: g.Mutations,
+			Species:   g.Species,
+			Fitness:   g.Fitness,
+			Solved:    g.Solved,
+		}
+	}
+}
+
+// Lookup returns the record of the genome, if kept
+func (s *Store) Lookup(id int64) (r Record, ok bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r, ok = s.records[id]
+	return
+}
+
+// Len returns the number of records kept
+func (s *Store) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.records)
+}
+
+// Record adds the population's genomes and removes the records of genomes that are no longer in
+// the population and are not ancestors of those that are. It is a callback, usually subscribed
+// to the Evaluated event so that the records carry the genomes' latest fitness. Subscribe it to the
+// Started event as well to record the initial genomes, which may not survive to be evaluated.
+func (s *Store) Record(pop evo.Population) error {
+	s.Add(pop.Genomes...)
+	ids := make([]int64, len(pop.Genomes))
+	for i, g := range pop.Genomes {
+		ids[i] = g.ID
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	keep := s.ancestry(ids...)
+	for id := range s.records {
+		if !keep[id] {
+			delete(s.records, id)
+		}
+	}
+	return nil
+}
+
+// Ancestors returns the record of the genome and those of its ancestors that are known to the
+// store, ordered by birth and then ID
+func (s *Store) Ancestors(id int64) (rs []Record, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.records[id]; !ok {
+		return nil, ErrUnknownGenome
+	}
+	for x := range s.ancestry(id) {
+		rs = append(rs, s.records[x])
+	}
+	sort.Slice(rs, func(i, j int) bool {
+		if rs[i].Birth == rs[j].Birth {
+			return rs[i].ID < rs[j].ID
+		}
+		return rs[i].Birth < rs[j].Birth
+	})
+	return
+}
+
+// Write the lineage of the genome to the writer in the format
+func (s *Store) Write(w io.Writer, id int64, f Format) (err error) {
+	var rs []Record
+	if rs, err = s.Ancestors(id); err != nil {
+		return
+	}
+	byID := make(map[int64]Record, len(rs))
+	for _, r := range rs {
+		byID[r.ID] = r
+	}
+	bw := bufio.NewWriter(w)
+	switch f {
+	case Newick:
+		writeNewick(bw, byID, id)
+		bw.WriteString(";\n")
+	case DOT:
+		writeDOT(bw, rs, id)
+	default:
+		return ErrUnknownFormat
+	}
+	return bw.Flush()
+}
+
+// WriteFile writes the lineage of the genome to the file in the format
+func (s *Store) WriteFile(filename string, id int64, f Format) (err error) {
+	var file *os.File
+	if file, err = os.Create(filename); err != nil {
+		return
+	}
+	if err = s.Write(file, id, f); err != nil {
+		file.Close() // ignore error as it would overwrite the write one
+		return
+	}
+	return file.Close()
+}
+
+// SaveChampion returns a callback, usually subscribed to the Completed event, that writes the
+// lineage of the best genome in the population to the file in the format. See codec.Best for the
+// comparisons.
+func (s *Store) SaveChampion(filename string, f Format, comparisons ...evo.Comparison) evo.Callback {
+	return func(pop evo.Population) error {
+		if len(pop.Genomes) == 0 {
+			return nil
+		}
+		best := codec.Best(pop, comparisons...)
+		s.Add(best) // ensure the champion's latest state is known
+		return s.WriteFile(filename, best.ID, f)
+	}
+}
+
+// Returns the IDs of the genomes and their known ancestors. The store must be locked by the
+// caller.
+func (s *Store) ancestry(ids ...int64) map[int64]bool {
+	seen := make(map[int64]bool, len(s.records))
+	for len(ids) > 0 {
+		id := ids[len(ids)-1]
+		ids = ids[:len(ids)-1]
+		r, ok := s.records[id]
+		if !ok || seen[id] {
+			continue
+		}
+		seen[id] = true
+		ids = append(ids, r.Parents...)
+	}
+	return seen
+}
+
+// Write the genome's line of first parents as a Newick tree with the genome at its root. Branch
+// lengths are the generations between the births of parent and child. Other parents are left out as
+// a Newick tree cannot share an ancestor between branches.
+func writeNewick(w *bufio.Writer, rs map[int64]Record, id int64) {
+	if r, ok := rs[id]; ok && len(r.Parents) > 0 {
+		p := r.Parents[0]
+		w.WriteByte('(')
+		writeNewick(w, rs, p)
+		if pr, ok := rs[p]; ok {
+			fmt.Fprintf(w, ":%d", r.Birth-pr.Birth)
+		}
+		w.WriteByte(')')
+	}
+	fmt.Fprintf(w, "g%d", id)
+}
+
+// Write the genome and its ancestors as a DOT graph with an edge from each parent to its child,
+// labelled with the mutations applied to the child. The genome itself is drawn in bold.
+func writeDOT(w *bufio.Writer, rs []Record, id int64) {
+	w.WriteString("digraph lineage {\n")
+	w.WriteString("\tnode [shape=box];\n")
+	for _, r := range rs {
+		style := ""
+		if r.ID == id {
+			style = ", style=bold"
+		}
+		fmt.Fprintf(w, "\tg%d [label=\"%d\\ngeneration %d\\nspecies %d\\nfitness %g\"%s];\n", r.ID, r.ID, r.Birth, r.Species, r.Fitness, style)
+	}
+	for _, r := range rs {
+		label := strings.Join(r.Mutations, ", ")
+		for _, p := range r.Parents {
+			fmt.Fprintf(w, "\tg%d -> g%d [label=\"%s\"];\n", p, r.ID, label)
+		}
+	}
+	w.WriteString("}\n")
+}
@@ -0,0 +1,127 @@
+package lineage
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/klokare/evo"
+)
+
+// Populations of a short run. Genome 5 is the child of 3 and 4, which share parent 2.
+var pops = []evo.Population{
+	{Generation: 0, Genomes: []evo.Genome{{ID: 1}, {ID: 2}}},
+	{Generation: 1, Genomes: []evo.Genome{
+		{ID: 2},
+		{ID: 3, Parents: []int64{1, 2}, Birth: 1, Mutations: []string{"weight"}},
+		{ID: 4, Parents: []int64{2}, Birth: 1, Mutations: []string{"add-node"}},
+	}},
+	{Generation: 2, Genomes: []evo.Genome{
+		{ID: 4, Parents: []int64{2}, Birth: 1, Mutations: []string{"add-node"}},
+		{ID: 5, Parents: []int64{3, 4}, Birth: 2, Mutations: []string{"bias", "weight"}, Fitness: 3.5, Solved: true},
+	}},
+}
+
+func newTestStore(t *testing.T) *Store {
+	s := NewStore()
+	for _, pop := range pops {
+		if err := s.Record(pop); err != nil {
+			t.Fatalf("error not expected: %v", err)
+		}
+	}
+	return s
+}
+
+func TestStoreAncestors(t *testing.T) {
+	s := newTestStore(t)
+
+	// All genomes are ancestors of the latest population
+	if s.Len() != 5 {
+		t.Errorf("incorrect number of records: expected 5, actual %d", s.Len())
+	}
+
+	// The ancestors are ordered by birth and then ID
+	rs, err := s.Ancestors(5)
+	if err != nil {
+		t.Fatalf("error not expected: %v", err)
+	}
+	ids := make([]int64, len(rs))
+	for i, r := range rs {
+		ids[i] = r.ID
+	}
+	if expected := []int64{1, 2, 3, 4, 5}; !equal(ids, expected) {
+		t.Errorf("incorrect ancestors: expected %v, actual %v", expected, ids)
+	}
+	if rs, _ = s.Ancestors(4); len(rs) != 2 {
+		t.Errorf("incorrect number of ancestors of genome 4: expected 2, actual %d", len(rs))
+	}
+	if _, err = s.Ancestors(9); err != ErrUnknownGenome {
+		t.Errorf("incorrect error: expected %v, actual %v", ErrUnknownGenome, err)
+	}
+
+	// Genomes that are neither in the population nor ancestors of it are removed
+	if err = s.Record(evo.Population{Generation: 3, Genomes: []evo.Genome{{ID: 6, Parents: []int64{4}, Birth: 3}}}); err != nil {
+		t.Fatalf("error not expected: %v", err)
+	}
+	for _, id := range []int64{1, 3, 5} {
+		if _, ok := s.Lookup(id); ok {
+			t.Errorf("genome %d should have been removed", id)
+		}
+	}
+	for _, id := range []int64{2, 4, 6} {
+		if _, ok := s.Lookup(id); !ok {
+			t.Errorf("genome %d should have been kept", id)
+		}
+	}
+}
+
+func TestStoreWrite(t *testing.T) {
+	s := newTestStore(t)
+
+	// The Newick tree is rooted at the genome and follows its first parents so that the shared
+	// ancestor does not appear twice
+	b := new(bytes.Buffer)
+	if err := s.Write(b, 5, Newick); err != nil {
+		t.Fatalf("error not expected: %v", err)
+	}
+	if expected := "((g1:1)g3:1)g5;\n"; b.String() != expected {
+		t.Errorf("incorrect newick tree:\nexpected %s\nactual   %s", expected, b.String())
+	}
+
+	// The DOT graph has a node for each ancestor and an edge from each parent
+	b.Reset()
+	if err := s.Write(b, 5, DOT); err != nil {
+		t.Fatalf("error not expected: %v", err)
+	}
+	for _, x := range []string{
+		"digraph lineage {",
+		`g5 [label="5\ngeneration 2\nspecies 0\nfitness 3.5", style=bold];`,
+		`g3 -> g5 [label="bias, weight"];`,
+		`g4 -> g5 [label="bias, weight"];`,
+		`g2 -> g4 [label="add-node"];`,
+	} {
+		if !strings.Contains(b.String(), x) {
+			t.Errorf("DOT graph does not contain %s:\n%s", x, b.String())
+		}
+	}
+	if n := strings.Count(b.String(), "->"); n != 5 {
+		t.Errorf("incorrect number of edges: expected 5, actual %d", n)
+	}
+
+	// Unknown format
+	if err := s.Write(b, 5, Format(9)); err != ErrUnknownFormat {
+		t.Errorf("incorrect error: expected %v, actual %v", ErrUnknownFormat, err)
+	}
+}
+
+func equal(a, b []int64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
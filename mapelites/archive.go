@@ -0,0 +1,164 @@
+// Package mapelites provides the MAP-Elites quality-diversity algorithm of Mouret and Clune. Each
+// genome's behavior is mapped to a cell in a grid of behavior descriptors and only the best genome,
+// or elite, of each cell is kept. Offspring are bred from elites chosen uniformly from the occupied
+// cells so that the search illuminates the whole behavior space rather than only its best region.
+package mapelites
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"sync"
+
+	"github.com/klokare/evo"
+	"github.com/klokare/evo/codec"
+	"github.com/klokare/evo/novelty"
+)
+
+// Known errors
+var (
+	ErrInvalidGrid     = errors.New("grid requires bins, minimums and maximums for each dimension")
+	ErrInvalidBehavior = errors.New("genome behavior must be a float64 or a slice of float64 with a value for each grid dimension")
+	ErrEmptyArchive    = errors.New("archive has no elites from which to select parents")
+)
+
+// Grid divides the behavior space into cells. Each dimension of the behavior is divided evenly into
+// the number of bins between its minimum and maximum. Values outside the range are placed in the
+// first or last bin.
+type Grid struct {
+	Bins     []int     // Number of bins in each dimension
+	Min, Max []float64 // Range of each dimension
+}
+
+// Size returns the number of cells in the grid
+func (g Grid) Size() int {
+	if len(g.Bins) == 0 {
+		return 0
+	}
+	n := 1
+	for _, b := range g.Bins {
+		n *= b
+	}
+	return n
+}
+
+// Cell returns the index of the cell containing the behavior
+func (g Grid) Cell(behavior []float64) (cell int, err error) {
+
+	// Check for errors
+	if len(g.Bins) == 0 || len(g.Min) != len(g.Bins) || len(g.Max) != len(g.Bins) {
+		err = ErrInvalidGrid
+		return
+	}
+	if len(behavior) != len(g.Bins) {
+		err = ErrInvalidBehavior
+		return
+	}
+
+	// Combine the bins of each dimension
+	for d, x := range behavior {
+		if g.Bins[d] < 1 || g.Max[d] <= g.Min[d] {
+			err = ErrInvalidGrid
+			return
+		}
+		var b int
+		switch f := (x - g.Min[d]) / (g.Max[d] - g.Min[d]) * float64(g.Bins[d]); {
+		case math.IsNaN(f) || f < 0:
+			b = 0
+		case f >= float64(g.Bins[d]):
+			b = g.Bins[d] - 1
+		default:
+			b = int(f)
+		}
+		cell = cell*g.Bins[d] + b
+	}
+	return
+}
+
+// Archive keeps the elite genome of each cell of the grid. It is safe for concurrent use.
+type Archive struct {
+	Grid
+	Comparison evo.Comparison // Decides which of two genomes in a cell is the elite. If zero, fitness is used.
+
+	mu     sync.Mutex
+	elites map[int]evo.Genome
+}
+
+// Add the genomes to the archive, each replacing the elite of its cell if it is better. Genomes
+// without a behavior, which have not yet been evaluated, are ignored. The number of genomes added
+// is returned.
+func (a *Archive) Add(genomes ...evo.Genome) (added int, err error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.elites == nil {
+		a.elites = make(map[int]evo.Genome, a.Size())
+	}
+	for _, g := range genomes {
+		if g.Behavior == nil {
+			continue
+		}
+		var b []float64
+		if b, err = novelty.Behavior(g.Behavior); err != nil {
+			err = ErrInvalidBehavior
+			return
+		}
+		g.Behavior = b // elites decoded from JSON keep their behavior as a slice of float64
+		var cell int
+		if cell, err = a.Cell(b); err != nil {
+			return
+		}
+		if e, ok := a.elites[cell]; ok && a.Comparison.Compare(g, e) <= 0 {
+			continue // the existing elite is at least as good
+		}
+		a.elites[cell] = g
+		added++
+	}
+	return
+}
+
+// Update is a callback, usually subscribed to the Evaluated event, that adds the population's
+// genomes to the archive
+func (a *Archive) Update(pop evo.Population) error {
+	_, err := a.Add(pop.Genomes...)
+	return err
+}
+
+// Len returns the number of occupied cells
+func (a *Archive) Len() int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return len(a.elites)
+}
+
+// Coverage returns the fraction of the grid's cells that are occupied
+func (a *Archive) Coverage() float64 {
+	n := a.Size()
+	if n == 0 {
+		return 0.0
+	}
+	return float64(a.Len()) / float64(n)
+}
+
+// Elites returns the elite of each occupied cell in the order of the cells
+func (a *Archive) Elites() []evo.Genome {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	cells := make([]int, 0, len(a.elites))
+	for c := range a.elites {
+		cells = append(cells, c)
+	}
+	sort.Ints(cells)
+	genomes := make([]evo.Genome, len(cells))
+	for i, c := range cells {
+		genomes[i] = a.elites[c]
+	}
+	return genomes
+}
+
+// Export returns a callback, usually subscribed to the Completed event, that writes the elites to
+// the file in the format as a population of the current generation
+func (a *Archive) Export(filename string, f codec.Format) evo.Callback {
+	return func(pop evo.Population) error {
+		return codec.WriteFile(filename, f, evo.Population{Generation: pop.Generation, Genomes: a.Elites()})
+	}
+}
@@ -0,0 +1,125 @@
+package mapelites
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/klokare/evo"
+	"github.com/klokare/evo/codec"
+)
+
+func TestGridCell(t *testing.T) {
+	grid := Grid{Bins: []int{4, 2}, Min: []float64{0, -1}, Max: []float64{1, 1}}
+	var cases = []struct {
+		Desc     string
+		Grid     Grid
+		Behavior []float64
+		Expected int
+		HasError bool
+	}{
+		{Desc: "first cell", Grid: grid, Behavior: []float64{0, -1}, Expected: 0},
+		{Desc: "second dimension", Grid: grid, Behavior: []float64{0, 0}, Expected: 1},
+		{Desc: "first dimension", Grid: grid, Behavior: []float64{0.3, -0.5}, Expected: 2},
+		{Desc: "last cell", Grid: grid, Behavior: []float64{0.99, 0.99}, Expected: 7},
+		{Desc: "above the range", Grid: grid, Behavior: []float64{2, 5}, Expected: 7},
+		{Desc: "below the range", Grid: grid, Behavior: []float64{-2, -5}, Expected: 0},
+		{Desc: "wrong behavior length", Grid: grid, Behavior: []float64{0}, HasError: true},
+		{Desc: "no bins", Grid: Grid{}, Behavior: []float64{}, HasError: true},
+		{Desc: "missing range", Grid: Grid{Bins: []int{2}}, Behavior: []float64{0}, HasError: true},
+		{Desc: "empty range", Grid: Grid{Bins: []int{2}, Min: []float64{1}, Max: []float64{1}}, Behavior: []float64{0}, HasError: true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Desc, func(t *testing.T) {
+			x, err := c.Grid.Cell(c.Behavior)
+			if c.HasError {
+				if err == nil {
+					t.Error("expected error not found")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("error not expected: %v", err)
+			}
+			if x != c.Expected {
+				t.Errorf("incorrect cell: expected %d, actual %d", c.Expected, x)
+			}
+		})
+	}
+	if n := grid.Size(); n != 8 {
+		t.Errorf("incorrect grid size: expected 8, actual %d", n)
+	}
+}
+
+func TestArchiveAdd(t *testing.T) {
+	a := &Archive{Grid: Grid{Bins: []int{2}, Min: []float64{0}, Max: []float64{1}}}
+
+	// The best genome in each cell is kept and unevaluated genomes are ignored
+	n, err := a.Add(
+		evo.Genome{ID: 1, Fitness: 1.0, Behavior: []float64{0.9}},
+		evo.Genome{ID: 2, Fitness: 2.0, Behavior: []float64{0.8}},
+		evo.Genome{ID: 3, Fitness: 1.5, Behavior: []float64{0.7}},
+		evo.Genome{ID: 4, Fitness: 0.5, Behavior: []float64{0.1}},
+		evo.Genome{ID: 5},
+	)
+	if err != nil {
+		t.Fatalf("error not expected: %v", err)
+	}
+	if n != 3 {
+		t.Errorf("incorrect number added: expected 3, actual %d", n)
+	}
+	elites := a.Elites()
+	if len(elites) != 2 || elites[0].ID != 4 || elites[1].ID != 2 {
+		t.Errorf("incorrect elites: expected 4 and 2, actual %v", elites)
+	}
+	if x := a.Coverage(); x != 1.0 {
+		t.Errorf("incorrect coverage: expected 1.0, actual %f", x)
+	}
+
+	// Other comparisons can decide the elite
+	a = &Archive{Grid: a.Grid, Comparison: evo.ByNovelty}
+	a.Add(evo.Genome{ID: 1, Fitness: 2.0, Behavior: []float64{0.9}}, evo.Genome{ID: 2, Novelty: 1.0, Behavior: []float64{0.9}})
+	if elites = a.Elites(); len(elites) != 1 || elites[0].ID != 2 {
+		t.Errorf("incorrect elites by novelty: expected 2, actual %v", elites)
+	}
+
+	// Behaviors decoded from JSON are accepted
+	if n, err = a.Add(evo.Genome{ID: 7, Novelty: 2.0, Behavior: []interface{}{0.2}}); err != nil {
+		t.Fatalf("error not expected: %v", err)
+	}
+	if n != 1 {
+		t.Errorf("incorrect number added: expected 1, actual %d", n)
+	}
+	if elites = a.Elites(); len(elites) != 2 || elites[0].ID != 7 {
+		t.Errorf("incorrect elites with decoded behavior: expected 7 and 2, actual %v", elites)
+	}
+
+	// Behaviors must be floats or slices of floats
+	if _, err = a.Add(evo.Genome{ID: 6, Behavior: "bad"}); err != ErrInvalidBehavior {
+		t.Errorf("incorrect error: expected %v, actual %v", ErrInvalidBehavior, err)
+	}
+}
+
+func TestArchiveExport(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mapelites")
+	if err != nil {
+		t.Fatalf("error not expected: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	a := &Archive{Grid: Grid{Bins: []int{2}, Min: []float64{0}, Max: []float64{1}}}
+	a.Add(evo.Genome{ID: 1, Behavior: []float64{0.9}}, evo.Genome{ID: 2, Behavior: []float64{0.1}})
+	filename := filepath.Join(dir, "elites.json")
+	if err = a.Export(filename, codec.JSON)(evo.Population{Generation: 5}); err != nil {
+		t.Fatalf("error not expected: %v", err)
+	}
+	var pop evo.Population
+	if err = codec.ReadFile(filename, &pop); err != nil {
+		t.Fatalf("error not expected: %v", err)
+	}
+	if pop.Generation != 5 || len(pop.Genomes) != 2 || pop.Genomes[0].ID != 2 {
+		t.Errorf("incorrect exported population: %+v", pop)
+	}
+}
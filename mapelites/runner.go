@@ -0,0 +1,188 @@
+package mapelites
+
+import (
+	"context"
+	"math"
+
+	"github.com/klokare/evo"
+	"github.com/klokare/evo/codec"
+	"github.com/klokare/evo/config"
+)
+
+// Selector adds each evaluated population to the archive and then breeds the next population from
+// elites chosen uniformly from the archive's occupied cells. No genomes continue as the elites are
+// kept by the archive. Until the archive has elites, as when the seed population has not yet been
+// evaluated, the population's own genomes are used as parents.
+type Selector struct {
+	Archive               *Archive
+	BatchSize             int     // Number of offspring in each generation. If zero, the population's size is used.
+	MutateOnlyProbability float64 // Probability that an offspring has a single parent
+}
+
+// Select the parents of the next generation from the archive
+func (s Selector) Select(pop evo.Population) (continuing []evo.Genome, parents [][]evo.Genome, err error) {
+	return s.SelectWith(evo.NewRandom(), pop)
+}
+
+// SelectWith selects the parents from the archive, drawing from the random stream
+func (s Selector) SelectWith(rng evo.Random, pop evo.Population) (continuing []evo.Genome, parents [][]evo.Genome, err error) {
+
+	// Update the archive with the latest genomes
+	if _, err = s.Archive.Add(pop.Genomes...); err != nil {
+		return
+	}
+	elites := s.Archive.Elites()
+	if len(elites) == 0 {
+		elites = pop.Genomes
+	}
+	if len(elites) == 0 {
+		err = ErrEmptyArchive
+		return
+	}
+
+	// Choose the parents uniformly from the occupied cells
+	n := s.BatchSize
+	if n <= 0 {
+		n = len(pop.Genomes)
+	}
+	parents = make([][]evo.Genome, n)
+	for i := range parents {
+		if rng.Float64() < s.MutateOnlyProbability {
+			parents[i] = []evo.Genome{elites[rng.Intn(len(elites))]}
+		} else {
+			parents[i] = []evo.Genome{elites[rng.Intn(len(elites))], elites[rng.Intn(len(elites))]}
+		}
+	}
+	return
+}
+
+// Runner runs an experiment in the MAP-Elites mode. The experiment's own selector is replaced by
+// one that breeds from the archive so that its crosser, such as neat.Crosser, and its mutators
+// provide the variation. The evaluator must describe each phenome's behavior as a []float64 with a
+// value for each dimension of the archive's grid. Checkpointing is not supported in this mode.
+type Runner struct {
+	*Archive
+	BatchSize             int          // Number of offspring in each generation. If zero, the initial population's size is used.
+	MutateOnlyProbability float64      // Probability that an offspring has a single parent
+	Filename              string       // File to which the elites are exported upon completion, if any
+	Format                codec.Format // Format of the exported elites. If zero, JSON is used.
+}
+
+// Keys used by the MAP-Elites runner. NewRunner declares them with the configurer.
+var Keys = []config.Key{
+	{Name: "mapelites|grid|bins", Type: config.Ints, Description: "Number of bins in each dimension of the archive"},
+	{Name: "mapelites|grid|min", Type: config.Float64s, Description: "Minimum behavior value in each dimension"},
+	{Name: "mapelites|grid|max", Type: config.Float64s, Description: "Maximum behavior value in each dimension"},
+	{Name: "mapelites|archive|comparison", Type: config.Comparison, Description: "Comparison deciding which genome is kept in a cell"},
+	{Name: "mapelites|selector|batch-size", Type: config.Int, Min: 0.0, Max: math.Inf(1), Description: "Number of offspring in each generation. The initial population size is used if zero."},
+	{Name: "mapelites|selector|mutate-only-probability", Type: config.Float64, Min: 0.0, Max: 1.0, Description: "Probability that an offspring has a single parent"},
+	{Name: "mapelites|runner|filename", Type: config.String, Description: "File to which the elites are exported upon completion"},
+	{Name: "mapelites|runner|format", Type: config.String, Description: "Format of the exported elites: json or binary"},
+}
+
+// NewRunner creates a new MAP-Elites runner, with an empty archive, using the configuration
+func NewRunner(cfg config.Configurer) *Runner {
+	cfg.Declare(Keys...)
+	return &Runner{
+		Archive: &Archive{
+			Grid: Grid{
+				Bins: cfg.Ints("mapelites|grid|bins"),
+				Min:  cfg.Float64s("mapelites|grid|min"),
+				Max:  cfg.Float64s("mapelites|grid|max"),
+			},
+			Comparison: cfg.Comparison("mapelites|archive|comparison"),
+		},
+		BatchSize:             cfg.Int("mapelites|selector|batch-size"),
+		MutateOnlyProbability: cfg.Float64("mapelites|selector|mutate-only-probability"),
+		Filename:              cfg.String("mapelites|runner|filename"),
+		Format:                codec.Formats[cfg.String("mapelites|runner|format")],
+	}
+}
+
+// Run the experiment in the given context with the evaluator and return the elites as a population
+// of the final generation. The archive is kept by the runner so it may be inspected or reused.
+func (r Runner) Run(ctx context.Context, exp evo.Experiment, eval evo.Evaluator) (pop evo.Population, err error) {
+
+	// Check for errors
+	if r.Archive == nil {
+		err = ErrInvalidGrid
+		return
+	}
+	if _, err = r.Cell(make([]float64, len(r.Bins))); err != nil {
+		return
+	}
+
+	// Wrap the experiment with the archive's selector. The final population is added to the
+	// archive, and the elites exported, upon completion.
+	x := &experiment{
+		Experiment: exp,
+		selector:   Selector{Archive: r.Archive, BatchSize: r.BatchSize, MutateOnlyProbability: r.MutateOnlyProbability},
+	}
+	if sp, ok := exp.(evo.SubscriptionProvider); ok {
+		x.subscriptions = append(x.subscriptions, sp.Subscriptions()...)
+	}
+	x.subscriptions = append(x.subscriptions, evo.Subscription{Event: evo.Completed, Callback: r.complete})
+
+	// Run the experiment and return the elites
+	if pop, err = evo.Run(ctx, x, eval); err != nil {
+		return
+	}
+	pop.Genomes = r.Elites()
+	return
+}
+
+// Complete adds the final population to the archive and exports the elites, if requested
+func (r Runner) complete(pop evo.Population) (err error) {
+	if err = r.Update(pop); err != nil {
+		return
+	}
+	if r.Filename != "" {
+		err = r.Export(r.Filename, r.Format)(pop)
+	}
+	return
+}
+
+// The experiment in MAP-Elites mode
+type experiment struct {
+	evo.Experiment
+	selector      Selector
+	subscriptions []evo.Subscription
+}
+
+// Select the parents from the archive
+func (e *experiment) Select(pop evo.Population) ([]evo.Genome, [][]evo.Genome, error) {
+	return e.selector.Select(pop)
+}
+
+// SelectWith selects the parents from the archive, drawing from the random stream
+func (e *experiment) SelectWith(rng evo.Random, pop evo.Population) ([]evo.Genome, [][]evo.Genome, error) {
+	return e.selector.SelectWith(rng, pop)
+}
+
+// PopulateWith creates the initial population, drawing from the random stream if the experiment
+// supports it
+func (e *experiment) PopulateWith(rng evo.Random) (evo.Population, error) {
+	if px, ok := e.Experiment.(evo.RandomPopulator); ok {
+		return px.PopulateWith(rng)
+	}
+	return e.Experiment.Populate()
+}
+
+// CrossWith crosses the parents, drawing from the random stream if the experiment supports it
+func (e *experiment) CrossWith(rng evo.Random, parents ...evo.Genome) (evo.Genome, error) {
+	if cx, ok := e.Experiment.(evo.RandomCrosser); ok {
+		return cx.CrossWith(rng, parents...)
+	}
+	return e.Experiment.Cross(parents...)
+}
+
+// MutateWith mutates the genome, drawing from the random stream if the experiment supports it
+func (e *experiment) MutateWith(rng evo.Random, g *evo.Genome) error {
+	if mx, ok := e.Experiment.(evo.RandomMutator); ok {
+		return mx.MutateWith(rng, g)
+	}
+	return e.Experiment.Mutate(g)
+}
+
+// Subscriptions returns the wrapped experiment's subscriptions and the runner's own
+func (e *experiment) Subscriptions() []evo.Subscription { return e.subscriptions }
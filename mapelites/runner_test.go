@@ -0,0 +1,188 @@
+package mapelites
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/klokare/evo"
+	"github.com/klokare/evo/searcher/serial"
+)
+
+func TestSelectorSelect(t *testing.T) {
+	a := &Archive{Grid: Grid{Bins: []int{10}, Min: []float64{0}, Max: []float64{1}}}
+	a.Add(evo.Genome{ID: 1, Behavior: []float64{0.15}}, evo.Genome{ID: 2, Behavior: []float64{0.85}})
+
+	// Parents come from the archive and the population, which replaces the archive's elite
+	pop := evo.Population{Genomes: []evo.Genome{{ID: 3, Fitness: 1.0, Behavior: []float64{0.12}}, {ID: 4}}}
+	s := Selector{Archive: a, BatchSize: 20, MutateOnlyProbability: 0.5}
+	continuing, parents, err := s.Select(pop)
+	if err != nil {
+		t.Fatalf("error not expected: %v", err)
+	}
+	if len(continuing) != 0 {
+		t.Errorf("incorrect number of continuing: expected 0, actual %d", len(continuing))
+	}
+	if len(parents) != 20 {
+		t.Errorf("incorrect number of parents: expected 20, actual %d", len(parents))
+	}
+	for _, ps := range parents {
+		if len(ps) < 1 || len(ps) > 2 {
+			t.Errorf("incorrect number of parents in group: %d", len(ps))
+		}
+		for _, p := range ps {
+			if p.ID != 2 && p.ID != 3 {
+				t.Errorf("parent %d is not an elite", p.ID)
+			}
+		}
+	}
+
+	// An empty archive uses the population
+	s = Selector{Archive: &Archive{Grid: a.Grid}}
+	if _, parents, err = s.Select(evo.Population{Genomes: []evo.Genome{{ID: 5}}}); err != nil {
+		t.Fatalf("error not expected: %v", err)
+	}
+	if len(parents) != 1 || parents[0][0].ID != 5 {
+		t.Errorf("incorrect parents from the population: %v", parents)
+	}
+	if _, _, err = s.Select(evo.Population{}); err != ErrEmptyArchive {
+		t.Errorf("incorrect error: expected %v, actual %v", ErrEmptyArchive, err)
+	}
+}
+
+func TestRunnerRun(t *testing.T) {
+
+	// Run the experiment
+	ctx, fn, cb := evo.WithIterations(context.Background(), 20)
+	defer fn()
+	exp := &mockExperiment{PopSize: 10}
+	exp.subscriptions = []evo.Subscription{{Event: evo.Evaluated, Callback: cb}}
+	r := Runner{Archive: &Archive{Grid: Grid{Bins: []int{5}, Min: []float64{0}, Max: []float64{1}}}, BatchSize: 10}
+	pop, err := r.Run(ctx, exp, mockEvaluator{})
+	if err != nil {
+		t.Fatalf("error not expected: %v", err)
+	}
+
+	// The experiment's own selector is not used and the elites are returned
+	if exp.selected {
+		t.Error("experiment's selector should not be called")
+	}
+	if len(pop.Genomes) != r.Len() || r.Len() == 0 {
+		t.Errorf("incorrect number of elites: expected %d, actual %d", r.Len(), len(pop.Genomes))
+	}
+	if pop.Generation != 20 {
+		t.Errorf("incorrect generation: expected 20, actual %d", pop.Generation)
+	}
+
+	// Errors
+	if _, err = (Runner{}).Run(ctx, exp, mockEvaluator{}); err != ErrInvalidGrid {
+		t.Errorf("incorrect error: expected %v, actual %v", ErrInvalidGrid, err)
+	}
+}
+
+// Mock experiment sets a random trait on mutation which the evaluator uses as the behavior
+func TestExperimentForwarding(t *testing.T) {
+
+	// The random streams are passed to the wrapped experiment
+	m := &mockRandomExperiment{mockExperiment: &mockExperiment{PopSize: 2}}
+	x := &experiment{Experiment: m}
+	pop, err := x.PopulateWith(evo.NewRandom())
+	if err != nil {
+		t.Fatalf("error not expected: %v", err)
+	}
+	child, err := x.CrossWith(evo.NewRandom(), pop.Genomes...)
+	if err != nil {
+		t.Fatalf("error not expected: %v", err)
+	}
+	if err = x.MutateWith(evo.NewRandom(), &child); err != nil {
+		t.Fatalf("error not expected: %v", err)
+	}
+	if m.calls != 3 {
+		t.Errorf("incorrect number of calls with a stream: expected 3, actual %d", m.calls)
+	}
+
+	// Experiments without them fall back to their plain methods
+	x = &experiment{Experiment: m.mockExperiment}
+	if pop, err = x.PopulateWith(evo.NewRandom()); err != nil {
+		t.Fatalf("error not expected: %v", err)
+	}
+	if child, err = x.CrossWith(evo.NewRandom(), pop.Genomes...); err != nil {
+		t.Fatalf("error not expected: %v", err)
+	}
+	if err = x.MutateWith(evo.NewRandom(), &child); err != nil {
+		t.Fatalf("error not expected: %v", err)
+	}
+}
+
+type mockExperiment struct {
+	PopSize int
+
+	subscriptions []evo.Subscription
+	selected      bool
+	rng           evo.Random
+}
+
+func (m *mockExperiment) Subscriptions() []evo.Subscription { return m.subscriptions }
+
+func (m *mockExperiment) Populate() (pop evo.Population, err error) {
+	m.rng = evo.NewRandom()
+	pop.Genomes = make([]evo.Genome, m.PopSize)
+	for i := range pop.Genomes {
+		pop.Genomes[i] = evo.Genome{ID: int64(i + 1), Traits: []float64{0.5}}
+	}
+	return
+}
+
+func (m *mockExperiment) Speciate(*evo.Population) error { return nil }
+
+func (m *mockExperiment) Select(pop evo.Population) ([]evo.Genome, [][]evo.Genome, error) {
+	m.selected = true
+	return nil, nil, errors.New("mock selector should not be called")
+}
+
+func (m *mockExperiment) Cross(parents ...evo.Genome) (evo.Genome, error) {
+	return evo.Genome{Traits: []float64{parents[0].Traits[0]}}, nil
+}
+
+func (m *mockExperiment) Mutate(g *evo.Genome) error {
+	g.Traits[0] = m.rng.Float64()
+	return nil
+}
+
+func (m *mockExperiment) Search(eval evo.Evaluator, phenomes []evo.Phenome) ([]evo.Result, error) {
+	return serial.Searcher{}.Search(eval, phenomes)
+}
+
+func (m *mockExperiment) Transcribe(enc evo.Substrate) (evo.Substrate, error) { return enc, nil }
+
+func (m *mockExperiment) Translate(evo.Substrate) (evo.Network, error) { return mockNetwork{}, nil }
+
+type mockNetwork struct{}
+
+func (mockNetwork) Activate(evo.Matrix) (evo.Matrix, error) { return nil, nil }
+
+type mockEvaluator struct{}
+
+func (mockEvaluator) Evaluate(p evo.Phenome) (evo.Result, error) {
+	return evo.Result{ID: p.ID, Fitness: 1.0 - p.Traits[0], Behavior: []float64{p.Traits[0]}}, nil
+}
+
+type mockRandomExperiment struct {
+	*mockExperiment
+	calls int
+}
+
+func (m *mockRandomExperiment) PopulateWith(evo.Random) (evo.Population, error) {
+	m.calls++
+	return m.Populate()
+}
+
+func (m *mockRandomExperiment) CrossWith(rng evo.Random, parents ...evo.Genome) (evo.Genome, error) {
+	m.calls++
+	return m.Cross(parents...)
+}
+
+func (m *mockRandomExperiment) MutateWith(rng evo.Random, g *evo.Genome) error {
+	m.calls++
+	return m.Mutate(g)
+}
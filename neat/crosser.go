@@ -24,6 +24,11 @@ type Crosser struct {
 // Cross the parents and create a new offspring, using the sequence to assign a new ID. There is a
 // chance that connections disabled in one of the parents will also be disabled in the child.
 func (z *Crosser) Cross(parents ...evo.Genome) (child evo.Genome, err error) {
+	return z.CrossWith(evo.NewRandom(), parents...)
+}
+
+// CrossWith crosses the parents, drawing from the random stream, to create a new offspring
+func (z *Crosser) CrossWith(rng evo.Random, parents ...evo.Genome) (child evo.Genome, err error) {
 
 	// Check for errors
 	if len(parents) == 0 {
@@ -35,7 +40,6 @@ func (z *Crosser) Cross(parents ...evo.Genome) (child evo.Genome, err error) {
 	}
 
 	// Special case: single parent
-	rng := evo.NewRandom()
 	p1 := parents[0]
 	if len(parents) == 1 {
 
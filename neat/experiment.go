@@ -1,16 +1,30 @@
 package neat
 
 import (
+	"encoding/json"
+	"errors"
+
 	"github.com/klokare/evo"
 	"github.com/klokare/evo/config"
 	"github.com/klokare/evo/neat/mutator"
+	"github.com/klokare/evo/network/ctrnn"
 	"github.com/klokare/evo/network/forward"
+	"github.com/klokare/evo/network/plastic"
+	"github.com/klokare/evo/network/recurrent"
+	"github.com/klokare/evo/novelty"
 	"github.com/klokare/evo/searcher/parallel"
 )
 
+// Known errors
+var (
+	ErrConflictingTranslators = errors.New("learning rules and time constants cannot both be mutated as plastic and continuous-time networks cannot be combined")
+)
+
 // Ensure the experiment struct implements the experiment interface
 var (
-	_ evo.Experiment = &Experiment{}
+	_ evo.Experiment   = &Experiment{}
+	_ evo.Stateful     = &Experiment{}
+	_ evo.Checkpointer = &Experiment{}
 )
 
 // Experiment implements an EVO experiment with the NEAT helpers.
@@ -20,10 +34,13 @@ type Experiment struct {
 	Selector
 	Speciator
 	Transcriber
-	forward.Translator
+	evo.Translator
 	evo.Searcher
 	evo.Mutators
+	Phased        *Phased         // Alternates complexifying and simplifying when phased search is configured
+	Novelty       *novelty.Scorer // Sets each genome's novelty when novelty search is configured
 	subscriptions []evo.Subscription
+	checkpointer  evo.Checkpointer
 }
 
 // NewExperiment creates a new NEAT experiment using the configuration. Configurations employ the
@@ -31,6 +48,9 @@ type Experiment struct {
 // desired.
 func NewExperiment(cfg config.Configurer) (exp *Experiment) {
 
+	// Declare the keys used by the experiment
+	cfg.Declare(Keys...)
+
 	// Create the experiment using the NEAT and other default helpers
 	exp = &Experiment{
 
@@ -104,18 +124,42 @@ func NewExperiment(cfg config.Configurer) (exp *Experiment) {
 
 	// Add the mutators. Only those with a chance of being activated will be added.
 	cm := mutator.Complexify{
-		AddNodeProbability: cfg.Float64("neat|mutator|complexify|add-node-probability"),
-		AddConnProbability: cfg.Float64("neat|mutator|complexify|add-conn-probability"),
-		WeightPower:        cfg.Float64("neat|mutator|complexify|weight-power"),
-		MaxWeight:          cfg.Float64("neat|mutator|complexify|max-weight"),
-		BiasPower:          cfg.Float64("neat|mutator|complexify|bias-power"),
-		MaxBias:            cfg.Float64("neat|mutator|complexify|max-bias"),
-		HiddenActivation:   cfg.Activation("neat|mutator|complexify|hidden-activation"),
-		DisableSortCheck:   cfg.Bool("neat|mutator|complexify|disable-sort-check"),
+		AddNodeProbability:   cfg.Float64("neat|mutator|complexify|add-node-probability"),
+		AddConnProbability:   cfg.Float64("neat|mutator|complexify|add-conn-probability"),
+		RecurrentProbability: cfg.Float64("neat|mutator|complexify|recurrent-probability"),
+		WeightPower:          cfg.Float64("neat|mutator|complexify|weight-power"),
+		MaxWeight:            cfg.Float64("neat|mutator|complexify|max-weight"),
+		BiasPower:            cfg.Float64("neat|mutator|complexify|bias-power"),
+		MaxBias:              cfg.Float64("neat|mutator|complexify|max-bias"),
+		HiddenActivation:     cfg.Activation("neat|mutator|complexify|hidden-activation"),
+		DisableSortCheck:     cfg.Bool("neat|mutator|complexify|disable-sort-check"),
+	}
+
+	sm := mutator.Simplify{
+		DeleteNodeProbability: cfg.Float64("neat|mutator|simplify|delete-node-probability"),
+		DeleteConnProbability: cfg.Float64("neat|mutator|simplify|delete-conn-probability"),
 	}
 
-	if cm.AddNodeProbability > 0.0 || cm.AddConnProbability > 0.0 {
-		exp.Mutators = append(exp.Mutators, cm)
+	// Phased search alternates between the complexify and simplify mutators. Otherwise, both may
+	// be used together.
+	ph := &Phased{
+		Complexify:          cm,
+		Simplify:            sm,
+		Selector:            &exp.Selector,
+		ComplexityThreshold: cfg.Float64("neat|phased|complexity-threshold"),
+		StagnationLimit:     cfg.Int("neat|phased|stagnation-limit"),
+		SimplifyLimit:       cfg.Int("neat|phased|simplify-limit"),
+	}
+	if ph.ComplexityThreshold > 0.0 || ph.StagnationLimit > 0 {
+		exp.Phased = ph
+		exp.Mutators = append(exp.Mutators, ph)
+	} else {
+		if cm.AddNodeProbability > 0.0 || cm.AddConnProbability > 0.0 {
+			exp.Mutators = append(exp.Mutators, cm)
+		}
+		if sm.DeleteNodeProbability > 0.0 || sm.DeleteConnProbability > 0.0 {
+			exp.Mutators = append(exp.Mutators, sm)
+		}
 	}
 
 	wm := mutator.Weight{
@@ -128,6 +172,17 @@ func NewExperiment(cfg config.Configurer) (exp *Experiment) {
 		exp.Mutators = append(exp.Mutators, wm)
 	}
 
+	hm := mutator.Hebbian{
+		MutateRuleProbability:  cfg.Float64("neat|mutator|hebbian|mutate-rule-probability"),
+		ReplaceRuleProbability: cfg.Float64("neat|mutator|hebbian|replace-rule-probability"),
+		RulePower:              cfg.Float64("neat|mutator|hebbian|rule-power"),
+		MaxRule:                cfg.Float64("neat|mutator|hebbian|max-rule"),
+		MaxRate:                cfg.Float64("neat|mutator|hebbian|max-rate"),
+	}
+	if hm.MutateRuleProbability > 0.0 {
+		exp.Mutators = append(exp.Mutators, hm)
+	}
+
 	bm := mutator.Bias{
 		MutateBiasProbability:  cfg.Float64("neat|mutator|bias|mutate-bias-probability"),
 		ReplaceBiasProbability: cfg.Float64("neat|mutator|bias|replace-bias-probability"),
@@ -138,6 +193,17 @@ func NewExperiment(cfg config.Configurer) (exp *Experiment) {
 		exp.Mutators = append(exp.Mutators, bm)
 	}
 
+	km := mutator.TimeConstant{
+		MutateTimeConstantProbability:  cfg.Float64("neat|mutator|time-constant|mutate-time-constant-probability"),
+		ReplaceTimeConstantProbability: cfg.Float64("neat|mutator|time-constant|replace-time-constant-probability"),
+		TimeConstantPower:              cfg.Float64("neat|mutator|time-constant|time-constant-power"),
+		MinTimeConstant:                cfg.Float64("neat|mutator|time-constant|min-time-constant"),
+		MaxTimeConstant:                cfg.Float64("neat|mutator|time-constant|max-time-constant"),
+	}
+	if km.MutateTimeConstantProbability > 0.0 {
+		exp.Mutators = append(exp.Mutators, km)
+	}
+
 	tm := mutator.Trait{
 		MutateTraitProbability: cfg.Float64("neat|mutator|trait|mutate-trait-probability"),
 	}
@@ -145,13 +211,78 @@ func NewExperiment(cfg config.Configurer) (exp *Experiment) {
 		exp.Mutators = append(exp.Mutators, tm)
 	}
 
+	// Switch from the forward translator if the networks need more. Learning rules are only used
+	// by plastic networks and time constants by continuous-time ones, both of which also handle
+	// recurrent connections. The two cannot be combined so a configuration with both leaves a
+	// translator that reports the conflict when the first genome is decoded.
+	switch {
+	case hm.MutateRuleProbability > 0.0 && km.MutateTimeConstantProbability > 0.0:
+		exp.Translator = invalidTranslator{err: ErrConflictingTranslators}
+	case hm.MutateRuleProbability > 0.0:
+		exp.Translator = plastic.Translator{
+			MaxWeight:        cfg.Float64("plastic|translator|max-weight"),
+			DisableSortCheck: cfg.Bool("plastic|translator|disable-sort-check"),
+		}
+	case km.MutateTimeConstantProbability > 0.0:
+		exp.Translator = ctrnn.Translator{
+			TimeStep:         cfg.Float64("ctrnn|translator|time-step"),
+			Steps:            cfg.Int("ctrnn|translator|steps"),
+			DisableSortCheck: cfg.Bool("ctrnn|translator|disable-sort-check"),
+		}
+	case cm.RecurrentProbability > 0.0:
+		exp.Translator = recurrent.Translator{DisableSortCheck: cfg.Bool("recurrent|translator|disable-sort-check")}
+	}
+
 	// Add subscriptions
-	// TODO: add subscription for phased mutator when that helper is complete.
+	if exp.Phased != nil {
+		exp.AddSubscription(evo.Subscription{Event: evo.Evaluated, Callback: exp.Phased.Update})
+	}
+
+	// Score novelty with the search results if novelty search is configured
+	if k := cfg.Int("novelty|scorer|k"); k > 0 {
+		exp.Novelty = &novelty.Scorer{
+			K:       k,
+			Archive: &novelty.Archive{MaxSize: cfg.Int("novelty|archive|max-size")},
+		}
+		switch cfg.String("novelty|archive|policy") {
+		case "random":
+			exp.Novelty.Policy = novelty.Random{Probability: cfg.Float64("novelty|archive|insert-probability")}
+		case "best":
+			exp.Novelty.Policy = novelty.Best{N: cfg.Int("novelty|archive|insert-best")}
+		default:
+			exp.Novelty.Policy = &novelty.Threshold{
+				Threshold:    cfg.Float64("novelty|archive|threshold"),
+				MinThreshold: cfg.Float64("novelty|archive|min-threshold"),
+				Modifier:     cfg.Float64("novelty|archive|threshold-modifier"),
+				RaiseAt:      cfg.Int("novelty|archive|raise-at"),
+				LowerAfter:   cfg.Int("novelty|archive|lower-after"),
+			}
+		}
+	}
 
 	// Return the new experiment
 	return
 }
 
+// Search the problem with the phenomes and, if novelty search is configured, score the results'
+// novelty. Scoring here, rather than in an Evaluated subscription, sets novelty before the
+// population is published so that every listener sees the same values.
+func (e *Experiment) Search(eval evo.Evaluator, phenomes []evo.Phenome) (results []evo.Result, err error) {
+	if results, err = e.Searcher.Search(eval, phenomes); err != nil || e.Novelty == nil {
+		return
+	}
+	err = e.Novelty.ScoreResults(results)
+	return
+}
+
+// A translator that fails because the experiment's configuration is invalid
+type invalidTranslator struct {
+	err error
+}
+
+// Translate returns the configuration error
+func (t invalidTranslator) Translate(evo.Substrate) (evo.Network, error) { return nil, t.err }
+
 // Subscriptions returns the subscriptions registered with this experiment.
 func (e *Experiment) Subscriptions() []evo.Subscription { return e.subscriptions }
 
@@ -162,3 +293,69 @@ func (e *Experiment) AddSubscription(s evo.Subscription) {
 	}
 	e.subscriptions = append(e.subscriptions, s)
 }
+
+// SetCheckpointer sets the helper which will save the experiment's checkpoints
+func (e *Experiment) SetCheckpointer(c evo.Checkpointer) { e.checkpointer = c }
+
+// Checkpoint passes the checkpoint to the experiment's checkpointer, if any
+func (e *Experiment) Checkpoint(cp evo.Checkpoint) error {
+	if e.checkpointer == nil {
+		return nil
+	}
+	return e.checkpointer.Checkpoint(cp)
+}
+
+// experimentState is the serialisable form of the stateful helpers' internal state
+type experimentState struct {
+	Selector  json.RawMessage
+	Speciator json.RawMessage
+	Phased    json.RawMessage `json:",omitempty"`
+	Novelty   json.RawMessage `json:",omitempty"`
+}
+
+// State returns the internal state of the experiment's selector, speciator, phased mutator and
+// novelty scorer
+func (e *Experiment) State() (b []byte, err error) {
+	var x experimentState
+	if x.Selector, err = e.Selector.State(); err != nil {
+		return
+	}
+	if x.Speciator, err = e.Speciator.State(); err != nil {
+		return
+	}
+	if e.Phased != nil {
+		if x.Phased, err = e.Phased.State(); err != nil {
+			return
+		}
+	}
+	if e.Novelty != nil {
+		if x.Novelty, err = e.Novelty.State(); err != nil {
+			return
+		}
+	}
+	return json.Marshal(x)
+}
+
+// Restore the internal state of the experiment's selector, speciator, phased mutator and novelty
+// scorer
+func (e *Experiment) Restore(b []byte) (err error) {
+	var x experimentState
+	if err = json.Unmarshal(b, &x); err != nil {
+		return
+	}
+	if err = e.Selector.Restore(x.Selector); err != nil {
+		return
+	}
+	if err = e.Speciator.Restore(x.Speciator); err != nil {
+		return
+	}
+	if e.Phased != nil && len(x.Phased) > 0 {
+		if err = e.Phased.Restore(x.Phased); err != nil {
+			return
+		}
+	}
+	if e.Novelty != nil && len(x.Novelty) > 0 {
+		err = e.Novelty.Restore(x.Novelty)
+	}
+	return
+}
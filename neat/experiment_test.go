@@ -0,0 +1,54 @@
+package neat
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/klokare/evo"
+	"github.com/klokare/evo/config"
+	"github.com/klokare/evo/config/source"
+	"github.com/klokare/evo/network/ctrnn"
+	"github.com/klokare/evo/network/forward"
+	"github.com/klokare/evo/network/plastic"
+	"github.com/klokare/evo/network/recurrent"
+)
+
+func TestNewExperimentTranslator(t *testing.T) {
+	var cases = []struct {
+		Desc       string
+		Recurrent  float64
+		Rule       float64
+		Time       float64
+		Translator evo.Translator
+		HasError   bool
+	}{
+		{Desc: "forward", Translator: forward.Translator{}},
+		{Desc: "recurrent", Recurrent: 0.1, Translator: recurrent.Translator{}},
+		{Desc: "plastic", Recurrent: 0.1, Rule: 0.1, Translator: plastic.Translator{}},
+		{Desc: "continuous-time", Recurrent: 0.1, Time: 0.1, Translator: ctrnn.Translator{}},
+		{Desc: "plastic and continuous-time", Rule: 0.1, Time: 0.1, HasError: true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Desc, func(t *testing.T) {
+			exp := NewExperiment(config.Configurer{Source: source.Map{
+				"neat": map[string]interface{}{
+					"mutator": map[string]interface{}{
+						"complexify":    map[string]interface{}{"recurrent-probability": c.Recurrent},
+						"hebbian":       map[string]interface{}{"mutate-rule-probability": c.Rule},
+						"time-constant": map[string]interface{}{"mutate-time-constant-probability": c.Time},
+					},
+				},
+			}})
+			if c.HasError {
+				if _, err := exp.Translate(evo.Substrate{}); err != ErrConflictingTranslators {
+					t.Errorf("incorrect error: expected %v, actual %v", ErrConflictingTranslators, err)
+				}
+				return
+			}
+			if reflect.TypeOf(exp.Translator) != reflect.TypeOf(c.Translator) {
+				t.Errorf("incorrect translator: expected %T, actual %T", c.Translator, exp.Translator)
+			}
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package neat
+
+import (
+	"math"
+
+	"github.com/klokare/evo/config"
+)
+
+// Keys used by the NEAT experiment. NewExperiment declares them with the configurer.
+var Keys = []config.Key{
+	{Name: "neat|crosser|enable-probability", Type: config.Float64, Min: 0.0, Max: 1.0, Description: "Probability that a disabled connection in the offspring is re-enabled"},
+	{Name: "neat|crosser|disable-equal-parent-check", Type: config.Bool, Description: "Treat equally fit parents as unequal so that excess and disjoint genes come only from the first"},
+	{Name: "neat|crosser|comparison", Type: config.Comparison, Description: "Comparison used to decide the fitter parent"},
+	{Name: "neat|crosser|disable-sort-check", Type: config.Bool, Description: "Skip sorting the parents' genes before crossing"},
+	{Name: "neat|seeder|num-inputs", Type: config.Int, Min: 1.0, Max: math.Inf(1), Required: true, Description: "Number of input nodes in the seed genome"},
+	{Name: "neat|seeder|num-outputs", Type: config.Int, Min: 1.0, Max: math.Inf(1), Required: true, Description: "Number of output nodes in the seed genome"},
+	{Name: "neat|seeder|num-traits", Type: config.Int, Min: 0.0, Max: math.Inf(1), Description: "Number of traits in the seed genome"},
+	{Name: "neat|seeder|output-activation", Type: config.Activation, Description: "Activation of the output nodes"},
+	{Name: "neat|seeder|disconnect-rate", Type: config.Float64, Min: 0.0, Max: 1.0, Description: "Proportion of the seed genome's connections that are removed"},
+	{Name: "neat|populator|population-size", Type: config.Int, Min: 1.0, Max: math.Inf(1), Required: true, Description: "Number of genomes in the initial population"},
+	{Name: "neat|populator|weight-power", Type: config.Float64, Min: 0.0, Max: math.Inf(1), Description: "Standard deviation of the initial connection weights"},
+	{Name: "neat|populator|max-weight", Type: config.Float64, Min: 0.0, Max: math.Inf(1), Description: "Maximum magnitude of the initial connection weights"},
+	{Name: "neat|populator|bias-power", Type: config.Float64, Min: 0.0, Max: math.Inf(1), Description: "Standard deviation of the initial node biases"},
+	{Name: "neat|populator|max-bias", Type: config.Float64, Min: 0.0, Max: math.Inf(1), Description: "Maximum magnitude of the initial node biases"},
+	{Name: "neat|selector|population-size", Type: config.Int, Min: 1.0, Max: math.Inf(1), Required: true, Description: "Number of genomes in each generation"},
+	{Name: "neat|selector|mutate-only-probability", Type: config.Float64, Min: 0.0, Max: 1.0, Description: "Probability that an offspring is created by mutation alone"},
+	{Name: "neat|selector|interspecies-mate-probability", Type: config.Float64, Min: 0.0, Max: 1.0, Description: "Probability that a parent is mated with one from another species"},
+	{Name: "neat|selector|elitism", Type: config.Float64, Min: 0.0, Max: 1.0, Description: "Proportion of each species that continues unchanged"},
+	{Name: "neat|selector|survival-rate", Type: config.Float64, Min: 0.0, Max: 1.0, Description: "Proportion of each species that may become parents"},
+	{Name: "neat|selector|comparison", Type: config.Comparison, Description: "Comparison used to rank genomes within species"},
+	{Name: "neat|updater|species-decay-rate", Type: config.Float64, Min: 0.0, Max: math.Inf(1), Description: "Rate at which the fitness of stagnant species decays with each generation"},
+	{Name: "neat|distancer|nodes-coefficient", Type: config.Float64, Min: 0.0, Max: math.Inf(1), Description: "Weight of the differing nodes in the compatibility distance"},
+	{Name: "neat|distancer|conns-coefficient", Type: config.Float64, Min: 0.0, Max: math.Inf(1), Description: "Weight of the differing connections in the compatibility distance"},
+	{Name: "neat|distancer|weight-coefficient", Type: config.Float64, Min: 0.0, Max: math.Inf(1), Description: "Weight of the connection weight differences in the compatibility distance"},
+	{Name: "neat|distancer|bias-coefficient", Type: config.Float64, Min: 0.0, Max: math.Inf(1), Description: "Weight of the node bias differences in the compatibility distance"},
+	{Name: "neat|distancer|activation-coefficient", Type: config.Float64, Min: 0.0, Max: math.Inf(1), Description: "Weight of the node activation differences in the compatibility distance"},
+	{Name: "neat|distancer|disable-sort-check", Type: config.Bool, Description: "Skip sorting the genomes' genes before measuring distance"},
+	{Name: "neat|speciator|compatibility-threshold", Type: config.Float64, Min: 0.0, Max: math.Inf(1), Description: "Distance within which genomes belong to the same species"},
+	{Name: "neat|speciator|compatibility-modifier", Type: config.Float64, Min: 0.0, Max: math.Inf(1), Description: "Amount by which the threshold is adjusted towards the target number of species"},
+	{Name: "neat|speciator|target-species", Type: config.Int, Min: 0.0, Max: math.Inf(1), Description: "Number of species sought. Ignored if zero."},
+	{Name: "neat|transcriber|disable-sort-check", Type: config.Bool, Description: "Skip sorting the genome's genes before transcribing"},
+	{Name: "forward|translator|disable-sort-check", Type: config.Bool, Description: "Skip sorting the substrate before translating"},
+	{Name: "neat|mutator|complexify|add-node-probability", Type: config.Float64, Min: 0.0, Max: 1.0, Description: "Probability of adding a node"},
+	{Name: "neat|mutator|complexify|add-conn-probability", Type: config.Float64, Min: 0.0, Max: 1.0, Description: "Probability of adding a connection"},
+	{Name: "neat|mutator|complexify|recurrent-probability", Type: config.Float64, Min: 0.0, Max: 1.0, Description: "Probability that an added connection may be recurrent"},
+	{Name: "neat|mutator|complexify|weight-power", Type: config.Float64, Min: 0.0, Max: math.Inf(1), Description: "Standard deviation of the weights of added connections"},
+	{Name: "neat|mutator|complexify|max-weight", Type: config.Float64, Min: 0.0, Max: math.Inf(1), Description: "Maximum magnitude of the weights of added connections"},
+	{Name: "neat|mutator|complexify|bias-power", Type: config.Float64, Min: 0.0, Max: math.Inf(1), Description: "Standard deviation of the biases of added nodes"},
+	{Name: "neat|mutator|complexify|max-bias", Type: config.Float64, Min: 0.0, Max: math.Inf(1), Description: "Maximum magnitude of the biases of added nodes"},
+	{Name: "neat|mutator|complexify|hidden-activation", Type: config.Activation, Description: "Activation of added nodes"},
+	{Name: "neat|mutator|complexify|disable-sort-check", Type: config.Bool, Description: "Skip sorting the genome's genes before complexifying"},
+	{Name: "neat|mutator|simplify|delete-node-probability", Type: config.Float64, Min: 0.0, Max: 1.0, Description: "Probability of deleting a hidden node"},
+	{Name: "neat|mutator|simplify|delete-conn-probability", Type: config.Float64, Min: 0.0, Max: 1.0, Description: "Probability of deleting a connection"},
+	{Name: "neat|phased|complexity-threshold", Type: config.Float64, Min: 0.0, Max: math.Inf(1), Description: "Rise in mean complexity at which simplifying begins. Ignored if zero."},
+	{Name: "neat|phased|stagnation-limit", Type: config.Int, Min: 0.0, Max: math.Inf(1), Description: "Generations without improvement after which simplifying begins. Ignored if zero."},
+	{Name: "neat|phased|simplify-limit", Type: config.Int, Default: 1, Min: 0.0, Max: math.Inf(1), Description: "Generations without a fall in mean complexity after which complexifying resumes"},
+	{Name: "recurrent|translator|disable-sort-check", Type: config.Bool, Description: "Skip sorting the substrate before translating"},
+	{Name: "neat|mutator|weight|mutate-weight-probability", Type: config.Float64, Min: 0.0, Max: 1.0, Description: "Probability that a connection's weight is mutated"},
+	{Name: "neat|mutator|weight|replace-weight-probability", Type: config.Float64, Min: 0.0, Max: 1.0, Description: "Probability that a mutated weight is replaced rather than perturbed"},
+	{Name: "neat|mutator|weight|weight-power", Type: config.Float64, Min: 0.0, Max: math.Inf(1), Description: "Standard deviation of weight mutations"},
+	{Name: "neat|mutator|weight|max-weight", Type: config.Float64, Min: 0.0, Max: math.Inf(1), Description: "Maximum magnitude of mutated weights"},
+	{Name: "neat|mutator|hebbian|mutate-rule-probability", Type: config.Float64, Min: 0.0, Max: 1.0, Description: "Probability that a connection's learning rule is mutated"},
+	{Name: "neat|mutator|hebbian|replace-rule-probability", Type: config.Float64, Min: 0.0, Max: 1.0, Description: "Probability that a mutated rule is replaced rather than perturbed"},
+	{Name: "neat|mutator|hebbian|rule-power", Type: config.Float64, Min: 0.0, Max: math.Inf(1), Description: "Standard deviation of rule mutations"},
+	{Name: "neat|mutator|hebbian|max-rule", Type: config.Float64, Min: 0.0, Max: math.Inf(1), Description: "Maximum magnitude of the rule coefficients"},
+	{Name: "neat|mutator|hebbian|max-rate", Type: config.Float64, Min: 0.0, Max: math.Inf(1), Description: "Maximum learning rate"},
+	{Name: "plastic|translator|max-weight", Type: config.Float64, Min: 0.0, Max: math.Inf(1), Description: "Maximum magnitude of adapted weights. Ignored if zero."},
+	{Name: "plastic|translator|disable-sort-check", Type: config.Bool, Description: "Skip sorting the substrate before translating"},
+	{Name: "neat|mutator|bias|mutate-bias-probability", Type: config.Float64, Min: 0.0, Max: 1.0, Description: "Probability that a node's bias is mutated"},
+	{Name: "neat|mutator|bias|replace-bias-probability", Type: config.Float64, Min: 0.0, Max: 1.0, Description: "Probability that a mutated bias is replaced rather than perturbed"},
+	{Name: "neat|mutator|bias|bias-power", Type: config.Float64, Min: 0.0, Max: math.Inf(1), Description: "Standard deviation of bias mutations"},
+	{Name: "neat|mutator|bias|max-bias", Type: config.Float64, Min: 0.0, Max: math.Inf(1), Description: "Maximum magnitude of mutated biases"},
+	{Name: "neat|mutator|time-constant|mutate-time-constant-probability", Type: config.Float64, Min: 0.0, Max: 1.0, Description: "Probability that a node's time constant is mutated"},
+	{Name: "neat|mutator|time-constant|replace-time-constant-probability", Type: config.Float64, Min: 0.0, Max: 1.0, Description: "Probability that a mutated time constant is replaced rather than perturbed"},
+	{Name: "neat|mutator|time-constant|time-constant-power", Type: config.Float64, Min: 0.0, Max: math.Inf(1), Description: "Standard deviation of time constant mutations"},
+	{Name: "neat|mutator|time-constant|min-time-constant", Type: config.Float64, Min: 0.0, Max: math.Inf(1), Description: "Minimum time constant"},
+	{Name: "neat|mutator|time-constant|max-time-constant", Type: config.Float64, Min: 0.0, Max: math.Inf(1), Description: "Maximum time constant"},
+	{Name: "ctrnn|translator|time-step", Type: config.Float64, Min: 0.0, Max: math.Inf(1), Description: "Size of each integration step of continuous-time networks"},
+	{Name: "ctrnn|translator|steps", Type: config.Int, Default: 1, Min: 0.0, Max: math.Inf(1), Description: "Number of integration steps in each activation"},
+	{Name: "ctrnn|translator|disable-sort-check", Type: config.Bool, Description: "Skip sorting the substrate before translating"},
+	{Name: "neat|mutator|trait|mutate-trait-probability", Type: config.Float64, Min: 0.0, Max: 1.0, Description: "Probability that a trait is mutated"},
+	{Name: "novelty|scorer|k", Type: config.Int, Min: 0.0, Max: math.Inf(1), Description: "Number of nearest neighbours used to score novelty. Novelty search is used if greater than zero."},
+	{Name: "novelty|archive|max-size", Type: config.Int, Min: 0.0, Max: math.Inf(1), Description: "Maximum number of behaviors in the archive. Ignored if zero."},
+	{Name: "novelty|archive|policy", Type: config.String, Description: "Policy for adding to the archive: threshold, random or best"},
+	{Name: "novelty|archive|insert-probability", Type: config.Float64, Min: 0.0, Max: 1.0, Description: "Probability of adding a behavior under the random policy"},
+	{Name: "novelty|archive|insert-best", Type: config.Int, Min: 0.0, Max: math.Inf(1), Description: "Number of most novel behaviors added each generation under the best policy"},
+	{Name: "novelty|archive|threshold", Type: config.Float64, Min: 0.0, Max: math.Inf(1), Description: "Initial novelty needed to be added under the threshold policy"},
+	{Name: "novelty|archive|min-threshold", Type: config.Float64, Min: 0.0, Max: math.Inf(1), Description: "Minimum novelty threshold"},
+	{Name: "novelty|archive|threshold-modifier", Type: config.Float64, Min: 0.0, Max: math.Inf(1), Description: "Proportion by which the threshold is raised or lowered"},
+	{Name: "novelty|archive|raise-at", Type: config.Int, Min: 0.0, Max: math.Inf(1), Description: "Additions in a generation at which the threshold is raised"},
+	{Name: "novelty|archive|lower-after", Type: config.Int, Min: 0.0, Max: math.Inf(1), Description: "Generations without additions after which the threshold is lowered"},
+}
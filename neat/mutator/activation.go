@@ -19,17 +19,26 @@ type Activation struct {
 
 // Mutate the the activation values of the genomes based on the settings in the helper.
 func (a Activation) Mutate(g *evo.Genome) (err error) {
+	return a.MutateWith(evo.NewRandom(), g)
+}
+
+// MutateWith mutates the activation values, drawing from the random stream
+func (a Activation) MutateWith(rng evo.Random, g *evo.Genome) (err error) {
 	if len(a.Activations) == 0 {
 		return ErrMissingActivations
 	}
 
-	rng := evo.NewRandom()
+	mutated := false
 	for i, n := range g.Encoded.Nodes {
 		if n.Neuron == evo.Hidden {
 			if rng.Float64() < a.ReplaceActivationProbability {
 				g.Encoded.Nodes[i].Activation = a.Activations[rng.Intn(len(a.Activations))]
+				mutated = true
 			}
 		}
 	}
+	if mutated {
+		g.Mutations = append(g.Mutations, "activation")
+	}
 	return
 }
@@ -16,7 +16,12 @@ type Bias struct {
 // version of NEAT, bias is a separate node type with connections to other nodes. In evo, bias is a
 // property of the node. Effectively, though, they are the same.
 func (b Bias) Mutate(g *evo.Genome) (err error) {
-	rng := evo.NewRandom()
+	return b.MutateWith(evo.NewRandom(), g)
+}
+
+// MutateWith mutates the bias values, drawing from the random stream
+func (b Bias) MutateWith(rng evo.Random, g *evo.Genome) (err error) {
+	mutated := false
 	for i, n := range g.Encoded.Nodes {
 		if n.Neuron == evo.Input {
 			continue
@@ -33,7 +38,11 @@ func (b Bias) Mutate(g *evo.Genome) (err error) {
 				n.Bias = b.MaxBias
 			}
 			g.Encoded.Nodes[i] = n
+			mutated = true
 		}
 	}
+	if mutated {
+		g.Mutations = append(g.Mutations, "bias")
+	}
 	return
 }
@@ -8,24 +8,37 @@ import (
 
 // Complexify mutates a genome by adding to its structure
 type Complexify struct {
-	AddNodeProbability float64
-	AddConnProbability float64
-	WeightPower        float64
-	MaxWeight          float64
-	BiasPower          float64
-	MaxBias            float64
-	HiddenActivation   evo.Activation
-	DisableSortCheck   bool
+	AddNodeProbability   float64
+	AddConnProbability   float64
+	RecurrentProbability float64 // Probability that an added connection will be recurrent. Requires a recurrent translator.
+	WeightPower          float64
+	MaxWeight            float64
+	BiasPower            float64
+	MaxBias              float64
+	HiddenActivation     evo.Activation
+	DisableSortCheck     bool
 }
 
 // Mutate a genome by adding nodes or connections
 func (m Complexify) Mutate(g *evo.Genome) (err error) {
-	rng := evo.NewRandom()
+	return m.MutateWith(evo.NewRandom(), g)
+}
+
+// MutateWith mutates the genome by adding nodes or connections, drawing from the random stream
+func (m Complexify) MutateWith(rng evo.Random, g *evo.Genome) (err error) {
+	n, c := len(g.Encoded.Nodes), len(g.Encoded.Conns)
 	if rng.Float64() < m.AddNodeProbability {
-		return m.addNode(rng, &g.Encoded, !m.DisableSortCheck)
+		if err = m.addNode(rng, &g.Encoded, !m.DisableSortCheck); err == nil && len(g.Encoded.Nodes) != n {
+			g.Mutations = append(g.Mutations, "add-node")
+		}
+		return
 	}
 	if rng.Float64() < m.AddConnProbability {
-		return m.addConn(rng, &g.Encoded, !m.DisableSortCheck)
+		recurrent := m.RecurrentProbability > 0.0 && rng.Float64() < m.RecurrentProbability
+		if err = m.addConn(rng, &g.Encoded, recurrent, !m.DisableSortCheck); err == nil && len(g.Encoded.Conns) != c {
+			g.Mutations = append(g.Mutations, "add-conn")
+		}
+		return
 	}
 	return
 }
@@ -35,7 +48,8 @@ func (m Complexify) Mutate(g *evo.Genome) (err error) {
 // In the add node mutation, an existing connection is split and the new node placed where the old
 // connection used to be. The old connection is disabled and two new connections are added to the
 // genome. The new connection leading into the new node receives a weight of 1, and the new
-// connection leading out receives the same weight as the old connection. (Stanley, 107)
+// connection leading out receives the same weight as the old connection. (Stanley, 107) The
+// connection leading out also keeps the old connection's learning rule.
 //
 // NOTE: Stanley's version does not use a bias property in nodes. Setting that property to zero is
 // the equivalent.
@@ -79,7 +93,7 @@ func (m Complexify) addNode(rng evo.Random, sub *evo.Substrate, check bool) (err
 
 		// Identify the connections to this node based on the original connection
 		c1 := evo.Conn{Source: c0.Source, Target: n.Position, Weight: 1.0, Enabled: true}
-		c2 := evo.Conn{Source: n.Position, Target: c0.Target, Weight: c0.Weight, Enabled: true}
+		c2 := evo.Conn{Source: n.Position, Target: c0.Target, Weight: c0.Weight, Enabled: true, Hebbian: c0.Hebbian}
 		sub.Conns = append(sub.Conns, c1, c2)
 
 		// Disable the original connection
@@ -97,7 +111,10 @@ func (m Complexify) addNode(rng evo.Random, sub *evo.Substrate, check bool) (err
 //
 // In the add connection mutation, a single new connection gene with a random weight is added
 // connecting two previously unconnected nodes (Stanley, 107).
-func (m Complexify) addConn(rng evo.Random, sub *evo.Substrate, check bool) (err error) {
+//
+// A recurrent connection points to a node in the same or an earlier layer, including the source
+// node itself.
+func (m Complexify) addConn(rng evo.Random, sub *evo.Substrate, recurrent, check bool) (err error) {
 
 	// Improve search speed
 	if check {
@@ -115,13 +132,16 @@ func (m Complexify) addConn(rng evo.Random, sub *evo.Substrate, check bool) (err
 			tgt := sub.Nodes[tidx]
 
 			// Simple tests for recurrence
-			// NOTE: recurrence not implemented in this version
-			if src.Position == tgt.Position {
+			if tgt.Neuron == evo.Input {
+				continue
+			} else if recurrent {
+				if tgt.Layer > src.Layer {
+					continue // Recurrent connections point backward or to the same layer
+				}
+			} else if src.Position == tgt.Position {
 				continue // No self-connection
 			} else if src.Neuron == evo.Output {
 				continue
-			} else if tgt.Neuron == evo.Input {
-				continue
 			} else if tgt.Layer <= src.Layer {
 				continue
 			}
@@ -253,3 +253,48 @@ func testComplexifyConns(actual, expected []evo.Conn, checkWeight bool) func(*te
 func TestComplexifyRandom(t *testing.T) {
 
 }
+
+func TestComplexifyRecurrent(t *testing.T) {
+
+	// Create a fully-connected feed-forward genome so only recurrent connections can be added
+	g := evo.Genome{
+		Encoded: evo.Substrate{
+			Nodes: []evo.Node{
+				{Position: evo.Position{Layer: 0.0, X: 0.0}, Neuron: evo.Input},
+				{Position: evo.Position{Layer: 0.5, X: 0.5}, Neuron: evo.Hidden},
+				{Position: evo.Position{Layer: 1.0, X: 0.5}, Neuron: evo.Output},
+			},
+			Conns: []evo.Conn{
+				{Source: evo.Position{Layer: 0.0, X: 0.0}, Target: evo.Position{Layer: 0.5, X: 0.5}, Enabled: true},
+				{Source: evo.Position{Layer: 0.0, X: 0.0}, Target: evo.Position{Layer: 1.0, X: 0.5}, Enabled: true},
+				{Source: evo.Position{Layer: 0.5, X: 0.5}, Target: evo.Position{Layer: 1.0, X: 0.5}, Enabled: true},
+			},
+		},
+	}
+
+	// Without recurrence no connection can be added
+	mut := Complexify{AddConnProbability: 1.0, WeightPower: 1.0, MaxWeight: 1.0}
+	if err := mut.Mutate(&g); err != nil {
+		t.Fatalf("error not expected: %v", err)
+	}
+	if len(g.Encoded.Conns) != 3 {
+		t.Fatalf("incorrect number of connections: expected 3, actual %d", len(g.Encoded.Conns))
+	}
+
+	// Add recurrent connections until all possible have been added. There are 3 possible: the hidden
+	// and output self-connections and output to hidden. Connections to the input are not allowed.
+	mut.RecurrentProbability = 1.0
+	for i := 0; i < 10; i++ {
+		if err := mut.Mutate(&g); err != nil {
+			t.Fatalf("error not expected: %v", err)
+		}
+	}
+	if len(g.Encoded.Conns) != 6 {
+		t.Errorf("incorrect number of connections: expected 6, actual %d", len(g.Encoded.Conns))
+	}
+	for _, c := range g.Encoded.Conns {
+		if c.Target.Layer == 0.0 {
+			t.Errorf("input should not be the target of a connection: %v", c)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package mutator
+
+import (
+	"github.com/klokare/evo"
+)
+
+// Hebbian mutates the learning rules of the genome's connections so that plastic networks can
+// evolve how they learn
+type Hebbian struct {
+	MutateRuleProbability  float64 // The probability that the connection's rule will be mutated
+	ReplaceRuleProbability float64 // The probability that, if being mutated, the rule will be replaced
+	RulePower              float64
+	MaxRule                float64 // Coefficients are kept within [-MaxRule, MaxRule]
+	MaxRate                float64 // Learning rates are kept within [0, MaxRate]
+}
+
+// Mutate a genome by perturbing or replacing its connections' learning rules
+func (z Hebbian) Mutate(g *evo.Genome) (err error) {
+	return z.MutateWith(evo.NewRandom(), g)
+}
+
+// MutateWith mutates the connections' learning rules, drawing from the random stream
+func (z Hebbian) MutateWith(rng evo.Random, g *evo.Genome) (err error) {
+	mutated := false
+	for i, c := range g.Encoded.Conns {
+		if rng.Float64() < z.MutateRuleProbability {
+			replace := rng.Float64() < z.ReplaceRuleProbability
+			for _, x := range []*float64{&c.A, &c.B, &c.C, &c.D} {
+				*x = z.mutate(rng, *x, replace, -z.MaxRule, z.MaxRule)
+			}
+			c.Rate = z.mutate(rng, c.Rate, replace, 0.0, z.MaxRate)
+			g.Encoded.Conns[i] = c
+			mutated = true
+		}
+	}
+	if mutated {
+		g.Mutations = append(g.Mutations, "hebbian")
+	}
+	return
+}
+
+// Perturb or replace the value and keep it within the range
+func (z Hebbian) mutate(rng evo.Random, x float64, replace bool, min, max float64) float64 {
+	if replace {
+		x = rng.NormFloat64() * z.RulePower
+	} else {
+		x += rng.NormFloat64() * z.RulePower
+	}
+	if x > max {
+		x = max
+	} else if x < min {
+		x = min
+	}
+	return x
+}
@@ -0,0 +1,69 @@
+package mutator
+
+import (
+	"math"
+	"testing"
+
+	"github.com/klokare/evo"
+	"github.com/klokare/evo/internal/float"
+)
+
+func TestHebbian(t *testing.T) {
+
+	var tests = []struct {
+		Desc                   string
+		MutateRuleProbability  float64
+		ReplaceRuleProbability float64
+		Mean                   float64
+		Stdev                  float64
+	}{
+		{Desc: "no probabilities so no change", Mean: 1.0, Stdev: 0.0},
+		{Desc: "always mutate but never replace", MutateRuleProbability: 1.0, Mean: 1.0, Stdev: 0.5},
+		{Desc: "always mutate and always replace", MutateRuleProbability: 1.0, ReplaceRuleProbability: 1.0, Mean: 0.0, Stdev: 0.5},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Desc, func(t *testing.T) {
+
+			// Create the mutator. The limits are wide enough not to affect the coefficients.
+			mut := Hebbian{
+				MutateRuleProbability:  test.MutateRuleProbability,
+				ReplaceRuleProbability: test.ReplaceRuleProbability,
+				RulePower:              0.5,
+				MaxRule:                8.0,
+				MaxRate:                0.5,
+			}
+
+			// Run N times to get a sample
+			coefficients := make([]float64, 10000)
+			for i := 0; i < len(coefficients); i++ {
+				g := evo.Genome{
+					Encoded: evo.Substrate{
+						Conns: []evo.Conn{{Weight: 1.0, Hebbian: evo.Hebbian{A: 1.0, B: 1.0, C: 1.0, D: 1.0, Rate: 0.1}}},
+					},
+				}
+				if err := mut.Mutate(&g); err != nil {
+					t.Fatalf("error not expected: %v", err)
+				}
+				c := g.Encoded.Conns[0]
+				coefficients[i] = c.A
+				if c.Weight != 1.0 {
+					t.Fatalf("weight should not change: actual %f", c.Weight)
+				}
+				if c.Rate < 0.0 || c.Rate > mut.MaxRate {
+					t.Fatalf("rate outside of range: actual %f", c.Rate)
+				}
+			}
+
+			// Compare against expected
+			m := float.Mean(coefficients)
+			s := float.Stdev(coefficients)
+			if math.Abs(m-test.Mean) > 0.05 {
+				t.Errorf("incorrect mean coefficient. expected %f, actual %f", test.Mean, m)
+			}
+			if math.Abs(s-test.Stdev) > 0.05 {
+				t.Errorf("incorrect standard deviation. expected %f, actual %f", test.Stdev, s)
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package mutator
+
+import (
+	"github.com/klokare/evo"
+)
+
+// Simplify mutates a genome by removing from its structure. Locked nodes and connections are never
+// removed, nor is a node with a locked connection.
+type Simplify struct {
+	DeleteNodeProbability float64
+	DeleteConnProbability float64
+}
+
+// Mutate a genome by deleting nodes or connections
+func (m Simplify) Mutate(g *evo.Genome) (err error) {
+	return m.MutateWith(evo.NewRandom(), g)
+}
+
+// MutateWith mutates the genome by deleting nodes or connections, drawing from the random stream
+func (m Simplify) MutateWith(rng evo.Random, g *evo.Genome) (err error) {
+	n, c := len(g.Encoded.Nodes), len(g.Encoded.Conns)
+	if rng.Float64() < m.DeleteNodeProbability {
+		if m.deleteNode(rng, &g.Encoded); len(g.Encoded.Nodes) != n {
+			g.Mutations = append(g.Mutations, "delete-node")
+		}
+		return
+	}
+	if rng.Float64() < m.DeleteConnProbability {
+		if m.deleteConn(rng, &g.Encoded); len(g.Encoded.Conns) != c {
+			g.Mutations = append(g.Mutations, "delete-conn")
+		}
+	}
+	return
+}
+
+// delete a hidden node and its connections. Only input, output and locked nodes are kept.
+func (m Simplify) deleteNode(rng evo.Random, sub *evo.Substrate) {
+
+	// Iterate nodes randomly
+	for _, idx := range rng.Perm(len(sub.Nodes)) {
+
+		// Identify the node
+		n := sub.Nodes[idx]
+		if n.Neuron != evo.Hidden || n.Locked {
+			continue
+		}
+
+		// Do not remove a node with a locked connection
+		locked := false
+		for _, c := range sub.Conns {
+			if c.Locked && (c.Source == n.Position || c.Target == n.Position) {
+				locked = true
+				break
+			}
+		}
+		if locked {
+			continue
+		}
+
+		// Remove the node and its connections
+		sub.Nodes = append(sub.Nodes[:idx], sub.Nodes[idx+1:]...)
+		conns := sub.Conns[:0]
+		for _, c := range sub.Conns {
+			if c.Source != n.Position && c.Target != n.Position {
+				conns = append(conns, c)
+			}
+		}
+		sub.Conns = conns
+		removeOrphans(sub)
+		return
+	}
+}
+
+// delete a connection and any hidden nodes left without connections
+func (m Simplify) deleteConn(rng evo.Random, sub *evo.Substrate) {
+
+	// Iterate connections randomly
+	for _, idx := range rng.Perm(len(sub.Conns)) {
+		if sub.Conns[idx].Locked {
+			continue // do not remove a locked connection
+		}
+		sub.Conns = append(sub.Conns[:idx], sub.Conns[idx+1:]...)
+		removeOrphans(sub)
+		return
+	}
+}
+
+// Remove the hidden nodes that no longer have any connections. The order of the nodes is kept.
+func removeOrphans(sub *evo.Substrate) {
+	used := make(map[evo.Position]bool, len(sub.Conns)*2)
+	for _, c := range sub.Conns {
+		used[c.Source] = true
+		used[c.Target] = true
+	}
+	nodes := sub.Nodes[:0]
+	for _, n := range sub.Nodes {
+		if n.Neuron != evo.Hidden || n.Locked || used[n.Position] {
+			nodes = append(nodes, n)
+		}
+	}
+	sub.Nodes = nodes
+}
@@ -0,0 +1,93 @@
+package mutator
+
+import (
+	"testing"
+
+	"github.com/klokare/evo"
+)
+
+func TestSimplify(t *testing.T) {
+
+	// Positions of the nodes
+	in := evo.Position{Layer: 0.0, X: 0.0}
+	hid := evo.Position{Layer: 0.5, X: 0.5}
+	out := evo.Position{Layer: 1.0, X: 1.0}
+
+	var cases = []struct {
+		Desc                  string
+		DeleteNodeProbability float64
+		DeleteConnProbability float64
+		Nodes                 []evo.Node
+		Conns                 []evo.Conn
+		ExpectedNodes         []evo.Node
+		ExpectedConns         []evo.Conn
+	}{
+		{
+			Desc:          "no probabilities, no change",
+			Nodes:         []evo.Node{{Position: in, Neuron: evo.Input}, {Position: hid, Neuron: evo.Hidden}, {Position: out, Neuron: evo.Output}},
+			Conns:         []evo.Conn{{Source: in, Target: hid, Enabled: true}, {Source: hid, Target: out, Enabled: true}},
+			ExpectedNodes: []evo.Node{{Position: in, Neuron: evo.Input}, {Position: hid, Neuron: evo.Hidden}, {Position: out, Neuron: evo.Output}},
+			ExpectedConns: []evo.Conn{{Source: in, Target: hid, Enabled: true}, {Source: hid, Target: out, Enabled: true}},
+		},
+		{
+			Desc:                  "delete node and its connections",
+			DeleteNodeProbability: 1.0,
+			Nodes:                 []evo.Node{{Position: in, Neuron: evo.Input}, {Position: hid, Neuron: evo.Hidden}, {Position: out, Neuron: evo.Output}},
+			Conns:                 []evo.Conn{{Source: in, Target: hid, Enabled: true}, {Source: in, Target: out}, {Source: hid, Target: out, Enabled: true}},
+			ExpectedNodes:         []evo.Node{{Position: in, Neuron: evo.Input}, {Position: out, Neuron: evo.Output}},
+			ExpectedConns:         []evo.Conn{{Source: in, Target: out}},
+		},
+		{
+			Desc:                  "locked node is not deleted",
+			DeleteNodeProbability: 1.0,
+			Nodes:                 []evo.Node{{Position: in, Neuron: evo.Input}, {Position: hid, Neuron: evo.Hidden, Locked: true}, {Position: out, Neuron: evo.Output}},
+			Conns:                 []evo.Conn{{Source: in, Target: hid, Enabled: true}, {Source: hid, Target: out, Enabled: true}},
+			ExpectedNodes:         []evo.Node{{Position: in, Neuron: evo.Input}, {Position: hid, Neuron: evo.Hidden}, {Position: out, Neuron: evo.Output}},
+			ExpectedConns:         []evo.Conn{{Source: in, Target: hid, Enabled: true}, {Source: hid, Target: out, Enabled: true}},
+		},
+		{
+			Desc:                  "node with locked connection is not deleted",
+			DeleteNodeProbability: 1.0,
+			Nodes:                 []evo.Node{{Position: in, Neuron: evo.Input}, {Position: hid, Neuron: evo.Hidden}, {Position: out, Neuron: evo.Output}},
+			Conns:                 []evo.Conn{{Source: in, Target: hid, Enabled: true, Locked: true}, {Source: hid, Target: out, Enabled: true}},
+			ExpectedNodes:         []evo.Node{{Position: in, Neuron: evo.Input}, {Position: hid, Neuron: evo.Hidden}, {Position: out, Neuron: evo.Output}},
+			ExpectedConns:         []evo.Conn{{Source: in, Target: hid, Enabled: true}, {Source: hid, Target: out, Enabled: true}},
+		},
+		{
+			Desc:                  "delete unlocked connection",
+			DeleteConnProbability: 1.0,
+			Nodes:                 []evo.Node{{Position: in, Neuron: evo.Input}, {Position: hid, Neuron: evo.Hidden}, {Position: out, Neuron: evo.Output}},
+			Conns:                 []evo.Conn{{Source: in, Target: hid, Enabled: true, Locked: true}, {Source: hid, Target: out, Enabled: true}},
+			ExpectedNodes:         []evo.Node{{Position: in, Neuron: evo.Input}, {Position: hid, Neuron: evo.Hidden}, {Position: out, Neuron: evo.Output}},
+			ExpectedConns:         []evo.Conn{{Source: in, Target: hid, Enabled: true}},
+		},
+		{
+			Desc:                  "delete connection and orphaned node",
+			DeleteConnProbability: 1.0,
+			Nodes:                 []evo.Node{{Position: in, Neuron: evo.Input}, {Position: hid, Neuron: evo.Hidden}, {Position: out, Neuron: evo.Output}},
+			Conns:                 []evo.Conn{{Source: in, Target: hid, Enabled: true}, {Source: in, Target: out, Enabled: true, Locked: true}},
+			ExpectedNodes:         []evo.Node{{Position: in, Neuron: evo.Input}, {Position: out, Neuron: evo.Output}},
+			ExpectedConns:         []evo.Conn{{Source: in, Target: out, Enabled: true}},
+		},
+		{
+			Desc:                  "all connections locked",
+			DeleteConnProbability: 1.0,
+			Nodes:                 []evo.Node{{Position: in, Neuron: evo.Input}, {Position: out, Neuron: evo.Output}},
+			Conns:                 []evo.Conn{{Source: in, Target: out, Enabled: true, Locked: true}},
+			ExpectedNodes:         []evo.Node{{Position: in, Neuron: evo.Input}, {Position: out, Neuron: evo.Output}},
+			ExpectedConns:         []evo.Conn{{Source: in, Target: out, Enabled: true}},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Desc, func(t *testing.T) {
+			g := evo.Genome{Encoded: evo.Substrate{Nodes: c.Nodes, Conns: c.Conns}}
+			mut := Simplify{DeleteNodeProbability: c.DeleteNodeProbability, DeleteConnProbability: c.DeleteConnProbability}
+			if err := mut.Mutate(&g); err != nil {
+				t.Fatalf("error not expected: %v", err)
+			}
+			t.Run("Nodes", testComplexifyNodes(g.Encoded.Nodes, c.ExpectedNodes))
+			t.Run("Conns", testComplexifyConns(g.Encoded.Conns, c.ExpectedConns, false))
+		})
+	}
+}
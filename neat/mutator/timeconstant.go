@@ -0,0 +1,49 @@
+package mutator
+
+import (
+	"github.com/klokare/evo"
+)
+
+// TimeConstant mutates the time constants of the genome's nodes so that continuous-time networks
+// can evolve how quickly each neuron responds
+type TimeConstant struct {
+	MutateTimeConstantProbability  float64 // The probability that the node's time constant will be mutated
+	ReplaceTimeConstantProbability float64 // The probability that, if being mutated, the time constant will be replaced
+	TimeConstantPower              float64
+	MinTimeConstant                float64 // Time constants are kept within [MinTimeConstant, MaxTimeConstant]
+	MaxTimeConstant                float64
+}
+
+// Mutate a genome by perturbing or replacing its nodes' time constants
+func (z TimeConstant) Mutate(g *evo.Genome) (err error) {
+	return z.MutateWith(evo.NewRandom(), g)
+}
+
+// MutateWith mutates the nodes' time constants, drawing from the random stream. A replaced time
+// constant is drawn uniformly from the allowed range.
+func (z TimeConstant) MutateWith(rng evo.Random, g *evo.Genome) (err error) {
+	mutated := false
+	for i, n := range g.Encoded.Nodes {
+		if n.Neuron == evo.Input {
+			continue
+		}
+		if rng.Float64() < z.MutateTimeConstantProbability {
+			if rng.Float64() < z.ReplaceTimeConstantProbability {
+				n.TimeConstant = z.MinTimeConstant + rng.Float64()*(z.MaxTimeConstant-z.MinTimeConstant)
+			} else {
+				n.TimeConstant += rng.NormFloat64() * z.TimeConstantPower
+			}
+			if n.TimeConstant < z.MinTimeConstant {
+				n.TimeConstant = z.MinTimeConstant
+			} else if n.TimeConstant > z.MaxTimeConstant {
+				n.TimeConstant = z.MaxTimeConstant
+			}
+			g.Encoded.Nodes[i] = n
+			mutated = true
+		}
+	}
+	if mutated {
+		g.Mutations = append(g.Mutations, "time-constant")
+	}
+	return
+}
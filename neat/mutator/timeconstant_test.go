@@ -0,0 +1,71 @@
+package mutator
+
+import (
+	"math"
+	"testing"
+
+	"github.com/klokare/evo"
+	"github.com/klokare/evo/internal/float"
+)
+
+func TestTimeConstant(t *testing.T) {
+
+	var tests = []struct {
+		Desc                           string
+		MutateTimeConstantProbability  float64
+		ReplaceTimeConstantProbability float64
+		Mean                           float64
+		Stdev                          float64
+	}{
+		{Desc: "no probabilities so no change", Mean: 1.0, Stdev: 0.0},
+		{Desc: "always mutate but never replace", MutateTimeConstantProbability: 1.0, Mean: 1.0, Stdev: 0.1},
+		{Desc: "always mutate and always replace", MutateTimeConstantProbability: 1.0, ReplaceTimeConstantProbability: 1.0, Mean: 1.0, Stdev: 0.9 / math.Sqrt(3.0)},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Desc, func(t *testing.T) {
+
+			// Create the mutator. Replaced time constants are uniform within [0.1, 1.9].
+			mut := TimeConstant{
+				MutateTimeConstantProbability:  test.MutateTimeConstantProbability,
+				ReplaceTimeConstantProbability: test.ReplaceTimeConstantProbability,
+				TimeConstantPower:              0.1,
+				MinTimeConstant:                0.1,
+				MaxTimeConstant:                1.9,
+			}
+
+			// Run N times to get a sample
+			taus := make([]float64, 10000)
+			for i := 0; i < len(taus); i++ {
+				g := evo.Genome{
+					Encoded: evo.Substrate{
+						Nodes: []evo.Node{
+							{Neuron: evo.Input, TimeConstant: 1.0},
+							{Neuron: evo.Output, TimeConstant: 1.0},
+						},
+					},
+				}
+				if err := mut.Mutate(&g); err != nil {
+					t.Fatalf("error not expected: %v", err)
+				}
+				if g.Encoded.Nodes[0].TimeConstant != 1.0 {
+					t.Fatalf("input node's time constant should not change: actual %f", g.Encoded.Nodes[0].TimeConstant)
+				}
+				taus[i] = g.Encoded.Nodes[1].TimeConstant
+				if taus[i] < mut.MinTimeConstant || taus[i] > mut.MaxTimeConstant {
+					t.Fatalf("time constant outside of range: actual %f", taus[i])
+				}
+			}
+
+			// Compare against expected
+			m := float.Mean(taus)
+			s := float.Stdev(taus)
+			if math.Abs(m-test.Mean) > 0.05 {
+				t.Errorf("incorrect mean time constant. expected %f, actual %f", test.Mean, m)
+			}
+			if math.Abs(s-test.Stdev) > 0.05 {
+				t.Errorf("incorrect standard deviation. expected %f, actual %f", test.Stdev, s)
+			}
+		})
+	}
+}
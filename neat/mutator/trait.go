@@ -12,7 +12,12 @@ type Trait struct {
 
 // Mutate the the trait values of the genomes based on the settings in the helper.
 func (b Trait) Mutate(g *evo.Genome) (err error) {
-	rng := evo.NewRandom()
+	return b.MutateWith(evo.NewRandom(), g)
+}
+
+// MutateWith mutates the trait values, drawing from the random stream
+func (b Trait) MutateWith(rng evo.Random, g *evo.Genome) (err error) {
+	mutated := false
 	for i, x := range g.Traits {
 		if rng.Float64() < b.MutateTraitProbability {
 			if rng.Float64() < b.ReplaceTraitProbability {
@@ -26,7 +31,11 @@ func (b Trait) Mutate(g *evo.Genome) (err error) {
 				}
 			}
 			g.Traits[i] = x
+			mutated = true
 		}
 	}
+	if mutated {
+		g.Mutations = append(g.Mutations, "trait")
+	}
 	return
 }
@@ -14,7 +14,12 @@ type Weight struct {
 
 // Mutate a genome by perturbing or replacing its connections' weights
 func (z Weight) Mutate(g *evo.Genome) (err error) {
-	rng := evo.NewRandom()
+	return z.MutateWith(evo.NewRandom(), g)
+}
+
+// MutateWith mutates the connection weights, drawing from the random stream
+func (z Weight) MutateWith(rng evo.Random, g *evo.Genome) (err error) {
+	mutated := false
 	for i, c := range g.Encoded.Conns {
 		if rng.Float64() < z.MutateWeightProbability {
 			if rng.Float64() < z.ReplaceWeightProbability {
@@ -28,7 +33,11 @@ func (z Weight) Mutate(g *evo.Genome) (err error) {
 				c.Weight = -z.MaxWeight
 			}
 			g.Encoded.Conns[i] = c
+			mutated = true
 		}
 	}
+	if mutated {
+		g.Mutations = append(g.Mutations, "weight")
+	}
 	return
 }
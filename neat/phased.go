@@ -0,0 +1,182 @@
+package neat
+
+import (
+	"encoding/json"
+	"sync"
+
+	"github.com/klokare/evo"
+	"github.com/klokare/evo/neat/mutator"
+)
+
+// MutateOnlyToggler is a selector that can be put into a mutate only mode, such as the NEAT
+// selector
+type MutateOnlyToggler interface {
+	ToggleMutateOnly(on bool) error
+}
+
+// Phased alternates the structural mutation of genomes between complexifying and simplifying
+// phases, after Green's phased searching. The search complexifies until the mean complexity of the
+// population rises a threshold above the floor, or the best fitness has stagnated, and then
+// simplifies until the mean complexity stops falling. The mean complexity at that point becomes the
+// new floor. While simplifying, the selector, if any, is put into mutate only mode so that crossover
+// does not reintroduce the structure being removed.
+//
+// Update must be subscribed to the Evaluated event for the phase to change.
+type Phased struct {
+	Complexify          mutator.Complexify
+	Simplify            mutator.Simplify
+	Selector            MutateOnlyToggler // Toggled into mutate only mode while simplifying, if set
+	ComplexityThreshold float64           // Rise in mean complexity above the floor at which simplifying begins. Ignored if zero.
+	StagnationLimit     int               // Generations without improvement in the best fitness after which simplifying begins. Ignored if zero.
+	SimplifyLimit       int               // Generations without a fall in mean complexity after which complexifying resumes. If zero, 1 is used.
+
+	// Internal state
+	mu          sync.RWMutex
+	simplifying bool
+	started     bool
+	floor       float64 // Mean complexity at the end of the last simplifying phase
+	lowest      float64 // Lowest mean complexity during the current simplifying phase
+	best        float64 // Best fitness seen during the current complexifying phase
+	stagnant    int     // Generations without improvement in the best fitness
+	unchanged   int     // Generations without a fall in the mean complexity
+}
+
+// Mutate the genome by complexifying or simplifying according to the current phase
+func (p *Phased) Mutate(g *evo.Genome) error {
+	return p.MutateWith(evo.NewRandom(), g)
+}
+
+// MutateWith mutates the genome according to the current phase, drawing from the random stream
+func (p *Phased) MutateWith(rng evo.Random, g *evo.Genome) error {
+	if p.Simplifying() {
+		return p.Simplify.MutateWith(rng, g)
+	}
+	return p.Complexify.MutateWith(rng, g)
+}
+
+// Simplifying returns true if the search is in a simplifying phase
+func (p *Phased) Simplifying() bool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.simplifying
+}
+
+// Update is a callback, usually subscribed to the Evaluated event, that switches the phase based
+// on the population's mean complexity and best fitness
+func (p *Phased) Update(pop evo.Population) (err error) {
+	if len(pop.Genomes) == 0 {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	// Measure the population
+	var mean float64
+	best := pop.Genomes[0].Fitness
+	for _, g := range pop.Genomes {
+		mean += float64(g.Complexity())
+		if best < g.Fitness {
+			best = g.Fitness
+		}
+	}
+	mean /= float64(len(pop.Genomes))
+
+	// The first population sets the floor
+	if !p.started {
+		p.started = true
+		p.floor = mean
+		p.best = best
+		return
+	}
+
+	// Continue simplifying until the mean complexity stops falling
+	if p.simplifying {
+		if mean < p.lowest {
+			p.lowest = mean
+			p.unchanged = 0
+			return
+		}
+		p.unchanged++
+		limit := p.SimplifyLimit
+		if limit < 1 {
+			limit = 1
+		}
+		if p.unchanged >= limit {
+			p.simplifying = false
+			p.floor = mean
+			p.best = best
+			p.stagnant = 0
+			err = p.toggle(false)
+		}
+		return
+	}
+
+	// Continue complexifying until the complexity threshold is crossed or fitness stagnates
+	if best > p.best {
+		p.best = best
+		p.stagnant = 0
+	} else {
+		p.stagnant++
+	}
+	if (p.ComplexityThreshold > 0.0 && mean > p.floor+p.ComplexityThreshold) ||
+		(p.StagnationLimit > 0 && p.stagnant >= p.StagnationLimit) {
+		p.simplifying = true
+		p.lowest = mean
+		p.unchanged = 0
+		err = p.toggle(true)
+	}
+	return
+}
+
+// Toggle the selector's mutate only mode, if there is a selector
+func (p *Phased) toggle(on bool) error {
+	if p.Selector == nil {
+		return nil
+	}
+	return p.Selector.ToggleMutateOnly(on)
+}
+
+// phasedState is the serialisable form of the phased mutator's internal state
+type phasedState struct {
+	Simplifying bool
+	Started     bool
+	Floor       float64
+	Lowest      float64
+	Best        float64
+	Stagnant    int
+	Unchanged   int
+}
+
+// State returns the phased mutator's internal state
+func (p *Phased) State() ([]byte, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return json.Marshal(phasedState{
+		Simplifying: p.simplifying,
+		Started:     p.started,
+		Floor:       p.floor,
+		Lowest:      p.lowest,
+		Best:        p.best,
+		Stagnant:    p.stagnant,
+		Unchanged:   p.unchanged,
+	})
+}
+
+// Restore the phased mutator's internal state. The selector's mutate only mode is restored with
+// its own state.
+func (p *Phased) Restore(b []byte) (err error) {
+	var x phasedState
+	if err = json.Unmarshal(b, &x); err != nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.simplifying = x.Simplifying
+	p.started = x.Started
+	p.floor = x.Floor
+	p.lowest = x.Lowest
+	p.best = x.Best
+	p.stagnant = x.Stagnant
+	p.unchanged = x.Unchanged
+	return
+}
@@ -0,0 +1,163 @@
+package neat
+
+import (
+	"testing"
+
+	"github.com/klokare/evo"
+	"github.com/klokare/evo/neat/mutator"
+)
+
+type mockToggler struct{ On []bool }
+
+func (m *mockToggler) ToggleMutateOnly(on bool) error {
+	m.On = append(m.On, on)
+	return nil
+}
+
+// Create a population with the mean complexity and best fitness
+func newPhasedPopulation(complexity int, fitness float64) evo.Population {
+	g := evo.Genome{Fitness: fitness, Encoded: evo.Substrate{Nodes: make([]evo.Node, complexity)}}
+	return evo.Population{Genomes: []evo.Genome{g, {Encoded: g.Encoded}}}
+}
+
+func TestPhasedUpdate(t *testing.T) {
+	type step struct {
+		Complexity  int
+		Fitness     float64
+		Simplifying bool
+	}
+	var cases = []struct {
+		Desc                string
+		ComplexityThreshold float64
+		StagnationLimit     int
+		SimplifyLimit       int
+		Steps               []step
+		Toggles             []bool
+	}{
+		{
+			Desc:                "complexity threshold",
+			ComplexityThreshold: 5.0,
+			Steps: []step{
+				{Complexity: 10, Fitness: 1.0},                     // sets the floor
+				{Complexity: 15, Fitness: 2.0},                     // at the threshold
+				{Complexity: 16, Fitness: 3.0, Simplifying: true},  // above the threshold
+				{Complexity: 14, Fitness: 3.0, Simplifying: true},  // complexity is falling
+				{Complexity: 14, Fitness: 3.0, Simplifying: false}, // complexity has stopped falling, new floor of 14
+				{Complexity: 19, Fitness: 3.0, Simplifying: false}, // at the new threshold
+				{Complexity: 20, Fitness: 3.0, Simplifying: true},  // above the new threshold
+			},
+			Toggles: []bool{true, false, true},
+		},
+		{
+			Desc:            "fitness stagnation",
+			StagnationLimit: 2,
+			SimplifyLimit:   2,
+			Steps: []step{
+				{Complexity: 10, Fitness: 1.0},
+				{Complexity: 50, Fitness: 2.0},                     // improved
+				{Complexity: 50, Fitness: 2.0},                     // stagnant for 1
+				{Complexity: 50, Fitness: 1.5, Simplifying: true},  // stagnant for 2
+				{Complexity: 40, Fitness: 1.5, Simplifying: true},  // complexity is falling
+				{Complexity: 40, Fitness: 1.5, Simplifying: true},  // unchanged for 1
+				{Complexity: 41, Fitness: 1.5, Simplifying: false}, // unchanged for 2
+				{Complexity: 41, Fitness: 1.0, Simplifying: false}, // stagnation restarts with the new phase
+			},
+			Toggles: []bool{true, false},
+		},
+		{
+			Desc: "never simplifies without limits",
+			Steps: []step{
+				{Complexity: 10, Fitness: 1.0},
+				{Complexity: 100, Fitness: 1.0},
+				{Complexity: 1000, Fitness: 1.0},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Desc, func(t *testing.T) {
+			tg := &mockToggler{}
+			p := &Phased{
+				Selector:            tg,
+				ComplexityThreshold: c.ComplexityThreshold,
+				StagnationLimit:     c.StagnationLimit,
+				SimplifyLimit:       c.SimplifyLimit,
+			}
+			for i, s := range c.Steps {
+				if err := p.Update(newPhasedPopulation(s.Complexity, s.Fitness)); err != nil {
+					t.Fatalf("error not expected: %v", err)
+				}
+				if p.Simplifying() != s.Simplifying {
+					t.Errorf("incorrect phase after step %d: expected simplifying %v, actual %v", i, s.Simplifying, p.Simplifying())
+				}
+			}
+			if len(tg.On) != len(c.Toggles) {
+				t.Fatalf("incorrect number of toggles: expected %v, actual %v", c.Toggles, tg.On)
+			}
+			for i, on := range c.Toggles {
+				if tg.On[i] != on {
+					t.Errorf("incorrect toggle %d: expected %v, actual %v", i, on, tg.On[i])
+				}
+			}
+		})
+	}
+}
+
+func TestPhasedMutate(t *testing.T) {
+
+	// A genome with a single hidden node which can be split or deleted
+	newGenome := func() evo.Genome {
+		in := evo.Position{Layer: 0.0, X: 0.0}
+		hid := evo.Position{Layer: 0.5, X: 0.5}
+		out := evo.Position{Layer: 1.0, X: 1.0}
+		return evo.Genome{Encoded: evo.Substrate{
+			Nodes: []evo.Node{{Position: in, Neuron: evo.Input}, {Position: hid, Neuron: evo.Hidden}, {Position: out, Neuron: evo.Output}},
+			Conns: []evo.Conn{{Source: in, Target: hid, Enabled: true}, {Source: hid, Target: out, Enabled: true}},
+		}}
+	}
+	p := &Phased{
+		Complexify:          mutator.Complexify{AddNodeProbability: 1.0},
+		Simplify:            mutator.Simplify{DeleteNodeProbability: 1.0},
+		ComplexityThreshold: 1.0,
+	}
+
+	// Complexify first
+	g := newGenome()
+	if err := p.Mutate(&g); err != nil {
+		t.Fatalf("error not expected: %v", err)
+	}
+	if len(g.Encoded.Nodes) != 4 {
+		t.Errorf("incorrect number of nodes when complexifying: expected 4, actual %d", len(g.Encoded.Nodes))
+	}
+
+	// Then simplify once the threshold has been crossed
+	p.Update(newPhasedPopulation(1, 0.0))
+	p.Update(newPhasedPopulation(5, 0.0))
+	g = newGenome()
+	if err := p.Mutate(&g); err != nil {
+		t.Fatalf("error not expected: %v", err)
+	}
+	if len(g.Encoded.Nodes) != 2 {
+		t.Errorf("incorrect number of nodes when simplifying: expected 2, actual %d", len(g.Encoded.Nodes))
+	}
+}
+
+func TestPhasedStateRestore(t *testing.T) {
+	p1 := &Phased{ComplexityThreshold: 1.0}
+	p1.Update(newPhasedPopulation(1, 0.0))
+	p1.Update(newPhasedPopulation(5, 0.0))
+	b, err := p1.State()
+	if err != nil {
+		t.Fatalf("error not expected: %v", err)
+	}
+	p2 := &Phased{ComplexityThreshold: 1.0}
+	if err = p2.Restore(b); err != nil {
+		t.Fatalf("error not expected: %v", err)
+	}
+	if !p2.Simplifying() {
+		t.Error("restored phased mutator should be simplifying")
+	}
+	if err = p2.Restore([]byte("bogus")); err == nil {
+		t.Error("expected error not found")
+	}
+}
@@ -23,6 +23,12 @@ type Populator struct {
 
 // Populate creates a new population by creating randomised version of a seed genome.
 func (p Populator) Populate() (pop evo.Population, err error) {
+	return p.PopulateWith(evo.NewRandom())
+}
+
+// PopulateWith creates a new population, drawing from the random stream. The stream is also passed
+// to the seeder if it accepts one.
+func (p Populator) PopulateWith(rng evo.Random) (pop evo.Population, err error) {
 
 	// Check for errors
 	if p.PopulationSize < 1 {
@@ -35,14 +41,18 @@ func (p Populator) Populate() (pop evo.Population, err error) {
 
 	// Create the seed genome
 	var seed evo.Genome
-	if seed, err = p.Seeder.Seed(); err != nil {
+	if sx, ok := p.Seeder.(evo.RandomSeeder); ok {
+		seed, err = sx.SeedWith(rng)
+	} else {
+		seed, err = p.Seeder.Seed()
+	}
+	if err != nil {
 		return
 	}
 
 	// Create the genomes
 	var g evo.Genome
 	pop.Genomes = make([]evo.Genome, p.PopulationSize)
-	rng := evo.NewRandom()
 	for i := 0; i < p.PopulationSize; i++ {
 
 		// Clone the seed genome
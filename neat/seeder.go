@@ -24,6 +24,11 @@ type Seeder struct {
 
 // Seed creates an unitialised genome from the specifications.
 func (s Seeder) Seed() (g evo.Genome, err error) {
+	return s.SeedWith(evo.NewRandom())
+}
+
+// SeedWith creates an unitialised genome, drawing from the random stream to disconnect sensors
+func (s Seeder) SeedWith(rng evo.Random) (g evo.Genome, err error) {
 
 	// Check for errors
 	if s.NumInputs <= 0 {
@@ -72,7 +77,6 @@ func (s Seeder) Seed() (g evo.Genome, err error) {
 
 	// Connect the sensors to the outputs
 	if s.DisconnectRate < 1.0 {
-		rng := evo.NewRandom()
 		for _, src := range g.Encoded.Nodes[:s.NumInputs] {
 			for _, tgt := range g.Encoded.Nodes[s.NumInputs:] {
 				if rng.Float64() > s.DisconnectRate {
@@ -1,6 +1,7 @@
 package neat
 
 import (
+	"encoding/json"
 	"errors"
 	"math"
 
@@ -31,6 +32,12 @@ type Selector struct {
 
 // Select the genomes to continue and those to become parents
 func (s *Selector) Select(pop evo.Population) (continuing []evo.Genome, parents [][]evo.Genome, err error) {
+	return s.SelectWith(evo.NewRandom(), pop)
+}
+
+// SelectWith selects the genomes to continue and those to become parents, drawing from the random
+// stream
+func (s *Selector) SelectWith(rng evo.Random, pop evo.Population) (continuing []evo.Genome, parents [][]evo.Genome, err error) {
 
 	// Sort and rank the genomes
 	genomes := make([]evo.Genome, len(pop.Genomes))
@@ -127,7 +134,6 @@ func (s *Selector) Select(pop evo.Population) (continuing []evo.Genome, parents
 	}
 
 	// Handle rounding errors by adjusting 1 offspring at a time
-	rng := evo.NewRandom()
 	adjCounts(rng, off, cnt, tgt)
 
 	// Generate parents
@@ -215,8 +221,10 @@ func adjCounts(rng evo.Random, off []int, cnt, tgt int) {
 		adj = -1
 	}
 
-	// Make multiple passes
+	// Make multiple passes. If no species can be adjusted, as when there are more species than
+	// offspring and each already has only 1, the count is left as is.
 	for cnt != tgt {
+		prev := cnt
 		idxs := rng.Perm(len(off))
 		for _, i := range idxs {
 			if off[i] > 0 && off[i]+adj > 0 {
@@ -227,6 +235,9 @@ func adjCounts(rng evo.Random, off []int, cnt, tgt int) {
 				}
 			}
 		}
+		if cnt == prev {
+			break
+		}
 	}
 	return
 }
@@ -253,3 +264,34 @@ func roulette(rng evo.Random, genomes []evo.Genome, ranks map[int64]float64) (pa
 	}
 	return
 }
+
+// selectorState is the serialisable form of the selector's internal state
+type selectorState struct {
+	MutateOnlyProbability float64
+	PrevBest              int64
+	LastImproved          int
+	MOP                   float64
+}
+
+// State returns the selector's internal state, including any toggled mutate only probability
+func (s *Selector) State() ([]byte, error) {
+	return json.Marshal(selectorState{
+		MutateOnlyProbability: s.MutateOnlyProbability,
+		PrevBest:              s.prevBest,
+		LastImproved:          s.lastImproved,
+		MOP:                   s.mop,
+	})
+}
+
+// Restore the selector's internal state
+func (s *Selector) Restore(b []byte) (err error) {
+	var x selectorState
+	if err = json.Unmarshal(b, &x); err != nil {
+		return
+	}
+	s.MutateOnlyProbability = x.MutateOnlyProbability
+	s.prevBest = x.PrevBest
+	s.lastImproved = x.LastImproved
+	s.mop = x.MOP
+	return
+}
@@ -348,6 +348,21 @@ func TestSelectorAdjCountHigh(t *testing.T) {
 	}
 }
 
+func TestSelectorAdjCountTooManySpecies(t *testing.T) {
+	// each species already has the minimum so the count cannot be lowered to the target. Without a
+	// check for progress the adjustment would loop forever.
+	cnt := 3
+	tgt := 2
+	off := []int{1, 1, 1}
+
+	adjCounts(evo.NewRandom(), off, cnt, tgt)
+	for i, x := range off {
+		if x != 1 {
+			t.Errorf("incorrect count for species %d: expected 1, actual %d", i, x)
+		}
+	}
+}
+
 func TestToggleMutateOnly(t *testing.T) {
 
 	// Create a new selector with a non-zero (so we can check) mutate only probability
@@ -368,3 +383,37 @@ func TestToggleMutateOnly(t *testing.T) {
 		t.Errorf("incorrect mutate only value when toggled off: expected %f, actual %f", 0.5, s.MutateOnlyProbability)
 	}
 }
+
+func TestSelectorStateRestore(t *testing.T) {
+
+	// Create a selector with internal state and toggle mutate only on
+	s1 := &Selector{MutateOnlyProbability: 0.5, prevBest: 10, lastImproved: 3}
+	s1.ToggleMutateOnly(true)
+
+	// Save the state and restore into a new selector
+	b, err := s1.State()
+	if err != nil {
+		t.Fatalf("error not expected: %v", err)
+	}
+	s2 := &Selector{MutateOnlyProbability: 0.5}
+	if err = s2.Restore(b); err != nil {
+		t.Fatalf("error not expected: %v", err)
+	}
+
+	// Compare the states
+	if s2.prevBest != s1.prevBest {
+		t.Errorf("incorrect previous best: expected %d, actual %d", s1.prevBest, s2.prevBest)
+	}
+	if s2.lastImproved != s1.lastImproved {
+		t.Errorf("incorrect last improved: expected %d, actual %d", s1.lastImproved, s2.lastImproved)
+	}
+	if s2.MutateOnlyProbability != 1.0 {
+		t.Errorf("incorrect mutate only value: expected 1.0, actual %f", s2.MutateOnlyProbability)
+	}
+
+	// Toggling off should restore the original probability
+	s2.ToggleMutateOnly(false)
+	if s2.MutateOnlyProbability != 0.5 {
+		t.Errorf("incorrect mutate only value when toggled off: expected %f, actual %f", 0.5, s2.MutateOnlyProbability)
+	}
+}
@@ -1,6 +1,7 @@
 package neat
 
 import (
+	"encoding/json"
 	"errors"
 	"sort"
 
@@ -130,3 +131,31 @@ func (s *Speciator) Speciate(pop *evo.Population) (err error) {
 	}
 	return
 }
+
+// speciatorState is the serialisable form of the speciator's internal state
+type speciatorState struct {
+	CompatibilityThreshold float64
+	LastSID                int
+	Examples               map[int]evo.Genome
+}
+
+// State returns the speciator's internal state, including the adjusted compatibility threshold
+func (s *Speciator) State() ([]byte, error) {
+	return json.Marshal(speciatorState{
+		CompatibilityThreshold: s.CompatibilityThreshold,
+		LastSID:                s.lastSID,
+		Examples:               s.examples,
+	})
+}
+
+// Restore the speciator's internal state
+func (s *Speciator) Restore(b []byte) (err error) {
+	var x speciatorState
+	if err = json.Unmarshal(b, &x); err != nil {
+		return
+	}
+	s.CompatibilityThreshold = x.CompatibilityThreshold
+	s.lastSID = x.LastSID
+	s.examples = x.Examples
+	return
+}
@@ -250,3 +250,62 @@ func (d MockDistancer) Distance(a, b evo.Genome) (float64, error) {
 	}
 	return math.Abs(float64(a.Complexity() - b.Complexity())), nil
 }
+
+func TestSpeciatorStateRestore(t *testing.T) {
+
+	// Speciate a population so the speciator has internal state
+	s1 := &Speciator{
+		Distancer:              MockDistancer{},
+		CompatibilityThreshold: 1.0,
+		CompatibilityModifier:  0.5,
+		TargetSpecies:          5,
+	}
+	pop := evo.Population{
+		Genomes: []evo.Genome{
+			{ID: 1, Encoded: evo.Substrate{Nodes: []evo.Node{{}, {}}}},
+			{ID: 2, Encoded: evo.Substrate{Nodes: []evo.Node{{}, {}, {}}}},
+		},
+	}
+	if err := s1.Speciate(&pop); err != nil {
+		t.Fatalf("error not expected: %v", err)
+	}
+
+	// Save the state and restore into a new speciator
+	b, err := s1.State()
+	if err != nil {
+		t.Fatalf("error not expected: %v", err)
+	}
+	s2 := &Speciator{Distancer: MockDistancer{}, CompatibilityModifier: 0.5, TargetSpecies: 5}
+	if err = s2.Restore(b); err != nil {
+		t.Fatalf("error not expected: %v", err)
+	}
+
+	// Compare the states
+	if s2.CompatibilityThreshold != s1.CompatibilityThreshold {
+		t.Errorf("incorrect compatibility threshold: expected %f, actual %f", s1.CompatibilityThreshold, s2.CompatibilityThreshold)
+	}
+	if s2.lastSID != s1.lastSID {
+		t.Errorf("incorrect last species ID: expected %d, actual %d", s1.lastSID, s2.lastSID)
+	}
+	if len(s2.examples) != len(s1.examples) {
+		t.Fatalf("incorrect number of examples: expected %d, actual %d", len(s1.examples), len(s2.examples))
+	}
+	for sid, e1 := range s1.examples {
+		if e2, ok := s2.examples[sid]; !ok || e1.ID != e2.ID {
+			t.Errorf("incorrect example for species %d: expected %d, actual %d", sid, e1.ID, e2.ID)
+		}
+	}
+
+	// A new genome should be assigned to the same species by both speciators
+	p1 := evo.Population{Genomes: []evo.Genome{{ID: 3, Encoded: evo.Substrate{Nodes: []evo.Node{{}, {}, {}, {}}}}}}
+	p2 := evo.Population{Genomes: []evo.Genome{{ID: 3, Encoded: evo.Substrate{Nodes: []evo.Node{{}, {}, {}, {}}}}}}
+	if err = s1.Speciate(&p1); err != nil {
+		t.Fatalf("error not expected: %v", err)
+	}
+	if err = s2.Speciate(&p2); err != nil {
+		t.Fatalf("error not expected: %v", err)
+	}
+	if p1.Genomes[0].Species != p2.Genomes[0].Species {
+		t.Errorf("restored speciator assigned a different species: expected %d, actual %d", p1.Genomes[0].Species, p2.Genomes[0].Species)
+	}
+}
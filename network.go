@@ -64,6 +64,9 @@ func (a Activation) String() string {
 	case ReLU:
 		return "relu"
 	default:
+		if c := customs[a]; c != nil {
+			return c.name
+		}
 		return "unknown"
 	}
 }
@@ -91,11 +94,15 @@ func (a Activation) Activate(x float64) float64 {
 		}
 		return 0
 	default:
+		if c := customs[a]; c != nil {
+			return c.fn(x)
+		}
 		panic("unknown activation")
 	}
 }
 
-// Activations provides map of activation functions by name
+// Activations provides map of activation functions by name, including those added with
+// RegisterActivation
 var Activations = map[string]Activation{
 	"direct":            Direct,
 	"sigmoid":           Sigmoid,
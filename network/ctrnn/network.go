@@ -0,0 +1,104 @@
+package ctrnn
+
+import (
+	"errors"
+
+	"github.com/klokare/evo"
+	"gonum.org/v1/gonum/mat"
+)
+
+// Known errors
+var (
+	ErrInputsMismatch = errors.New("number of input columns does not match number of input neurons")
+)
+
+// Synapse is an incoming connection to a neuron
+type Synapse struct {
+	Source int     // index of the source neuron
+	Weight float64 // the connection weight
+}
+
+// Neuron in the continuous-time network
+type Neuron struct {
+	Type         evo.Neuron     // the type of neuron
+	Activation   evo.Activation // activation function for the neuron
+	Bias         float64        // bias value for the neuron
+	TimeConstant float64        // time constant of the neuron's response
+	Synapses     []Synapse      // incoming connections
+}
+
+// Network of neurons whose values change continuously in time. The value y of each neuron follows
+//
+//	TimeConstant * dy/dt = -y + f(Bias + sum of Weight * source's y)
+//
+// which is integrated by Euler's method. Each call to Activate sets the input neurons' values and
+// advances the network by the number of steps, of size TimeStep, and the neurons' values are kept
+// for the next call. Each row of the inputs is treated as an independent sequence. The state is
+// reset if the number of rows changes.
+type Network struct {
+	Neurons  []Neuron // neurons ordered by position on the substrate
+	Inputs   []int    // indexes of the input neurons
+	Outputs  []int    // indexes of the output neurons
+	TimeStep float64  // size of each integration step
+	Steps    int      // number of integration steps in each activation
+	state    [][]float64
+}
+
+// Activate advances the network using the incoming matrix of values
+func (net *Network) Activate(inputs evo.Matrix) (outputs evo.Matrix, err error) {
+
+	// Check the inputs
+	r, c := inputs.Dims()
+	if c != len(net.Inputs) {
+		err = ErrInputsMismatch
+		return
+	}
+
+	// Ensure there is state for each row
+	if len(net.state) != r {
+		net.state = make([][]float64, r)
+		for i := 0; i < r; i++ {
+			net.state[i] = make([]float64, len(net.Neurons))
+		}
+	}
+
+	// Iterate the rows
+	out := mat.NewDense(r, len(net.Outputs), nil)
+	for i := 0; i < r; i++ {
+		prev := net.state[i]
+		next := make([]float64, len(net.Neurons))
+
+		// Set the input values
+		for j, idx := range net.Inputs {
+			prev[idx] = inputs.At(i, j)
+		}
+
+		// Integrate the neurons' values. Every neuron sees the values of the previous step.
+		for s := 0; s < net.Steps; s++ {
+			copy(next, prev)
+			for j, n := range net.Neurons {
+				if n.Type == evo.Input {
+					continue
+				}
+				x := n.Bias
+				for _, syn := range n.Synapses {
+					x += prev[syn.Source] * syn.Weight
+				}
+				next[j] += net.TimeStep / n.TimeConstant * (n.Activation.Activate(x) - prev[j])
+			}
+			prev, next = next, prev
+		}
+
+		// Record the outputs and save the state
+		for j, idx := range net.Outputs {
+			out.Set(i, j, prev[idx])
+		}
+		net.state[i] = prev
+	}
+
+	outputs = out
+	return
+}
+
+// Reset clears the network's state
+func (net *Network) Reset() { net.state = nil }
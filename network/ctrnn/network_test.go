@@ -0,0 +1,191 @@
+package ctrnn
+
+import (
+	"math"
+	"testing"
+
+	"github.com/klokare/evo"
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestTranslatorErrors(t *testing.T) {
+	var (
+		in  = evo.Node{Position: evo.Position{Layer: 0.0}, Neuron: evo.Input, Activation: evo.Direct}
+		out = evo.Node{Position: evo.Position{Layer: 1.0}, Neuron: evo.Output, Activation: evo.Direct}
+	)
+	var cases = []struct {
+		Desc     string
+		HasError bool
+		TimeStep float64
+		evo.Substrate
+	}{
+		{Desc: "no time step", HasError: true, Substrate: evo.Substrate{Nodes: []evo.Node{in, out}}},
+		{Desc: "no inputs", HasError: true, TimeStep: 0.1, Substrate: evo.Substrate{Nodes: []evo.Node{out}}},
+		{Desc: "no outputs", HasError: true, TimeStep: 0.1, Substrate: evo.Substrate{Nodes: []evo.Node{in}}},
+		{
+			Desc:     "unknown source",
+			HasError: true,
+			TimeStep: 0.1,
+			Substrate: evo.Substrate{
+				Nodes: []evo.Node{in, out},
+				Conns: []evo.Conn{{Source: evo.Position{Layer: 0.5}, Target: out.Position, Enabled: true}},
+			},
+		},
+		{
+			Desc:     "unknown target",
+			HasError: true,
+			TimeStep: 0.1,
+			Substrate: evo.Substrate{
+				Nodes: []evo.Node{in, out},
+				Conns: []evo.Conn{{Source: in.Position, Target: evo.Position{Layer: 0.5}, Enabled: true}},
+			},
+		},
+		{
+			Desc:     "input as target",
+			HasError: true,
+			TimeStep: 0.1,
+			Substrate: evo.Substrate{
+				Nodes: []evo.Node{in, out},
+				Conns: []evo.Conn{{Source: out.Position, Target: in.Position, Enabled: true}},
+			},
+		},
+		{
+			Desc:     "recurrent connection",
+			HasError: false,
+			TimeStep: 0.1,
+			Substrate: evo.Substrate{
+				Nodes: []evo.Node{in, out},
+				Conns: []evo.Conn{{Source: out.Position, Target: out.Position, Enabled: true}},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Desc, func(t *testing.T) {
+			_, err := Translator{TimeStep: c.TimeStep}.Translate(c.Substrate)
+			if c.HasError && err == nil {
+				t.Error("expected error not found")
+			} else if !c.HasError && err != nil {
+				t.Errorf("error not expected: %v", err)
+			}
+		})
+	}
+}
+
+func TestNetworkActivate(t *testing.T) {
+
+	// Positions of the neurons
+	var (
+		in  = evo.Position{Layer: 0.0}
+		hid = evo.Position{Layer: 0.5}
+		out = evo.Position{Layer: 1.0}
+	)
+
+	var cases = []struct {
+		Desc         string
+		TimeConstant float64
+		Steps        int
+		Hidden       bool
+		Inputs       []float64 // one input per activation
+		Expected     []float64 // output per activation
+	}{
+		{
+			Desc:     "unset time constant responds within a step",
+			Inputs:   []float64{1.0, 1.0, 0.0},
+			Expected: []float64{1.0, 1.0, 0.0},
+		},
+		{
+			Desc:         "larger time constant responds gradually",
+			TimeConstant: 0.2,
+			Inputs:       []float64{1.0, 1.0, 1.0},
+			Expected:     []float64{0.5, 0.75, 0.875},
+		},
+		{
+			Desc:         "multiple steps in each activation",
+			TimeConstant: 0.2,
+			Steps:        2,
+			Inputs:       []float64{1.0, 1.0},
+			Expected:     []float64{0.75, 0.9375},
+		},
+		{
+			Desc:     "values pass through a hidden neuron one step later",
+			Hidden:   true,
+			Inputs:   []float64{1.0, 1.0, 0.0, 0.0},
+			Expected: []float64{0.0, 1.0, 1.0, 0.0},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Desc, func(t *testing.T) {
+
+			// Create the network. All neurons use direct activation to make checking easier.
+			sub := evo.Substrate{
+				Nodes: []evo.Node{
+					{Position: in, Neuron: evo.Input, Activation: evo.Direct},
+					{Position: out, Neuron: evo.Output, Activation: evo.Direct, TimeConstant: c.TimeConstant},
+				},
+				Conns: []evo.Conn{{Source: in, Target: out, Weight: 1.0, Enabled: true}},
+			}
+			if c.Hidden {
+				sub.Nodes = append(sub.Nodes, evo.Node{Position: hid, Neuron: evo.Hidden, Activation: evo.Direct, TimeConstant: c.TimeConstant})
+				sub.Conns = []evo.Conn{
+					{Source: in, Target: hid, Weight: 1.0, Enabled: true},
+					{Source: hid, Target: out, Weight: 1.0, Enabled: true},
+				}
+			}
+			net, err := Translator{TimeStep: 0.1, Steps: c.Steps}.Translate(sub)
+			if err != nil {
+				t.Fatalf("error not expected: %v", err)
+			}
+
+			// Activate the network for each input
+			for i, x := range c.Inputs {
+				var outputs evo.Matrix
+				if outputs, err = net.Activate(mat.NewDense(1, 1, []float64{x})); err != nil {
+					t.Fatalf("error not expected: %v", err)
+				}
+				if math.Abs(outputs.At(0, 0)-c.Expected[i]) > 1e-9 {
+					t.Errorf("incorrect output at activation %d: expected %f, actual %f", i, c.Expected[i], outputs.At(0, 0))
+				}
+			}
+		})
+	}
+}
+
+func TestNetworkReset(t *testing.T) {
+
+	// Create a network that responds gradually
+	sub := evo.Substrate{
+		Nodes: []evo.Node{
+			{Position: evo.Position{Layer: 0.0}, Neuron: evo.Input, Activation: evo.Direct},
+			{Position: evo.Position{Layer: 1.0}, Neuron: evo.Output, Activation: evo.Direct, TimeConstant: 0.2},
+		},
+		Conns: []evo.Conn{
+			{Source: evo.Position{Layer: 0.0}, Target: evo.Position{Layer: 1.0}, Weight: 1.0, Enabled: true},
+		},
+	}
+	net, err := Translator{TimeStep: 0.1}.Translate(sub)
+	if err != nil {
+		t.Fatalf("error not expected: %v", err)
+	}
+
+	// Rows are independent sequences
+	inputs := mat.NewDense(2, 1, []float64{1.0, 0.0})
+	net.Activate(inputs)
+	outputs, _ := net.Activate(inputs)
+	if outputs.At(0, 0) != 0.75 || outputs.At(1, 0) != 0.0 {
+		t.Errorf("incorrect outputs: expected [0.75 0], actual [%f %f]", outputs.At(0, 0), outputs.At(1, 0))
+	}
+
+	// Resetting clears the state
+	net.(*Network).Reset()
+	outputs, _ = net.Activate(inputs)
+	if outputs.At(0, 0) != 0.5 {
+		t.Errorf("incorrect output after reset: expected 0.5, actual %f", outputs.At(0, 0))
+	}
+
+	// Mismatched inputs
+	if _, err = net.Activate(mat.NewDense(1, 2, nil)); err == nil {
+		t.Error("expected error not found")
+	}
+}
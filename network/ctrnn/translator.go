@@ -0,0 +1,77 @@
+// Package ctrnn provides continuous-time recurrent neural networks, as used for the controllers of
+// robots and other agents acting in time. Each neuron responds to its inputs at the rate set by its
+// time constant and the network's values are integrated by Euler's method.
+package ctrnn
+
+import (
+	"errors"
+
+	"github.com/klokare/evo"
+	"github.com/klokare/evo/internal/layout"
+)
+
+// Known errors
+var (
+	ErrNoSensors         = layout.ErrNoSensors
+	ErrNoOutputs         = layout.ErrNoOutputs
+	ErrUnknownConnSource = layout.ErrUnknownConnSource
+	ErrUnknownConnTarget = layout.ErrUnknownConnTarget
+	ErrInputAsTarget     = layout.ErrInputAsTarget
+	ErrInvalidTimeStep   = errors.New("time step must be greater than zero")
+)
+
+// Translator transforms substrates into continuous-time networks. As every connection uses the
+// values of the previous step, connections may point in any direction. A node's time constant is
+// never less than the time step; those less, including unset time constants, are raised to it so
+// that the neuron takes its new value within a single step.
+type Translator struct {
+	TimeStep         float64 // Size of each integration step
+	Steps            int     // Number of integration steps in each activation. If zero, 1 is used.
+	DisableSortCheck bool
+}
+
+// Translate the substrate into a network
+func (t Translator) Translate(sub evo.Substrate) (net evo.Network, err error) {
+
+	// Check for errors
+	if t.TimeStep <= 0.0 {
+		return nil, ErrInvalidTimeStep
+	}
+
+	// Index the neurons and the enabled connections
+	var l layout.Layout
+	if l, err = layout.New(sub, t.DisableSortCheck); err != nil {
+		return
+	}
+
+	// Create the neurons
+	n := &Network{
+		Neurons:  make([]Neuron, len(l.Nodes)),
+		Inputs:   l.Inputs,
+		Outputs:  l.Outputs,
+		TimeStep: t.TimeStep,
+		Steps:    t.Steps,
+	}
+	if n.Steps < 1 {
+		n.Steps = 1
+	}
+	for i, node := range l.Nodes {
+		n.Neurons[i] = Neuron{
+			Type:         node.Neuron,
+			Activation:   node.Activation,
+			Bias:         node.Bias,
+			TimeConstant: node.TimeConstant,
+		}
+		if n.Neurons[i].TimeConstant < t.TimeStep {
+			n.Neurons[i].TimeConstant = t.TimeStep
+		}
+	}
+
+	// Add the synapses to their target neurons
+	for _, s := range l.Synapses {
+		n.Neurons[s.Target].Synapses = append(n.Neurons[s.Target].Synapses, Synapse{Source: s.Source, Weight: s.Weight})
+	}
+
+	// Return the new network
+	return n, nil
+}
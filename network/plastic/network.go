@@ -0,0 +1,148 @@
+package plastic
+
+import (
+	"errors"
+
+	"github.com/klokare/evo"
+	"gonum.org/v1/gonum/mat"
+)
+
+// Known errors
+var (
+	ErrInputsMismatch = errors.New("number of input columns does not match number of input neurons")
+)
+
+// Synapse is an incoming connection to a neuron whose weight adapts by its Hebbian rule
+type Synapse struct {
+	Source    int         // index of the source neuron
+	Weight    float64     // the initial connection weight
+	Recurrent bool        // true if the source's value from the previous activation is used
+	Rule      evo.Hebbian // the rule by which the weight adapts
+}
+
+// Neuron in the plastic network
+type Neuron struct {
+	Type       evo.Neuron     // the type of neuron
+	Activation evo.Activation // activation function for the neuron
+	Bias       float64        // bias value for the neuron
+	Synapses   []Synapse      // incoming connections
+}
+
+// Network of neurons whose connection weights adapt during its lifetime. Each call to Activate
+// advances the network by one time step, after which each synapse's weight is changed by its rule
+// using the values of its source and target neurons. Each row of the inputs is treated as an
+// independent sequence with its own neuron values and weights. The state, including the adapted
+// weights, is reset if the number of rows changes.
+type Network struct {
+	Neurons   []Neuron // neurons ordered by position on the substrate
+	Inputs    []int    // indexes of the input neurons
+	Outputs   []int    // indexes of the output neurons
+	MaxWeight float64  // adapted weights are kept within [-MaxWeight, MaxWeight]. Ignored if zero.
+	state     [][]float64
+	weights   [][][]float64
+}
+
+// Activate advances the network one step using the incoming matrix of values and then adapts the
+// weights
+func (net *Network) Activate(inputs evo.Matrix) (outputs evo.Matrix, err error) {
+
+	// Check the inputs
+	r, c := inputs.Dims()
+	if c != len(net.Inputs) {
+		err = ErrInputsMismatch
+		return
+	}
+
+	// Ensure there is state for each row
+	if len(net.state) != r {
+		net.state = make([][]float64, r)
+		net.weights = make([][][]float64, r)
+		for i := 0; i < r; i++ {
+			net.state[i] = make([]float64, len(net.Neurons))
+			net.weights[i] = make([][]float64, len(net.Neurons))
+			for j, n := range net.Neurons {
+				net.weights[i][j] = make([]float64, len(n.Synapses))
+				for k, s := range n.Synapses {
+					net.weights[i][j][k] = s.Weight
+				}
+			}
+		}
+	}
+
+	// Iterate the rows
+	out := mat.NewDense(r, len(net.Outputs), nil)
+	for i := 0; i < r; i++ {
+		prev := net.state[i]
+		next := make([]float64, len(net.Neurons))
+		weights := net.weights[i]
+
+		// Set the input values
+		for j, idx := range net.Inputs {
+			next[idx] = inputs.At(i, j)
+		}
+
+		// Activate the remaining neurons in order
+		for j, n := range net.Neurons {
+			if n.Type == evo.Input {
+				continue
+			}
+			x := n.Bias
+			for k, s := range n.Synapses {
+				x += source(s, prev, next) * weights[j][k]
+			}
+			next[j] = n.Activation.Activate(x)
+		}
+
+		// Adapt the weights using the values of this step
+		for j, n := range net.Neurons {
+			for k, s := range n.Synapses {
+				if s.Rule.Rate == 0.0 {
+					continue
+				}
+				w := weights[j][k] + s.Rule.Delta(source(s, prev, next), next[j])
+				if net.MaxWeight > 0.0 {
+					if w > net.MaxWeight {
+						w = net.MaxWeight
+					} else if w < -net.MaxWeight {
+						w = -net.MaxWeight
+					}
+				}
+				weights[j][k] = w
+			}
+		}
+
+		// Record the outputs and save the state
+		for j, idx := range net.Outputs {
+			out.Set(i, j, next[idx])
+		}
+		net.state[i] = next
+	}
+
+	outputs = out
+	return
+}
+
+// Return the value of the synapse's source
+func source(s Synapse, prev, next []float64) float64 {
+	if s.Recurrent {
+		return prev[s.Source]
+	}
+	return next[s.Source]
+}
+
+// Weights returns the current weights of the neuron's synapses for the row of inputs. Nil is
+// returned if the network has not been activated with that row.
+func (net *Network) Weights(row, neuron int) []float64 {
+	if row >= len(net.weights) || neuron >= len(net.weights[row]) {
+		return nil
+	}
+	ws := make([]float64, len(net.weights[row][neuron]))
+	copy(ws, net.weights[row][neuron])
+	return ws
+}
+
+// Reset clears the network's state and restores the initial weights
+func (net *Network) Reset() {
+	net.state = nil
+	net.weights = nil
+}
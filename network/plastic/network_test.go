@@ -0,0 +1,183 @@
+package plastic
+
+import (
+	"math"
+	"testing"
+
+	"github.com/klokare/evo"
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestTranslatorErrors(t *testing.T) {
+	var (
+		in  = evo.Node{Position: evo.Position{Layer: 0.0}, Neuron: evo.Input, Activation: evo.Direct}
+		out = evo.Node{Position: evo.Position{Layer: 1.0}, Neuron: evo.Output, Activation: evo.Direct}
+	)
+	var cases = []struct {
+		Desc     string
+		HasError bool
+		evo.Substrate
+	}{
+		{Desc: "no inputs", HasError: true, Substrate: evo.Substrate{Nodes: []evo.Node{out}}},
+		{Desc: "no outputs", HasError: true, Substrate: evo.Substrate{Nodes: []evo.Node{in}}},
+		{
+			Desc:     "unknown source",
+			HasError: true,
+			Substrate: evo.Substrate{
+				Nodes: []evo.Node{in, out},
+				Conns: []evo.Conn{{Source: evo.Position{Layer: 0.5}, Target: out.Position, Enabled: true}},
+			},
+		},
+		{
+			Desc:     "unknown target",
+			HasError: true,
+			Substrate: evo.Substrate{
+				Nodes: []evo.Node{in, out},
+				Conns: []evo.Conn{{Source: in.Position, Target: evo.Position{Layer: 0.5}, Enabled: true}},
+			},
+		},
+		{
+			Desc:     "input as target",
+			HasError: true,
+			Substrate: evo.Substrate{
+				Nodes: []evo.Node{in, out},
+				Conns: []evo.Conn{{Source: out.Position, Target: in.Position, Enabled: true}},
+			},
+		},
+		{
+			Desc:     "plastic connection",
+			HasError: false,
+			Substrate: evo.Substrate{
+				Nodes: []evo.Node{in, out},
+				Conns: []evo.Conn{{Source: in.Position, Target: out.Position, Enabled: true, Hebbian: evo.Hebbian{A: 1.0, Rate: 0.1}}},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Desc, func(t *testing.T) {
+			_, err := Translator{}.Translate(c.Substrate)
+			if c.HasError && err == nil {
+				t.Error("expected error not found")
+			} else if !c.HasError && err != nil {
+				t.Errorf("error not expected: %v", err)
+			}
+		})
+	}
+}
+
+func TestNetworkActivate(t *testing.T) {
+
+	// Positions of the neurons
+	var (
+		in  = evo.Position{Layer: 0.0}
+		out = evo.Position{Layer: 1.0}
+	)
+
+	var cases = []struct {
+		Desc      string
+		Rule      evo.Hebbian
+		MaxWeight float64
+		Inputs    []float64 // one input per step
+		Expected  []float64 // output per step
+	}{
+		{
+			Desc:     "zero rate keeps the weight fixed",
+			Rule:     evo.Hebbian{A: 1.0, B: 1.0, C: 1.0, D: 1.0},
+			Inputs:   []float64{1.0, 1.0, 1.0},
+			Expected: []float64{1.0, 1.0, 1.0},
+		},
+		{
+			Desc:     "correlation strengthens the weight",
+			Rule:     evo.Hebbian{A: 1.0, Rate: 0.5},
+			Inputs:   []float64{1.0, 1.0, 1.0},
+			Expected: []float64{1.0, 1.5, 2.25},
+		},
+		{
+			Desc:      "adapted weight is limited",
+			Rule:      evo.Hebbian{A: 1.0, Rate: 0.5},
+			MaxWeight: 2.0,
+			Inputs:    []float64{1.0, 1.0, 1.0},
+			Expected:  []float64{1.0, 1.5, 2.0},
+		},
+		{
+			Desc:     "constant term changes the weight without activity",
+			Rule:     evo.Hebbian{D: -1.0, Rate: 0.25},
+			Inputs:   []float64{1.0, 1.0, 1.0},
+			Expected: []float64{1.0, 0.75, 0.5},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Desc, func(t *testing.T) {
+
+			// Create the network. All neurons use direct activation to make checking easier.
+			sub := evo.Substrate{
+				Nodes: []evo.Node{
+					{Position: in, Neuron: evo.Input, Activation: evo.Direct},
+					{Position: out, Neuron: evo.Output, Activation: evo.Direct},
+				},
+				Conns: []evo.Conn{{Source: in, Target: out, Weight: 1.0, Enabled: true, Hebbian: c.Rule}},
+			}
+			net, err := Translator{MaxWeight: c.MaxWeight}.Translate(sub)
+			if err != nil {
+				t.Fatalf("error not expected: %v", err)
+			}
+
+			// Activate the network for each step
+			for i, x := range c.Inputs {
+				var outputs evo.Matrix
+				if outputs, err = net.Activate(mat.NewDense(1, 1, []float64{x})); err != nil {
+					t.Fatalf("error not expected: %v", err)
+				}
+				if math.Abs(outputs.At(0, 0)-c.Expected[i]) > 1e-9 {
+					t.Errorf("incorrect output at step %d: expected %f, actual %f", i, c.Expected[i], outputs.At(0, 0))
+				}
+			}
+		})
+	}
+}
+
+func TestNetworkWeightsAndReset(t *testing.T) {
+
+	// Create a network whose weight strengthens with correlated activity
+	sub := evo.Substrate{
+		Nodes: []evo.Node{
+			{Position: evo.Position{Layer: 0.0}, Neuron: evo.Input, Activation: evo.Direct},
+			{Position: evo.Position{Layer: 1.0}, Neuron: evo.Output, Activation: evo.Direct},
+		},
+		Conns: []evo.Conn{
+			{Source: evo.Position{Layer: 0.0}, Target: evo.Position{Layer: 1.0}, Weight: 1.0, Enabled: true, Hebbian: evo.Hebbian{A: 1.0, Rate: 0.5}},
+		},
+	}
+	x, err := Translator{}.Translate(sub)
+	if err != nil {
+		t.Fatalf("error not expected: %v", err)
+	}
+	net := x.(*Network)
+	if ws := net.Weights(0, 1); ws != nil {
+		t.Errorf("weights not expected before activation: %v", ws)
+	}
+
+	// Rows are independent sequences. Without activity, the second row's weight is unchanged.
+	inputs := mat.NewDense(2, 1, []float64{1.0, 0.0})
+	net.Activate(inputs)
+	if ws := net.Weights(0, 1); len(ws) != 1 || ws[0] != 1.5 {
+		t.Errorf("incorrect weights for first row: expected [1.5], actual %v", ws)
+	}
+	if ws := net.Weights(1, 1); len(ws) != 1 || ws[0] != 1.0 {
+		t.Errorf("incorrect weights for second row: expected [1], actual %v", ws)
+	}
+
+	// Resetting restores the initial weights
+	net.Reset()
+	outputs, _ := net.Activate(inputs)
+	if outputs.At(0, 0) != 1.0 {
+		t.Errorf("incorrect output after reset: expected 1, actual %f", outputs.At(0, 0))
+	}
+
+	// Mismatched inputs
+	if _, err = net.Activate(mat.NewDense(1, 2, nil)); err == nil {
+		t.Error("expected error not found")
+	}
+}
@@ -0,0 +1,64 @@
+// Package plastic provides networks whose connection weights adapt during their lifetime by the
+// generalised (ABCD) Hebbian rule carried by each connection. Evolving the rules, rather than the
+// weights alone, lets the networks learn within an evaluation.
+package plastic
+
+import (
+	"github.com/klokare/evo"
+	"github.com/klokare/evo/internal/layout"
+)
+
+// Known errors
+var (
+	ErrNoSensors         = layout.ErrNoSensors
+	ErrNoOutputs         = layout.ErrNoOutputs
+	ErrUnknownConnSource = layout.ErrUnknownConnSource
+	ErrUnknownConnTarget = layout.ErrUnknownConnTarget
+	ErrInputAsTarget     = layout.ErrInputAsTarget
+)
+
+// Translator transforms substrates into plastic networks. As with the recurrent translator,
+// connections may point backward (to an earlier or the same layer) or to the source neuron itself.
+type Translator struct {
+	MaxWeight        float64 // Adapted weights are kept within [-MaxWeight, MaxWeight]. Ignored if zero.
+	DisableSortCheck bool
+}
+
+// Translate the substrate into a network
+func (t Translator) Translate(sub evo.Substrate) (net evo.Network, err error) {
+
+	// Index the neurons and the enabled connections
+	var l layout.Layout
+	if l, err = layout.New(sub, t.DisableSortCheck); err != nil {
+		return
+	}
+
+	// Create the neurons
+	n := &Network{
+		Neurons:   make([]Neuron, len(l.Nodes)),
+		Inputs:    l.Inputs,
+		Outputs:   l.Outputs,
+		MaxWeight: t.MaxWeight,
+	}
+	for i, node := range l.Nodes {
+		n.Neurons[i] = Neuron{
+			Type:       node.Neuron,
+			Activation: node.Activation,
+			Bias:       node.Bias,
+		}
+	}
+
+	// Add the synapses to their target neurons. Connections from an earlier layer use the current
+	// step's values and all others, including self-connections, use the previous step's values.
+	for _, s := range l.Synapses {
+		n.Neurons[s.Target].Synapses = append(n.Neurons[s.Target].Synapses, Synapse{
+			Source:    s.Source,
+			Weight:    s.Weight,
+			Recurrent: s.Conn.Source.Layer >= s.Conn.Target.Layer,
+			Rule:      s.Hebbian,
+		})
+	}
+
+	// Return the new network
+	return n, nil
+}
@@ -0,0 +1,97 @@
+package recurrent
+
+import (
+	"errors"
+
+	"github.com/klokare/evo"
+	"gonum.org/v1/gonum/mat"
+)
+
+// Known errors
+var (
+	ErrInputsMismatch = errors.New("number of input columns does not match number of input neurons")
+)
+
+// Synapse is an incoming connection to a neuron
+type Synapse struct {
+	Source    int     // index of the source neuron
+	Weight    float64 // the connection weight
+	Recurrent bool    // true if the source's value from the previous activation is used
+}
+
+// Neuron in the recurrent network
+type Neuron struct {
+	Type       evo.Neuron     // the type of neuron
+	Activation evo.Activation // activation function for the neuron
+	Bias       float64        // bias value for the neuron
+	Synapses   []Synapse      // incoming connections
+}
+
+// Network of neurons which may contain recurrent connections. Each call to Activate advances the
+// network by one time step and the neurons' values are kept for the next call. Each row of the
+// inputs is treated as an independent sequence. The state is reset if the number of rows changes.
+type Network struct {
+	Neurons []Neuron // neurons ordered by position on the substrate
+	Inputs  []int    // indexes of the input neurons
+	Outputs []int    // indexes of the output neurons
+	state   [][]float64
+}
+
+// Activate advances the network one step using the incoming matrix of values
+func (net *Network) Activate(inputs evo.Matrix) (outputs evo.Matrix, err error) {
+
+	// Check the inputs
+	r, c := inputs.Dims()
+	if c != len(net.Inputs) {
+		err = ErrInputsMismatch
+		return
+	}
+
+	// Ensure there is state for each row
+	if len(net.state) != r {
+		net.state = make([][]float64, r)
+		for i := 0; i < r; i++ {
+			net.state[i] = make([]float64, len(net.Neurons))
+		}
+	}
+
+	// Iterate the rows
+	out := mat.NewDense(r, len(net.Outputs), nil)
+	for i := 0; i < r; i++ {
+		prev := net.state[i]
+		next := make([]float64, len(net.Neurons))
+
+		// Set the input values
+		for j, idx := range net.Inputs {
+			next[idx] = inputs.At(i, j)
+		}
+
+		// Activate the remaining neurons in order
+		for j, n := range net.Neurons {
+			if n.Type == evo.Input {
+				continue
+			}
+			x := n.Bias
+			for _, s := range n.Synapses {
+				if s.Recurrent {
+					x += prev[s.Source] * s.Weight
+				} else {
+					x += next[s.Source] * s.Weight
+				}
+			}
+			next[j] = n.Activation.Activate(x)
+		}
+
+		// Record the outputs and save the state
+		for j, idx := range net.Outputs {
+			out.Set(i, j, next[idx])
+		}
+		net.state[i] = next
+	}
+
+	outputs = out
+	return
+}
+
+// Reset clears the network's state
+func (net *Network) Reset() { net.state = nil }
@@ -0,0 +1,192 @@
+package recurrent
+
+import (
+	"math"
+	"testing"
+
+	"github.com/klokare/evo"
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestTranslatorErrors(t *testing.T) {
+	var (
+		in  = evo.Node{Position: evo.Position{Layer: 0.0}, Neuron: evo.Input, Activation: evo.Direct}
+		out = evo.Node{Position: evo.Position{Layer: 1.0}, Neuron: evo.Output, Activation: evo.Direct}
+	)
+	var cases = []struct {
+		Desc     string
+		HasError bool
+		evo.Substrate
+	}{
+		{
+			Desc:      "no inputs",
+			HasError:  true,
+			Substrate: evo.Substrate{Nodes: []evo.Node{out}},
+		},
+		{
+			Desc:      "no outputs",
+			HasError:  true,
+			Substrate: evo.Substrate{Nodes: []evo.Node{in}},
+		},
+		{
+			Desc:     "unknown source",
+			HasError: true,
+			Substrate: evo.Substrate{
+				Nodes: []evo.Node{in, out},
+				Conns: []evo.Conn{{Source: evo.Position{Layer: 0.5}, Target: out.Position, Enabled: true}},
+			},
+		},
+		{
+			Desc:     "unknown target",
+			HasError: true,
+			Substrate: evo.Substrate{
+				Nodes: []evo.Node{in, out},
+				Conns: []evo.Conn{{Source: in.Position, Target: evo.Position{Layer: 0.5}, Enabled: true}},
+			},
+		},
+		{
+			Desc:     "input as target",
+			HasError: true,
+			Substrate: evo.Substrate{
+				Nodes: []evo.Node{in, out},
+				Conns: []evo.Conn{{Source: out.Position, Target: in.Position, Enabled: true}},
+			},
+		},
+		{
+			Desc:     "disabled connection to input is ignored",
+			HasError: false,
+			Substrate: evo.Substrate{
+				Nodes: []evo.Node{in, out},
+				Conns: []evo.Conn{{Source: out.Position, Target: in.Position, Enabled: false}},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Desc, func(t *testing.T) {
+			_, err := Translator{}.Translate(c.Substrate)
+			if c.HasError && err == nil {
+				t.Error("expected error not found")
+			} else if !c.HasError && err != nil {
+				t.Errorf("error not expected: %v", err)
+			}
+		})
+	}
+}
+
+func TestNetworkActivate(t *testing.T) {
+
+	// Positions of the neurons
+	var (
+		in  = evo.Position{Layer: 0.0}
+		hid = evo.Position{Layer: 0.5}
+		out = evo.Position{Layer: 1.0}
+	)
+
+	var cases = []struct {
+		Desc     string
+		Conns    []evo.Conn
+		Inputs   []float64   // one input per step
+		Expected [][]float64 // outputs per step for each row
+	}{
+		{
+			Desc: "feed forward",
+			Conns: []evo.Conn{
+				{Source: in, Target: hid, Weight: 2.0, Enabled: true},
+				{Source: hid, Target: out, Weight: 0.5, Enabled: true},
+			},
+			Inputs:   []float64{1.0, 2.0, 3.0},
+			Expected: [][]float64{{1.0}, {2.0}, {3.0}},
+		},
+		{
+			Desc: "self connection accumulates",
+			Conns: []evo.Conn{
+				{Source: in, Target: hid, Weight: 1.0, Enabled: true},
+				{Source: hid, Target: hid, Weight: 1.0, Enabled: true},
+				{Source: hid, Target: out, Weight: 1.0, Enabled: true},
+			},
+			Inputs:   []float64{1.0, 1.0, 1.0},
+			Expected: [][]float64{{1.0}, {2.0}, {3.0}},
+		},
+		{
+			Desc: "backward connection uses previous step",
+			Conns: []evo.Conn{
+				{Source: in, Target: out, Weight: 1.0, Enabled: true},
+				{Source: out, Target: hid, Weight: 1.0, Enabled: true},
+				{Source: hid, Target: out, Weight: 1.0, Enabled: true},
+			},
+			Inputs:   []float64{1.0, 0.0, 0.0},
+			Expected: [][]float64{{1.0}, {1.0}, {1.0}},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Desc, func(t *testing.T) {
+
+			// Create the network. All neurons use direct activation to make checking easier.
+			sub := evo.Substrate{
+				Nodes: []evo.Node{
+					{Position: out, Neuron: evo.Output, Activation: evo.Direct},
+					{Position: in, Neuron: evo.Input, Activation: evo.Direct},
+					{Position: hid, Neuron: evo.Hidden, Activation: evo.Direct},
+				},
+				Conns: c.Conns,
+			}
+			net, err := Translator{}.Translate(sub)
+			if err != nil {
+				t.Fatalf("error not expected: %v", err)
+			}
+
+			// Activate the network for each step
+			for i, x := range c.Inputs {
+				var outputs evo.Matrix
+				if outputs, err = net.Activate(mat.NewDense(1, 1, []float64{x})); err != nil {
+					t.Fatalf("error not expected: %v", err)
+				}
+				if math.Abs(outputs.At(0, 0)-c.Expected[i][0]) > 1e-9 {
+					t.Errorf("incorrect output at step %d: expected %f, actual %f", i, c.Expected[i][0], outputs.At(0, 0))
+				}
+			}
+		})
+	}
+}
+
+func TestNetworkStateAndReset(t *testing.T) {
+
+	// Create a network with a self connection on the output
+	sub := evo.Substrate{
+		Nodes: []evo.Node{
+			{Position: evo.Position{Layer: 0.0}, Neuron: evo.Input, Activation: evo.Direct},
+			{Position: evo.Position{Layer: 1.0}, Neuron: evo.Output, Activation: evo.Direct},
+		},
+		Conns: []evo.Conn{
+			{Source: evo.Position{Layer: 0.0}, Target: evo.Position{Layer: 1.0}, Weight: 1.0, Enabled: true},
+			{Source: evo.Position{Layer: 1.0}, Target: evo.Position{Layer: 1.0}, Weight: 1.0, Enabled: true},
+		},
+	}
+	x, err := Translator{}.Translate(sub)
+	if err != nil {
+		t.Fatalf("error not expected: %v", err)
+	}
+	net := x.(*Network)
+
+	// Rows are independent sequences
+	inputs := mat.NewDense(2, 1, []float64{1.0, 2.0})
+	net.Activate(inputs)
+	outputs, _ := net.Activate(inputs)
+	if outputs.At(0, 0) != 2.0 || outputs.At(1, 0) != 4.0 {
+		t.Errorf("incorrect outputs: expected [2, 4], actual [%f, %f]", outputs.At(0, 0), outputs.At(1, 0))
+	}
+
+	// Resetting clears the memory
+	net.Reset()
+	outputs, _ = net.Activate(inputs)
+	if outputs.At(0, 0) != 1.0 || outputs.At(1, 0) != 2.0 {
+		t.Errorf("incorrect outputs after reset: expected [1, 2], actual [%f, %f]", outputs.At(0, 0), outputs.At(1, 0))
+	}
+
+	// Mismatched inputs
+	if _, err = net.Activate(mat.NewDense(1, 2, nil)); err == nil {
+		t.Error("expected error not found")
+	}
+}
@@ -0,0 +1,58 @@
+package recurrent
+
+import (
+	"github.com/klokare/evo"
+	"github.com/klokare/evo/internal/layout"
+)
+
+// Known errors
+var (
+	ErrNoSensors         = layout.ErrNoSensors
+	ErrNoOutputs         = layout.ErrNoOutputs
+	ErrUnknownConnSource = layout.ErrUnknownConnSource
+	ErrUnknownConnTarget = layout.ErrUnknownConnTarget
+	ErrInputAsTarget     = layout.ErrInputAsTarget
+)
+
+// Translator transforms substrates into recurrent networks. Unlike the forward translator,
+// connections may point backward (to an earlier or the same layer) or to the source neuron itself.
+type Translator struct {
+	DisableSortCheck bool
+}
+
+// Translate the substrate into a network
+func (t Translator) Translate(sub evo.Substrate) (net evo.Network, err error) {
+
+	// Index the neurons and the enabled connections
+	var l layout.Layout
+	if l, err = layout.New(sub, t.DisableSortCheck); err != nil {
+		return
+	}
+
+	// Create the neurons
+	n := &Network{
+		Neurons: make([]Neuron, len(l.Nodes)),
+		Inputs:  l.Inputs,
+		Outputs: l.Outputs,
+	}
+	for i, node := range l.Nodes {
+		n.Neurons[i] = Neuron{
+			Type:       node.Neuron,
+			Activation: node.Activation,
+			Bias:       node.Bias,
+		}
+	}
+
+	// Add the synapses to their target neurons. Connections from an earlier layer use the current
+	// step's values and all others, including self-connections, use the previous step's values.
+	for _, s := range l.Synapses {
+		n.Neurons[s.Target].Synapses = append(n.Neurons[s.Target].Synapses, Synapse{
+			Source:    s.Source,
+			Weight:    s.Weight,
+			Recurrent: s.Conn.Source.Layer >= s.Conn.Target.Layer,
+		})
+	}
+
+	// Return the new network
+	return n, nil
+}
@@ -4,11 +4,12 @@ import "fmt"
 
 // A Node describes a neuron in the network
 type Node struct {
-	Position           // The location of the node on the substrate
-	Neuron             // Then neuron type
-	Activation         // The activation type
-	Bias       float64 // Bias value for the neuron
-	Locked     bool    // Locked nodes cannot be removed
+	Position             // The location of the node on the substrate
+	Neuron               // Then neuron type
+	Activation           // The activation type
+	Bias         float64 // Bias value for the neuron
+	TimeConstant float64 // Time constant of the neuron in a continuous-time network
+	Locked       bool    // Locked nodes cannot be removed
 }
 
 // String reutnrs the description of the node
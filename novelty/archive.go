@@ -0,0 +1,24 @@
+package novelty
+
+// Archive holds the behaviors of past genomes deemed novel when they were evaluated. New genomes are
+// compared against the archive so that search is pushed away from previously visited areas.
+type Archive struct {
+	MaxSize   int         // Maximum number of behaviors to keep. The oldest are removed first. Zero means no limit.
+	Behaviors [][]float64 // The archived behaviors in order of insertion
+}
+
+// Add the behaviors to the archive, removing the oldest if the archive grows too large.
+func (a *Archive) Add(behaviors ...[]float64) {
+	for _, b := range behaviors {
+		x := make([]float64, len(b)) // keep a copy so the caller cannot change the archive
+		copy(x, b)
+		a.Behaviors = append(a.Behaviors, x)
+	}
+	if a.MaxSize > 0 && len(a.Behaviors) > a.MaxSize {
+		n := len(a.Behaviors) - a.MaxSize
+		a.Behaviors = append(a.Behaviors[:0], a.Behaviors[n:]...)
+	}
+}
+
+// Len returns the number of behaviors in the archive
+func (a *Archive) Len() int { return len(a.Behaviors) }
@@ -0,0 +1,127 @@
+package novelty
+
+import (
+	"encoding/json"
+	"sort"
+
+	"github.com/klokare/evo"
+)
+
+// Policy decides which of the scored behaviors will be added to the archive
+type Policy interface {
+	Insert(scores []float64) (idxs []int)
+}
+
+// RandomPolicy is a policy whose decisions draw from a random stream. The scorer provides the
+// stream so that seeded runs are reproducible.
+type RandomPolicy interface {
+	Policy
+	InsertWith(rng evo.Random, scores []float64) (idxs []int)
+}
+
+// Threshold adds behaviors whose novelty exceeds a dynamic threshold. The threshold is raised when
+// too many behaviors are added in a single generation and lowered after a number of generations in
+// which none are added, as described by Lehman and Stanley.
+type Threshold struct {
+	Threshold    float64 // The current novelty threshold
+	MinThreshold float64 // The threshold will not be lowered below this value
+	Modifier     float64 // Fraction by which the threshold is raised or lowered
+	RaiseAt      int     // Number of insertions in a single generation that raises the threshold. Zero disables.
+	LowerAfter   int     // Number of generations without insertion that lowers the threshold. Zero disables.
+
+	// Internal state
+	stale int // number of generations since the last insertion
+}
+
+// Insert returns the indexes of the scores above the threshold and adjusts the threshold.
+func (t *Threshold) Insert(scores []float64) (idxs []int) {
+
+	// Identify the novel behaviors
+	for i, x := range scores {
+		if x > t.Threshold {
+			idxs = append(idxs, i)
+		}
+	}
+
+	// Adjust the threshold
+	if len(idxs) == 0 {
+		t.stale++
+		if t.LowerAfter > 0 && t.stale >= t.LowerAfter {
+			t.Threshold *= 1.0 - t.Modifier
+			if t.Threshold < t.MinThreshold {
+				t.Threshold = t.MinThreshold
+			}
+			t.stale = 0
+		}
+	} else {
+		t.stale = 0
+		if t.RaiseAt > 0 && len(idxs) >= t.RaiseAt {
+			t.Threshold *= 1.0 + t.Modifier
+		}
+	}
+	return
+}
+
+// thresholdState is the serialisable form of the threshold's internal state
+type thresholdState struct {
+	Threshold float64
+	Stale     int
+}
+
+// State returns the policy's internal state, including the adjusted threshold
+func (t *Threshold) State() ([]byte, error) {
+	return json.Marshal(thresholdState{Threshold: t.Threshold, Stale: t.stale})
+}
+
+// Restore the policy's internal state
+func (t *Threshold) Restore(b []byte) (err error) {
+	var x thresholdState
+	if err = json.Unmarshal(b, &x); err != nil {
+		return
+	}
+	t.Threshold = x.Threshold
+	t.stale = x.Stale
+	return
+}
+
+// Random adds each behavior with a fixed probability regardless of its novelty
+type Random struct {
+	Probability float64
+}
+
+// Insert returns the indexes of the randomly chosen scores
+func (r Random) Insert(scores []float64) (idxs []int) {
+	return r.InsertWith(evo.NewRandom(), scores)
+}
+
+// InsertWith returns the indexes of the scores chosen by drawing from the random stream
+func (r Random) InsertWith(rng evo.Random, scores []float64) (idxs []int) {
+	for i := range scores {
+		if rng.Float64() < r.Probability {
+			idxs = append(idxs, i)
+		}
+	}
+	return
+}
+
+// Best adds the most novel behaviors of each generation
+type Best struct {
+	N int // Number of behaviors to add each generation
+}
+
+// Insert returns the indexes of the N highest scores
+func (b Best) Insert(scores []float64) (idxs []int) {
+	n := b.N
+	if n > len(scores) {
+		n = len(scores)
+	}
+	if n <= 0 {
+		return
+	}
+	idxs = make([]int, len(scores))
+	for i := range idxs {
+		idxs[i] = i
+	}
+	sort.SliceStable(idxs, func(i, j int) bool { return scores[idxs[i]] > scores[idxs[j]] })
+	return idxs[:n]
+}
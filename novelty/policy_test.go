@@ -0,0 +1,95 @@
+package novelty
+
+import (
+	"math"
+	"testing"
+)
+
+func TestThresholdInsert(t *testing.T) {
+
+	// Create a policy that raises after 2 insertions and lowers after 2 stale generations
+	p := &Threshold{Threshold: 1.0, MinThreshold: 0.85, Modifier: 0.1, RaiseAt: 2, LowerAfter: 2}
+
+	// Two novel behaviors raises the threshold
+	idxs := p.Insert([]float64{0.5, 1.5, 2.0})
+	if len(idxs) != 2 || idxs[0] != 1 || idxs[1] != 2 {
+		t.Errorf("incorrect indexes: expected [1 2], actual %v", idxs)
+	}
+	if math.Abs(p.Threshold-1.1) > 1e-9 {
+		t.Errorf("incorrect threshold after raising: expected 1.1, actual %f", p.Threshold)
+	}
+
+	// One stale generation does not change the threshold but two does
+	p.Insert([]float64{0.5})
+	if math.Abs(p.Threshold-1.1) > 1e-9 {
+		t.Errorf("incorrect threshold after 1 stale generation: expected 1.1, actual %f", p.Threshold)
+	}
+	p.Insert([]float64{0.5})
+	if math.Abs(p.Threshold-1.1*0.9) > 1e-9 {
+		t.Errorf("incorrect threshold after 2 stale generations: expected %f, actual %f", 1.1*0.9, p.Threshold)
+	}
+
+	// The threshold is not lowered below the minimum
+	for i := 0; i < 4; i++ {
+		p.Insert([]float64{0.5})
+	}
+	if p.Threshold != 0.85 {
+		t.Errorf("incorrect threshold after lowering to minimum: expected 0.85, actual %f", p.Threshold)
+	}
+}
+
+func TestRandomInsert(t *testing.T) {
+	scores := []float64{1, 2, 3, 4}
+	if idxs := (Random{Probability: 0.0}).Insert(scores); len(idxs) != 0 {
+		t.Errorf("incorrect number of insertions: expected 0, actual %d", len(idxs))
+	}
+	if idxs := (Random{Probability: 1.0}).Insert(scores); len(idxs) != len(scores) {
+		t.Errorf("incorrect number of insertions: expected %d, actual %d", len(scores), len(idxs))
+	}
+}
+
+func TestBestInsert(t *testing.T) {
+	var cases = []struct {
+		Desc     string
+		N        int
+		Scores   []float64
+		Expected []int
+	}{
+		{Desc: "none", N: 0, Scores: []float64{1, 2}},
+		{Desc: "more than available", N: 3, Scores: []float64{1, 2}, Expected: []int{1, 0}},
+		{Desc: "top scores", N: 2, Scores: []float64{3, 1, 4, 2}, Expected: []int{2, 0}},
+	}
+	for _, c := range cases {
+		t.Run(c.Desc, func(t *testing.T) {
+			idxs := Best{N: c.N}.Insert(c.Scores)
+			if len(idxs) != len(c.Expected) {
+				t.Fatalf("incorrect number of insertions: expected %d, actual %d", len(c.Expected), len(idxs))
+			}
+			for i, x := range c.Expected {
+				if idxs[i] != x {
+					t.Errorf("incorrect index at %d: expected %d, actual %d", i, x, idxs[i])
+				}
+			}
+		})
+	}
+}
+
+func TestArchiveAdd(t *testing.T) {
+	a := &Archive{MaxSize: 2}
+	b := []float64{1.0}
+	a.Add(b, []float64{2.0}, []float64{3.0})
+	if a.Len() != 2 {
+		t.Fatalf("incorrect archive size: expected 2, actual %d", a.Len())
+	}
+	if a.Behaviors[0][0] != 2.0 || a.Behaviors[1][0] != 3.0 {
+		t.Errorf("oldest behaviors should be removed first: actual %v", a.Behaviors)
+	}
+
+	// The archive keeps copies
+	a = new(Archive)
+	a.Add(b)
+	b[0] = 5.0
+	if a.Behaviors[0][0] != 1.0 {
+		t.Errorf("archive should not be changed by caller: expected 1.0, actual %f", a.Behaviors[0][0])
+	}
+}
@@ -0,0 +1,219 @@
+package novelty
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"sort"
+
+	"github.com/klokare/evo"
+)
+
+// Known errors
+var (
+	ErrInvalidK        = errors.New("novelty scorer requires k greater than zero")
+	ErrInvalidBehavior = errors.New("behavior must be a float64 or a slice of float64")
+	ErrMissingArchive  = errors.New("novelty scorer requires an archive")
+)
+
+// Key of the random stream used by random insertion policies, distinct from those used by evo
+const policyStream int64 = 0x6e6f76 // "nov"
+
+// Scorer calculates the novelty of each genome's behavior as the mean distance to its k-nearest
+// neighbours amongst the current population and the archive. Call ScoreResults on the searcher's
+// results, as the NEAT experiment does, so that novelty is set before the Evaluated event is
+// published and every listener sees the same values.
+type Scorer struct {
+	K       int      // Number of nearest neighbours to consider
+	Archive *Archive // Archive of past novel behaviors
+	Policy           // Decides which behaviors are added to the archive. If nil, nothing is archived.
+
+	// Internal state
+	scored int64 // number of times the scorer has been called, keying the policy's random stream
+}
+
+// ScoreResults sets the novelty of the results and updates the archive. Results without a behavior
+// are ignored and receive a novelty of zero.
+func (s *Scorer) ScoreResults(results []evo.Result) (err error) {
+	bs := make([]interface{}, len(results))
+	for i, r := range results {
+		bs[i] = r.Behavior
+	}
+	var scores []float64
+	if scores, err = s.score(bs); err != nil {
+		return
+	}
+	for i := range results {
+		results[i].Novelty = scores[i]
+	}
+	return
+}
+
+// Score sets the novelty of the population's genomes and updates the archive. Genomes without a
+// behavior are ignored and receive a novelty of zero. The genomes are updated in place so Score
+// must not be subscribed alongside other callbacks that read novelty; prefer ScoreResults.
+func (s *Scorer) Score(pop evo.Population) (err error) {
+	bs := make([]interface{}, len(pop.Genomes))
+	for i, g := range pop.Genomes {
+		bs[i] = g.Behavior
+	}
+	var scores []float64
+	if scores, err = s.score(bs); err != nil {
+		return
+	}
+	for i := range pop.Genomes {
+		pop.Genomes[i].Novelty = scores[i]
+	}
+	return
+}
+
+// Returns the novelty of each behavior, zero for those that are nil, and updates the archive
+func (s *Scorer) score(xs []interface{}) (scores []float64, err error) {
+
+	// Check for errors
+	if s.K < 1 {
+		return nil, ErrInvalidK
+	} else if s.Archive == nil {
+		return nil, ErrMissingArchive
+	}
+
+	// Collect the behaviors
+	idxs := make([]int, 0, len(xs))
+	behaviors := make([][]float64, 0, len(xs))
+	for i, x := range xs {
+		if x == nil {
+			continue
+		}
+		var b []float64
+		if b, err = Behavior(x); err != nil {
+			return
+		}
+		idxs = append(idxs, i)
+		behaviors = append(behaviors, b)
+	}
+
+	// Score each behavior against the others and the archive
+	scores = make([]float64, len(xs))
+	novel := make([]float64, len(behaviors))
+	others := make([][]float64, 0, len(behaviors)+s.Archive.Len())
+	for i, b := range behaviors {
+		others = others[:0]
+		others = append(others, behaviors[:i]...)
+		others = append(others, behaviors[i+1:]...)
+		others = append(others, s.Archive.Behaviors...)
+		novel[i] = Novelty(b, others, s.K)
+		scores[idxs[i]] = novel[i]
+	}
+
+	// Update the archive
+	if s.Policy != nil {
+		var ins []int
+		if px, ok := s.Policy.(RandomPolicy); ok {
+			ins = px.InsertWith(evo.NewStream(policyStream, s.scored), novel)
+		} else {
+			ins = s.Policy.Insert(novel)
+		}
+		for _, i := range ins {
+			s.Archive.Add(behaviors[i])
+		}
+	}
+	s.scored++
+	return
+}
+
+// scorerState is the serialisable form of the scorer's internal state
+type scorerState struct {
+	Archive [][]float64
+	Scored  int64
+	Policy  json.RawMessage `json:",omitempty"`
+}
+
+// State returns the archived behaviors, the number of calls and the policy's internal state, if any
+func (s *Scorer) State() (b []byte, err error) {
+	x := scorerState{Scored: s.scored}
+	if s.Archive != nil {
+		x.Archive = s.Archive.Behaviors
+	}
+	if px, ok := s.Policy.(evo.Stateful); ok {
+		if x.Policy, err = px.State(); err != nil {
+			return
+		}
+	}
+	return json.Marshal(x)
+}
+
+// Restore the archived behaviors and the policy's internal state
+func (s *Scorer) Restore(b []byte) (err error) {
+	var x scorerState
+	if err = json.Unmarshal(b, &x); err != nil {
+		return
+	}
+	if s.Archive == nil {
+		s.Archive = new(Archive)
+	}
+	s.Archive.Behaviors = x.Archive
+	s.scored = x.Scored
+	if px, ok := s.Policy.(evo.Stateful); ok && len(x.Policy) > 0 {
+		err = px.Restore(x.Policy)
+	}
+	return
+}
+
+// Novelty returns the mean Euclidean distance between the behavior and its k-nearest neighbours
+// amongst the others. If there are fewer than k others, all are used.
+func Novelty(behavior []float64, others [][]float64, k int) float64 {
+	if len(others) == 0 || k < 1 {
+		return 0.0
+	}
+	ds := make([]float64, len(others))
+	for i, o := range others {
+		ds[i] = Distance(behavior, o)
+	}
+	sort.Float64s(ds)
+	if k > len(ds) {
+		k = len(ds)
+	}
+	var sum float64
+	for _, d := range ds[:k] {
+		sum += d
+	}
+	return sum / float64(k)
+}
+
+// Distance returns the Euclidean distance between two behaviors. Missing values in the shorter
+// behavior are treated as zero.
+func Distance(a, b []float64) float64 {
+	if len(a) < len(b) {
+		a, b = b, a
+	}
+	var sum float64
+	for i, x := range a {
+		if i < len(b) {
+			x -= b[i]
+		}
+		sum += x * x
+	}
+	return math.Sqrt(sum)
+}
+
+// Behavior converts the behavior reported by the evaluator into a slice of float64. Slices of
+// interfaces, like those produced when decoding a checkpoint, are also accepted.
+func Behavior(x interface{}) (b []float64, err error) {
+	switch y := x.(type) {
+	case []float64:
+		return y, nil
+	case float64:
+		return []float64{y}, nil
+	case []interface{}:
+		b = make([]float64, len(y))
+		for i, z := range y {
+			var ok bool
+			if b[i], ok = z.(float64); !ok {
+				return nil, ErrInvalidBehavior
+			}
+		}
+		return
+	default:
+		return nil, ErrInvalidBehavior
+	}
+}
@@ -0,0 +1,225 @@
+package novelty
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/klokare/evo"
+)
+
+func TestNovelty(t *testing.T) {
+	var cases = []struct {
+		Desc     string
+		Behavior []float64
+		Others   [][]float64
+		K        int
+		Expected float64
+	}{
+		{
+			Desc:     "no others",
+			Behavior: []float64{1.0},
+			K:        3,
+			Expected: 0.0,
+		},
+		{
+			Desc:     "fewer others than k",
+			Behavior: []float64{0.0, 0.0},
+			Others:   [][]float64{{3.0, 4.0}, {0.0, 1.0}},
+			K:        3,
+			Expected: 3.0,
+		},
+		{
+			Desc:     "only nearest are used",
+			Behavior: []float64{0.0},
+			Others:   [][]float64{{10.0}, {1.0}, {-2.0}, {100.0}},
+			K:        2,
+			Expected: 1.5,
+		},
+		{
+			Desc:     "unequal lengths",
+			Behavior: []float64{3.0},
+			Others:   [][]float64{{0.0, 4.0}},
+			K:        1,
+			Expected: 5.0,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Desc, func(t *testing.T) {
+			x := Novelty(c.Behavior, c.Others, c.K)
+			if math.Abs(x-c.Expected) > 1e-9 {
+				t.Errorf("incorrect novelty: expected %f, actual %f", c.Expected, x)
+			}
+		})
+	}
+}
+
+func TestBehavior(t *testing.T) {
+	var cases = []struct {
+		Desc     string
+		Actual   interface{}
+		Expected []float64
+		HasError bool
+	}{
+		{Desc: "slice", Actual: []float64{1, 2}, Expected: []float64{1, 2}},
+		{Desc: "single value", Actual: 3.0, Expected: []float64{3}},
+		{Desc: "decoded slice", Actual: []interface{}{1.0, 2.0}, Expected: []float64{1, 2}},
+		{Desc: "decoded slice with invalid value", Actual: []interface{}{1.0, "a"}, HasError: true},
+		{Desc: "unknown type", Actual: "a", HasError: true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Desc, func(t *testing.T) {
+			b, err := Behavior(c.Actual)
+			if c.HasError {
+				if err == nil {
+					t.Error("expected error not found")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("error not expected: %v", err)
+			}
+			if len(b) != len(c.Expected) {
+				t.Fatalf("incorrect length: expected %d, actual %d", len(c.Expected), len(b))
+			}
+			for i, x := range c.Expected {
+				if b[i] != x {
+					t.Errorf("incorrect value at %d: expected %f, actual %f", i, x, b[i])
+				}
+			}
+		})
+	}
+}
+
+func TestScorerScore(t *testing.T) {
+
+	// Check for errors
+	if err := (&Scorer{Archive: new(Archive)}).Score(evo.Population{}); err == nil {
+		t.Error("expected error for invalid k not found")
+	}
+	if err := (&Scorer{K: 1}).Score(evo.Population{}); err == nil {
+		t.Error("expected error for missing archive not found")
+	}
+	bad := evo.Population{Genomes: []evo.Genome{{ID: 1, Behavior: "a"}}}
+	if err := (&Scorer{K: 1, Archive: new(Archive)}).Score(bad); err == nil {
+		t.Error("expected error for invalid behavior not found")
+	}
+
+	// Score a population with an existing archive
+	s := &Scorer{
+		K:       1,
+		Archive: &Archive{Behaviors: [][]float64{{10.0}}},
+		Policy:  Best{N: 1},
+	}
+	pop := evo.Population{
+		Genomes: []evo.Genome{
+			{ID: 1, Behavior: []float64{0.0}},
+			{ID: 2, Behavior: []float64{1.0}},
+			{ID: 3, Behavior: []float64{7.0}},
+			{ID: 4, Novelty: 5.0}, // no behavior
+		},
+	}
+	if err := s.Score(pop); err != nil {
+		t.Fatalf("error not expected: %v", err)
+	}
+
+	// Genomes should be updated in place
+	expected := []float64{1.0, 1.0, 3.0, 0.0}
+	for i, g := range pop.Genomes {
+		if math.Abs(g.Novelty-expected[i]) > 1e-9 {
+			t.Errorf("incorrect novelty for genome %d: expected %f, actual %f", g.ID, expected[i], g.Novelty)
+		}
+	}
+
+	// The most novel behavior should be archived
+	if s.Archive.Len() != 2 {
+		t.Fatalf("incorrect archive size: expected 2, actual %d", s.Archive.Len())
+	}
+	if s.Archive.Behaviors[1][0] != 7.0 {
+		t.Errorf("incorrect archived behavior: expected 7.0, actual %f", s.Archive.Behaviors[1][0])
+	}
+}
+
+func TestScorerScoreResults(t *testing.T) {
+
+	// Score the results with an existing archive
+	s := &Scorer{
+		K:       1,
+		Archive: &Archive{Behaviors: [][]float64{{10.0}}},
+		Policy:  Best{N: 1},
+	}
+	results := []evo.Result{
+		{ID: 1, Behavior: []float64{0.0}},
+		{ID: 2, Behavior: []interface{}{1.0}}, // decoded from a checkpoint
+		{ID: 3, Behavior: 7.0},
+		{ID: 4, Novelty: 5.0}, // no behavior
+	}
+	if err := s.ScoreResults(results); err != nil {
+		t.Fatalf("error not expected: %v", err)
+	}
+
+	// Results should be updated
+	expected := []float64{1.0, 1.0, 3.0, 0.0}
+	for i, r := range results {
+		if math.Abs(r.Novelty-expected[i]) > 1e-9 {
+			t.Errorf("incorrect novelty for result %d: expected %f, actual %f", r.ID, expected[i], r.Novelty)
+		}
+	}
+	if s.Archive.Len() != 2 {
+		t.Errorf("incorrect archive size: expected 2, actual %d", s.Archive.Len())
+	}
+}
+
+func TestScorerRandomPolicyReproducible(t *testing.T) {
+
+	// Score the same results several times with the same seed
+	results := make([]evo.Result, 20)
+	for i := range results {
+		results[i] = evo.Result{ID: int64(i + 1), Behavior: []float64{float64(i)}}
+	}
+	var archives [][][]float64
+	for i := 0; i < 2; i++ {
+		evo.SetSeed(42)
+		s := &Scorer{K: 1, Archive: new(Archive), Policy: Random{Probability: 0.5}}
+		for j := 0; j < 3; j++ {
+			if err := s.ScoreResults(results); err != nil {
+				t.Fatalf("error not expected: %v", err)
+			}
+		}
+		archives = append(archives, s.Archive.Behaviors)
+	}
+
+	// The archives should be identical
+	if !reflect.DeepEqual(archives[0], archives[1]) {
+		t.Errorf("seeded scorers archived different behaviors:\nexpected %v\nactual   %v", archives[0], archives[1])
+	}
+}
+
+func TestScorerStateRestore(t *testing.T) {
+
+	// Create a scorer with state
+	s1 := &Scorer{
+		K:       1,
+		Archive: &Archive{Behaviors: [][]float64{{1.0}, {2.0}}},
+		Policy:  &Threshold{Threshold: 2.0, stale: 3},
+	}
+	b, err := s1.State()
+	if err != nil {
+		t.Fatalf("error not expected: %v", err)
+	}
+
+	// Restore into a new scorer
+	p := &Threshold{Threshold: 1.0}
+	s2 := &Scorer{K: 1, Policy: p}
+	if err = s2.Restore(b); err != nil {
+		t.Fatalf("error not expected: %v", err)
+	}
+	if s2.Archive.Len() != 2 {
+		t.Errorf("incorrect archive size: expected 2, actual %d", s2.Archive.Len())
+	}
+	if p.Threshold != 2.0 || p.stale != 3 {
+		t.Errorf("incorrect policy state: expected 2.0 and 3, actual %f and %d", p.Threshold, p.stale)
+	}
+}
@@ -0,0 +1,98 @@
+// Package nsga provides multi-objective selection using the non-dominated sorting and crowding
+// distance of NSGA-II, as described by Deb et al. Genomes are ranked into Pareto fronts so that
+// objectives such as fitness, novelty and complexity can be traded off without weighting them.
+package nsga
+
+import (
+	"math"
+	"sort"
+)
+
+// Dominates returns true if a is at least as good as b in every objective and better in at least
+// one. Higher values are better.
+func Dominates(a, b []float64) bool {
+	better := false
+	for i := range a {
+		switch {
+		case a[i] < b[i]:
+			return false
+		case a[i] > b[i]:
+			better = true
+		}
+	}
+	return better
+}
+
+// Fronts sorts the objective values into Pareto fronts. The first front contains the indexes of
+// the values dominated by no others, the second those dominated only by values in the first, and
+// so on.
+func Fronts(values [][]float64) (fronts [][]int) {
+
+	// Count how many values dominate each and record which each dominates
+	n := len(values)
+	counts := make([]int, n)
+	dominated := make([][]int, n)
+	var front []int
+	for i := 0; i < n; i++ {
+		for j := i + 1; j < n; j++ {
+			if Dominates(values[i], values[j]) {
+				dominated[i] = append(dominated[i], j)
+				counts[j]++
+			} else if Dominates(values[j], values[i]) {
+				dominated[j] = append(dominated[j], i)
+				counts[i]++
+			}
+		}
+		if counts[i] == 0 {
+			front = append(front, i)
+		}
+	}
+
+	// Peel off each front in turn
+	for len(front) > 0 {
+		fronts = append(fronts, front)
+		var next []int
+		for _, i := range front {
+			for _, j := range dominated[i] {
+				counts[j]--
+				if counts[j] == 0 {
+					next = append(next, j)
+				}
+			}
+		}
+		sort.Ints(next)
+		front = next
+	}
+	return
+}
+
+// Crowding returns the crowding distance of each member of the front, in the same order as the
+// front. Members at the boundary of any objective have an infinite distance so that the extremes
+// of the front are preserved.
+func Crowding(values [][]float64, front []int) (distances []float64) {
+	distances = make([]float64, len(front))
+	if len(front) == 0 {
+		return
+	}
+	idxs := make([]int, len(front))
+	for m := range values[front[0]] {
+
+		// Order the members by this objective
+		for i := range idxs {
+			idxs[i] = i
+		}
+		sort.SliceStable(idxs, func(i, j int) bool { return values[front[idxs[i]]][m] < values[front[idxs[j]]][m] })
+
+		// Accumulate the normalised distance between each member's neighbours
+		lo, hi := values[front[idxs[0]]][m], values[front[idxs[len(idxs)-1]]][m]
+		distances[idxs[0]] = math.Inf(1)
+		distances[idxs[len(idxs)-1]] = math.Inf(1)
+		if hi == lo {
+			continue
+		}
+		for i := 1; i < len(idxs)-1; i++ {
+			distances[idxs[i]] += (values[front[idxs[i+1]]][m] - values[front[idxs[i-1]]][m]) / (hi - lo)
+		}
+	}
+	return
+}
@@ -0,0 +1,97 @@
+package nsga
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestDominates(t *testing.T) {
+	var cases = []struct {
+		Desc     string
+		A, B     []float64
+		Expected bool
+	}{
+		{Desc: "better in all", A: []float64{2, 2}, B: []float64{1, 1}, Expected: true},
+		{Desc: "better in one, equal in other", A: []float64{2, 1}, B: []float64{1, 1}, Expected: true},
+		{Desc: "equal", A: []float64{1, 1}, B: []float64{1, 1}, Expected: false},
+		{Desc: "trade off", A: []float64{2, 0}, B: []float64{1, 1}, Expected: false},
+		{Desc: "worse", A: []float64{0, 0}, B: []float64{1, 1}, Expected: false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Desc, func(t *testing.T) {
+			if x := Dominates(c.A, c.B); x != c.Expected {
+				t.Errorf("incorrect dominance: expected %v, actual %v", c.Expected, x)
+			}
+		})
+	}
+}
+
+func TestFronts(t *testing.T) {
+	var cases = []struct {
+		Desc     string
+		Values   [][]float64
+		Expected [][]int
+	}{
+		{Desc: "no values"},
+		{
+			Desc:     "single front",
+			Values:   [][]float64{{1, 3}, {2, 2}, {3, 1}},
+			Expected: [][]int{{0, 1, 2}},
+		},
+		{
+			Desc:     "multiple fronts",
+			Values:   [][]float64{{1, 1}, {3, 1}, {2, 2}, {1, 3}, {0, 0}, {2, 1}},
+			Expected: [][]int{{1, 2, 3}, {5}, {0}, {4}},
+		},
+		{
+			Desc:     "equal values share a front",
+			Values:   [][]float64{{1, 1}, {1, 1}, {0, 1}},
+			Expected: [][]int{{0, 1}, {2}},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Desc, func(t *testing.T) {
+			x := Fronts(c.Values)
+			if !reflect.DeepEqual(x, c.Expected) {
+				t.Errorf("incorrect fronts: expected %v, actual %v", c.Expected, x)
+			}
+		})
+	}
+}
+
+func TestCrowding(t *testing.T) {
+	inf := math.Inf(1)
+	var cases = []struct {
+		Desc     string
+		Values   [][]float64
+		Front    []int
+		Expected []float64
+	}{
+		{Desc: "empty front", Values: [][]float64{{1, 1}}, Expected: []float64{}},
+		{Desc: "single member", Values: [][]float64{{1, 1}}, Front: []int{0}, Expected: []float64{inf}},
+		{
+			Desc:     "boundaries are infinite",
+			Values:   [][]float64{{0, 4}, {1, 3}, {3, 1}, {4, 0}},
+			Front:    []int{0, 1, 2, 3},
+			Expected: []float64{inf, 1.5, 1.5, inf},
+		},
+		{
+			Desc:     "uneven spacing in subset",
+			Values:   [][]float64{{9, 9}, {0, 4}, {1, 3}, {2, 2}, {4, 0}},
+			Front:    []int{4, 3, 2, 1},
+			Expected: []float64{inf, 1.5, 1.0, inf},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Desc, func(t *testing.T) {
+			x := Crowding(c.Values, c.Front)
+			if !reflect.DeepEqual(x, c.Expected) {
+				t.Errorf("incorrect distances: expected %v, actual %v", c.Expected, x)
+			}
+		})
+	}
+}
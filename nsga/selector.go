@@ -0,0 +1,180 @@
+package nsga
+
+import (
+	"errors"
+	"math"
+	"sort"
+
+	"github.com/klokare/evo"
+	"github.com/klokare/evo/config"
+)
+
+// Known errors
+var (
+	ErrInvalidPopulationSize = errors.New("invalid population size")
+	ErrMissingObjectives     = errors.New("genomes have no objectives")
+	ErrMismatchedObjectives  = errors.New("genomes have different numbers of objectives")
+)
+
+// Selector chooses the continuing genomes and parents by Pareto rank and crowding distance. Each
+// genome's objectives are its Objectives, as returned by the evaluator, followed by the value of
+// each of the selector's comparisons; for example, ByFitness, ByNovelty and ByComplexity trade off
+// fitness, novelty and smaller networks.
+//
+// The best genomes by rank, with ties broken by the larger crowding distance, continue to the next
+// generation. Parents are chosen by tournament using the same ordering. Species are not used so,
+// to replace the selector of an existing experiment, embed both in a new type:
+//
+//	type experiment struct {
+//		*neat.Experiment
+//		*nsga.Selector
+//	}
+type Selector struct {
+	PopulationSize        int              // Size of the next generation
+	Elitism               float64          // Fraction of the population that continues unchanged
+	MutateOnlyProbability float64          // Probability that an offspring has a single parent
+	TournamentSize        int              // Number of genomes in each tournament. If zero, 2 is used.
+	Comparisons           []evo.Comparison // Comparisons used as additional objectives
+}
+
+// Keys used by the NSGA-II selector. NewSelector declares them with the configurer.
+var Keys = []config.Key{
+	{Name: "nsga|selector|population-size", Type: config.Int, Min: 1.0, Max: math.Inf(1), Required: true, Description: "Size of the next generation"},
+	{Name: "nsga|selector|elitism", Type: config.Float64, Min: 0.0, Max: 1.0, Description: "Fraction of the population that continues unchanged"},
+	{Name: "nsga|selector|mutate-only-probability", Type: config.Float64, Min: 0.0, Max: 1.0, Description: "Probability that an offspring has a single parent"},
+	{Name: "nsga|selector|tournament-size", Type: config.Int, Default: 2, Min: 0.0, Max: math.Inf(1), Description: "Number of genomes in each tournament"},
+	{Name: "nsga|selector|comparisons", Type: config.Comparisons, Description: "Comparisons used as additional objectives"},
+}
+
+// NewSelector creates a new NSGA-II selector using the configuration
+func NewSelector(cfg config.Configurer) *Selector {
+	cfg.Declare(Keys...)
+	return &Selector{
+		PopulationSize:        cfg.Int("nsga|selector|population-size"),
+		Elitism:               cfg.Float64("nsga|selector|elitism"),
+		MutateOnlyProbability: cfg.Float64("nsga|selector|mutate-only-probability"),
+		TournamentSize:        cfg.Int("nsga|selector|tournament-size"),
+		Comparisons:           cfg.Comparisons("nsga|selector|comparisons"),
+	}
+}
+
+// Select the genomes to continue and those to become parents
+func (s *Selector) Select(pop evo.Population) (continuing []evo.Genome, parents [][]evo.Genome, err error) {
+	return s.SelectWith(evo.NewRandom(), pop)
+}
+
+// SelectWith selects the genomes to continue and those to become parents, drawing from the random
+// stream for the tournaments
+func (s *Selector) SelectWith(rng evo.Random, pop evo.Population) (continuing []evo.Genome, parents [][]evo.Genome, err error) {
+
+	// Check for errors
+	if s.PopulationSize < 1 {
+		err = ErrInvalidPopulationSize
+		return
+	}
+	if len(pop.Genomes) == 0 {
+		return
+	}
+
+	// Rank the genomes and order them best first
+	genomes := make([]evo.Genome, len(pop.Genomes))
+	copy(genomes, pop.Genomes)
+	var ranks []int
+	var crowding []float64
+	if ranks, crowding, err = s.rank(genomes); err != nil {
+		return
+	}
+	idxs := make([]int, len(genomes))
+	for i := range idxs {
+		idxs[i] = i
+	}
+	better := func(i, j int) bool {
+		if ranks[i] != ranks[j] {
+			return ranks[i] < ranks[j]
+		}
+		if crowding[i] != crowding[j] {
+			return crowding[i] > crowding[j]
+		}
+		return genomes[i].ID < genomes[j].ID // prefer the older genome for a deterministic order
+	}
+	sort.Slice(idxs, func(i, j int) bool { return better(idxs[i], idxs[j]) })
+
+	// Determine continuing
+	n := int(math.Floor(s.Elitism*float64(s.PopulationSize) + 0.5))
+	if n > len(idxs) {
+		n = len(idxs)
+	}
+	continuing = make([]evo.Genome, n)
+	for i := 0; i < n; i++ {
+		continuing[i] = genomes[idxs[i]]
+	}
+
+	// Choose the parents by tournament
+	tgt := s.PopulationSize - n
+	if tgt <= 0 {
+		return
+	}
+	ts := s.TournamentSize
+	if ts < 1 {
+		ts = 2
+	}
+	tournament := func() evo.Genome {
+		w := rng.Intn(len(genomes))
+		for i := 1; i < ts; i++ {
+			if c := rng.Intn(len(genomes)); better(c, w) {
+				w = c
+			}
+		}
+		return genomes[w]
+	}
+	parents = make([][]evo.Genome, tgt)
+	for i := range parents {
+		if rng.Float64() < s.MutateOnlyProbability {
+			parents[i] = []evo.Genome{tournament()}
+		} else {
+			parents[i] = []evo.Genome{tournament(), tournament()}
+		}
+	}
+	return
+}
+
+// Objectives returns the genome's objective values used by the selector
+func (s *Selector) Objectives(g evo.Genome) []float64 {
+	values := make([]float64, 0, len(g.Objectives)+len(s.Comparisons))
+	values = append(values, g.Objectives...)
+	for _, c := range s.Comparisons {
+		values = append(values, c.Value(g))
+	}
+	return values
+}
+
+// Rank the genomes by Pareto front, with zero being the best, and calculate their crowding
+// distance within the front
+func (s *Selector) rank(genomes []evo.Genome) (ranks []int, crowding []float64, err error) {
+
+	// Collect the objectives
+	values := make([][]float64, len(genomes))
+	for i, g := range genomes {
+		values[i] = s.Objectives(g)
+		if len(values[i]) != len(values[0]) {
+			err = ErrMismatchedObjectives
+			return
+		}
+	}
+	if len(values[0]) == 0 {
+		err = ErrMissingObjectives
+		return
+	}
+
+	// Sort into fronts and measure the crowding within each
+	ranks = make([]int, len(genomes))
+	crowding = make([]float64, len(genomes))
+	for r, front := range Fronts(values) {
+		ds := Crowding(values, front)
+		for i, idx := range front {
+			ranks[idx] = r
+			crowding[idx] = ds[i]
+		}
+	}
+	return
+}
@@ -0,0 +1,128 @@
+package nsga
+
+import (
+	"testing"
+
+	"github.com/klokare/evo"
+)
+
+func TestSelectorSelect(t *testing.T) {
+
+	// Genomes 1-3 form the first front, 4 the second, and 5 the third. Genome 2 is the most
+	// crowded member of the first front.
+	pop := evo.Population{Genomes: []evo.Genome{
+		{ID: 5, Objectives: []float64{0, 0}},
+		{ID: 4, Objectives: []float64{1, 1}},
+		{ID: 3, Objectives: []float64{3, 0}},
+		{ID: 2, Objectives: []float64{2, 2}},
+		{ID: 1, Objectives: []float64{0, 3}},
+	}}
+
+	var cases = []struct {
+		Desc       string
+		Elitism    float64
+		MutateOnly float64
+		Continuing []int64
+		Parents    [][]int64
+	}{
+		{Desc: "no elitism", Elitism: 0.0, Parents: [][]int64{{2, 1}, {4, 1}, {1, 1}, {2, 1}, {4, 1}}},
+		{Desc: "first front boundaries", Elitism: 0.4, Continuing: []int64{1, 3}, Parents: [][]int64{{2, 1}, {4, 1}, {1, 1}}},
+		{Desc: "first front", Elitism: 0.6, Continuing: []int64{1, 3, 2}, Parents: [][]int64{{2, 1}, {4, 1}}},
+		{Desc: "first and second fronts", Elitism: 0.8, Continuing: []int64{1, 3, 2, 4}, Parents: [][]int64{{2, 1}}},
+		{Desc: "mutate only", Elitism: 0.2, MutateOnly: 1.0, Continuing: []int64{1}, Parents: [][]int64{{2}, {2}, {5}, {1}}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Desc, func(t *testing.T) {
+			evo.SetSeed(42)
+			s := &Selector{PopulationSize: 5, Elitism: c.Elitism, MutateOnlyProbability: c.MutateOnly, TournamentSize: 2}
+			continuing, parents, err := s.SelectWith(evo.NewStream(1), pop)
+			if err != nil {
+				t.Fatalf("error not expected: %v", err)
+			}
+
+			// Check the continuing genomes
+			if len(continuing) != len(c.Continuing) {
+				t.Fatalf("incorrect number of continuing: expected %d, actual %d", len(c.Continuing), len(continuing))
+			}
+			for i, id := range c.Continuing {
+				if continuing[i].ID != id {
+					t.Errorf("incorrect continuing genome at %d: expected %d, actual %d", i, id, continuing[i].ID)
+				}
+			}
+
+			// The seeded stream decides the tournaments so the parents are known
+			if len(parents) != len(c.Parents) {
+				t.Fatalf("incorrect number of parents: expected %d, actual %d", len(c.Parents), len(parents))
+			}
+			for i, ids := range c.Parents {
+				if len(parents[i]) != len(ids) {
+					t.Errorf("incorrect number of parents in group %d: expected %d, actual %d", i, len(ids), len(parents[i]))
+					continue
+				}
+				for j, id := range ids {
+					if parents[i][j].ID != id {
+						t.Errorf("incorrect parent %d in group %d: expected %d, actual %d", j, i, id, parents[i][j].ID)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestSelectorComparisons(t *testing.T) {
+
+	// Fitness and complexity are traded off so the small, unfit genome survives with the best
+	s := &Selector{PopulationSize: 3, Elitism: 0.67, Comparisons: []evo.Comparison{evo.ByFitness, evo.ByComplexity}}
+	pop := evo.Population{Genomes: []evo.Genome{
+		{ID: 1, Fitness: 1.0, Encoded: evo.Substrate{Nodes: make([]evo.Node, 1)}},
+		{ID: 2, Fitness: 2.0, Encoded: evo.Substrate{Nodes: make([]evo.Node, 5)}},
+		{ID: 3, Fitness: 1.5, Encoded: evo.Substrate{Nodes: make([]evo.Node, 6)}},
+	}}
+	continuing, _, err := s.Select(pop)
+	if err != nil {
+		t.Fatalf("error not expected: %v", err)
+	}
+	if len(continuing) != 2 || continuing[0].ID != 1 || continuing[1].ID != 2 {
+		t.Errorf("incorrect continuing: expected genomes 1 and 2, actual %v", continuing)
+	}
+	if x := s.Objectives(pop.Genomes[1]); len(x) != 2 || x[0] != 2.0 || x[1] != -5.0 {
+		t.Errorf("incorrect objectives: expected [2 -5], actual %v", x)
+	}
+}
+
+func TestSelectorErrors(t *testing.T) {
+	var cases = []struct {
+		Desc     string
+		Selector Selector
+		Genomes  []evo.Genome
+		Expected error
+	}{
+		{
+			Desc:     "invalid population size",
+			Selector: Selector{},
+			Genomes:  []evo.Genome{{ID: 1, Objectives: []float64{1}}},
+			Expected: ErrInvalidPopulationSize,
+		},
+		{
+			Desc:     "missing objectives",
+			Selector: Selector{PopulationSize: 2},
+			Genomes:  []evo.Genome{{ID: 1}, {ID: 2}},
+			Expected: ErrMissingObjectives,
+		},
+		{
+			Desc:     "mismatched objectives",
+			Selector: Selector{PopulationSize: 2},
+			Genomes:  []evo.Genome{{ID: 1, Objectives: []float64{1}}, {ID: 2, Objectives: []float64{1, 2}}},
+			Expected: ErrMismatchedObjectives,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Desc, func(t *testing.T) {
+			if _, _, err := c.Selector.Select(evo.Population{Genomes: c.Genomes}); err != c.Expected {
+				t.Errorf("incorrect error: expected %v, actual %v", c.Expected, err)
+			}
+		})
+	}
+}
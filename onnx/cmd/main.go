@@ -0,0 +1,51 @@
+// Command cmd exports a genome or substrate, saved with the codec package, as an ONNX model.
+//
+//	cmd -output best.onnx best.json
+//	cmd -float -output best.onnx best.bin
+//
+// A genome's decoded substrate is exported.
+package main
+
+import (
+	"flag"
+	"log"
+
+	"github.com/klokare/evo"
+	"github.com/klokare/evo/codec"
+	"github.com/klokare/evo/onnx"
+)
+
+func main() {
+
+	// Parse the flags
+	var (
+		output = flag.String("output", "network.onnx", "filename for the model")
+		name   = flag.String("name", "evo", "name of the model's graph")
+		single = flag.Bool("float", false, "export 32-bit floats rather than 64-bit doubles")
+	)
+	flag.Parse()
+	args := flag.Args()
+	if len(args) != 1 {
+		log.Fatal("you must specify one genome or substrate file")
+	}
+
+	// Load the substrate, trying the file as a genome first
+	var sub evo.Substrate
+	var g evo.Genome
+	err := codec.ReadFile(args[0], &g)
+	switch {
+	case err == nil:
+		sub = g.Decoded
+	case err == codec.ErrMismatchedKind:
+		err = codec.ReadFile(args[0], &sub)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Export the model
+	e := onnx.Exporter{Name: *name, Float: *single}
+	if err = e.WriteFile(*output, sub); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,303 @@
+// Package onnx exports evolved networks as ONNX models so they can be run by inference stacks
+// outside of Go. The model is written with a small protocol buffer encoder and needs no generated
+// code. Each layer of the network becomes a MatMul and Add per source layer followed by its
+// activations. Layers that mix activations are split by Gather, activated and rejoined by Concat.
+// Every built-in evo.Activation is supported, with small subgraphs for those that have no ONNX
+// operator. Custom activations added with evo.RegisterActivation are reported with
+// ErrUnsupportedActivation.
+package onnx
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/klokare/evo"
+	"github.com/klokare/evo/internal/export"
+	"github.com/klokare/evo/network/forward"
+)
+
+// Known errors
+var (
+	ErrUnsupportedActivation = errors.New("activation cannot be exported")
+	ErrEmptyNetwork          = export.ErrEmptyNetwork
+)
+
+// Versions of the exported model
+const (
+	IRVersion    = 7  // ONNX intermediate representation version
+	OpsetVersion = 13 // Version of the default operator set
+)
+
+// Names of the model's input and output
+const (
+	InputName  = "inputs"
+	OutputName = "outputs"
+)
+
+// Exporter writes networks as ONNX models. The model takes a batch of inputs, one row per
+// observation, and returns the outputs in the same order as the network.
+type Exporter struct {
+	Name  string // Name of the graph. Defaults to "evo".
+	Float bool   // Export 32-bit floats rather than the network's 64-bit doubles
+}
+
+// Substrate writes the model of the network translated from the decoded substrate
+func (e Exporter) Substrate(w io.Writer, sub evo.Substrate) error {
+	return export.Substrate(w, e, sub)
+}
+
+// WriteFile writes the model of the network translated from the decoded substrate to the file
+func (e Exporter) WriteFile(filename string, sub evo.Substrate) error {
+	return export.WriteFile(filename, e, sub)
+}
+
+// Network writes the model of the forward network
+func (e Exporter) Network(w io.Writer, net forward.Network) (err error) {
+
+	// Check for errors
+	if len(net.Layers) < 2 {
+		return ErrEmptyNetwork
+	}
+	for i, lay := range net.Layers[1:] {
+		for _, a := range lay.Activations {
+			if !supported(a) {
+				return fmt.Errorf("%w: %v in layer %d", ErrUnsupportedActivation, a, i+1)
+			}
+		}
+	}
+
+	// Build the graph
+	g := &graph{single: e.Float, consts: make(map[string]bool)}
+	elem := int64(typeDouble)
+	if e.Float {
+		elem = typeFloat
+	}
+	ni := len(net.Layers[0].Activations)
+	values := make([]string, len(net.Layers))
+	values[0] = InputName
+	for i := 1; i < len(net.Layers); i++ {
+		values[i] = g.layer(i, net.Layers[i], values, ni)
+	}
+	g.node("output", "Identity", []string{values[len(values)-1]}, OutputName, nil)
+
+	gm := new(message)
+	for _, n := range g.nodes {
+		gm.Message(graphNode, n)
+	}
+	name := e.Name
+	if name == "" {
+		name = "evo"
+	}
+	gm.String(graphName, name)
+	for _, t := range g.inits {
+		gm.Message(graphInitializer, t)
+	}
+	gm.Message(graphInput, valueInfo(InputName, elem, ni))
+	gm.Message(graphOutput, valueInfo(OutputName, elem, len(net.Layers[len(net.Layers)-1].Activations)))
+
+	// Build the model
+	opset := new(message)
+	opset.String(opsetDomain, "")
+	opset.Int(opsetVersion, OpsetVersion)
+
+	m := new(message)
+	m.Int(modelIRVersion, IRVersion)
+	m.String(modelProducerName, "github.com/klokare/evo")
+	m.String(modelProducerVersion, "1")
+	m.Message(modelGraph, gm)
+	m.Message(modelOpsetImport, opset)
+
+	_, err = w.Write(m.b)
+	return
+}
+
+// Returns true if the activation can be exported
+func supported(a evo.Activation) bool {
+	switch a {
+	case evo.Direct, evo.Sigmoid, evo.SteepenedSigmoid, evo.Tanh, evo.InverseAbs, evo.Sin, evo.Gauss, evo.ReLU:
+		return true
+	default:
+		return false
+	}
+}
+
+// A graph collects the nodes and initializers of the model as they are built
+type graph struct {
+	nodes  []*message
+	inits  []*message
+	consts map[string]bool // names of the scalar constants already added
+	single bool
+}
+
+// Adds a node with the operator and integer attributes and returns the name of its output
+func (g *graph) node(name, op string, inputs []string, output string, attrs map[string]int64) string {
+	n := new(message)
+	for _, x := range inputs {
+		n.String(nodeInput, x)
+	}
+	n.String(nodeOutput, output)
+	n.String(nodeName, name)
+	n.String(nodeOpType, op)
+	for k, v := range attrs {
+		a := new(message)
+		a.String(attributeName, k)
+		a.Int(attributeInt, v)
+		a.Int(attributeType, attributeTypeInt)
+		n.Message(nodeAttribute, a)
+	}
+	g.nodes = append(g.nodes, n)
+	return output
+}
+
+// Adds an initializer of floating point values and returns its name
+func (g *graph) floats(name string, dims []int64, xs []float64) string {
+	t := new(message)
+	for _, d := range dims {
+		t.Int(tensorDims, d)
+	}
+	if g.single {
+		t.Int(tensorDataType, typeFloat)
+	} else {
+		t.Int(tensorDataType, typeDouble)
+	}
+	t.String(tensorName, name)
+	t.Bytes(tensorRawData, rawFloats(xs, g.single))
+	g.inits = append(g.inits, t)
+	return name
+}
+
+// Adds an initializer of integer values and returns its name
+func (g *graph) ints(name string, xs []int64) string {
+	t := new(message)
+	t.Int(tensorDims, int64(len(xs)))
+	t.Int(tensorDataType, typeInt64)
+	t.String(tensorName, name)
+	t.Bytes(tensorRawData, rawInts(xs))
+	g.inits = append(g.inits, t)
+	return name
+}
+
+// Returns the name of the scalar constant, adding it if needed
+func (g *graph) scalar(name string, x float64) string {
+	if !g.consts[name] {
+		g.floats(name, nil, []float64{x})
+		g.consts[name] = true
+	}
+	return name
+}
+
+// Adds the nodes of the layer and returns the name of its values. The weights of each source are
+// multiplied and added to the biases in the order the network adds them.
+func (g *graph) layer(i int, lay forward.Layer, values []string, ni int) string {
+	p := fmt.Sprintf("layer%d", i)
+	n := len(lay.Activations)
+	biases := make([]float64, n)
+	copy(biases, lay.Biases)
+	z := g.floats(p+"_biases", []int64{int64(n)}, biases)
+
+	// A layer without sources still needs a row per observation so zero weights from the inputs
+	// provide the batch dimension
+	if len(lay.Sources) == 0 {
+		w := g.floats(p+"_weights", []int64{int64(ni), int64(n)}, make([]float64, ni*n))
+		m := g.node(p+"_matmul", "MatMul", []string{values[0], w}, p+"_product", nil)
+		z = g.node(p+"_add", "Add", []string{z, m}, p+"_sum", nil)
+	}
+	for k, src := range lay.Sources {
+		rows, cols := lay.Weights[k].Dims()
+		xs := make([]float64, 0, rows*cols)
+		for r := 0; r < rows; r++ {
+			for c := 0; c < cols; c++ {
+				xs = append(xs, lay.Weights[k].At(r, c))
+			}
+		}
+		w := g.floats(fmt.Sprintf("%s_weights%d", p, k), []int64{int64(rows), int64(cols)}, xs)
+		m := g.node(fmt.Sprintf("%s_matmul%d", p, k), "MatMul", []string{values[src], w}, fmt.Sprintf("%s_product%d", p, k), nil)
+		z = g.node(fmt.Sprintf("%s_add%d", p, k), "Add", []string{z, m}, fmt.Sprintf("%s_sum%d", p, k), nil)
+	}
+
+	// Group the neurons by activation, in order of first appearance
+	var order []evo.Activation
+	groups := make(map[evo.Activation][]int64)
+	for j, a := range lay.Activations {
+		if _, ok := groups[a]; !ok {
+			order = append(order, a)
+		}
+		groups[a] = append(groups[a], int64(j))
+	}
+	if len(order) == 1 {
+		return g.activate(p, order[0], z)
+	}
+
+	// Activate each group separately and restore the neurons' order
+	parts := make([]string, len(order))
+	perm := make([]int64, n)
+	var pos int64
+	for k, a := range order {
+		q := fmt.Sprintf("%s_%s", p, a)
+		idx := g.ints(q+"_indices", groups[a])
+		x := g.node(q+"_gather", "Gather", []string{z, idx}, q+"_inputs", map[string]int64{"axis": 1})
+		parts[k] = g.activate(q, a, x)
+		for _, j := range groups[a] {
+			perm[j] = pos
+			pos++
+		}
+	}
+	c := g.node(p+"_concat", "Concat", parts, p+"_grouped", map[string]int64{"axis": 1})
+	idx := g.ints(p+"_order", perm)
+	return g.node(p+"_gather", "Gather", []string{c, idx}, p+"_values", map[string]int64{"axis": 1})
+}
+
+// Adds the nodes applying the activation and returns the name of the result. Steepened sigmoid
+// and Gauss, which have no operators, are built from others following evo.Activation.Activate.
+func (g *graph) activate(p string, a evo.Activation, x string) string {
+	switch a {
+	case evo.Direct:
+		return x
+	case evo.Sigmoid:
+		return g.node(p+"_sigmoid", "Sigmoid", []string{x}, p+"_activated", nil)
+	case evo.SteepenedSigmoid:
+		c := g.scalar("steepness", 4.9)
+		y := g.node(p+"_steepen", "Mul", []string{x, c}, p+"_steepened", nil)
+		return g.node(p+"_sigmoid", "Sigmoid", []string{y}, p+"_activated", nil)
+	case evo.Tanh:
+		return g.node(p+"_tanh", "Tanh", []string{x}, p+"_activated", nil)
+	case evo.InverseAbs:
+		return g.node(p+"_softsign", "Softsign", []string{x}, p+"_activated", nil)
+	case evo.Sin:
+		return g.node(p+"_sin", "Sin", []string{x}, p+"_activated", nil)
+	case evo.Gauss:
+		c := g.scalar("gauss_scale", -2.0)
+		y := g.node(p+"_scale", "Mul", []string{c, x}, p+"_scaled", nil)
+		y = g.node(p+"_square", "Mul", []string{y, x}, p+"_squared", nil)
+		return g.node(p+"_exp", "Exp", []string{y}, p+"_activated", nil)
+	case evo.ReLU:
+		return g.node(p+"_relu", "Relu", []string{x}, p+"_activated", nil)
+	default:
+		panic("unknown activation") // checked by Network
+	}
+}
+
+// Returns the value info of a tensor with a batch of rows of n columns
+func valueInfo(name string, elem int64, n int) *message {
+	batch := new(message)
+	batch.String(dimensionParam, "batch")
+	cols := new(message)
+	cols.Int(dimensionValue, int64(n))
+
+	shape := new(message)
+	shape.Message(shapeDim, batch)
+	shape.Message(shapeDim, cols)
+
+	tensor := new(message)
+	tensor.Int(tensorElemType, elem)
+	tensor.Message(tensorShape, shape)
+
+	typ := new(message)
+	typ.Message(typeTensor, tensor)
+
+	v := new(message)
+	v.String(valueName, name)
+	v.Message(valueType, typ)
+	return v
+}
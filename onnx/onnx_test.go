@@ -0,0 +1,478 @@
+package onnx
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/klokare/evo"
+	"github.com/klokare/evo/network/forward"
+	"gonum.org/v1/gonum/mat"
+)
+
+// Returns a substrate whose layers exercise each way the exporter activates them: a hidden layer
+// mixing activations, interleaved so that gathering the groups must restore the neurons' order, a
+// hidden layer of a single activation, and an output layer mixing the remaining activations. The
+// mixed hidden layer also feeds the outputs directly, an input skips both hidden layers, and a
+// disabled connection is left out.
+func newTestSubstrate() evo.Substrate {
+	sub := evo.Substrate{}
+	ins := []evo.Position{{Layer: 0.0, X: 0.0}, {Layer: 0.0, X: 1.0}}
+	for _, p := range ins {
+		sub.Nodes = append(sub.Nodes, evo.Node{Position: p, Neuron: evo.Input, Activation: evo.Direct})
+	}
+
+	mixed := []evo.Activation{evo.Sigmoid, evo.Tanh, evo.Sigmoid, evo.ReLU, evo.SteepenedSigmoid, evo.ReLU}
+	uniform := []evo.Activation{evo.InverseAbs, evo.InverseAbs, evo.InverseAbs}
+	outputs := []evo.Activation{evo.Gauss, evo.Direct, evo.Sin, evo.Gauss}
+
+	layer := func(l float64, n evo.Neuron, as []evo.Activation) []evo.Position {
+		ps := make([]evo.Position, len(as))
+		for k, a := range as {
+			ps[k] = evo.Position{Layer: l, X: float64(k) / float64(len(as)-1)}
+			sub.Nodes = append(sub.Nodes, evo.Node{Position: ps[k], Neuron: n, Activation: a, Bias: 0.2*float64(k) - 0.3})
+		}
+		return ps
+	}
+	connect := func(srcs, tgts []evo.Position, scale float64) {
+		for a, src := range srcs {
+			for b, tgt := range tgts {
+				w := scale * (float64((a+2*b)%5) - 2.0 + 0.25*float64(a))
+				sub.Conns = append(sub.Conns, evo.Conn{Source: src, Target: tgt, Weight: w, Enabled: true})
+			}
+		}
+	}
+
+	hs := layer(0.4, evo.Hidden, mixed)
+	us := layer(0.7, evo.Hidden, uniform)
+	outs := layer(1.0, evo.Output, outputs)
+	connect(ins, hs, 0.8)
+	connect(hs, us, 0.5)
+	connect(us, outs, 0.6)
+	connect(hs[3:5], outs[1:3], -0.4)
+	sub.Conns = append(sub.Conns,
+		evo.Conn{Source: ins[1], Target: outs[2], Weight: 1.5, Enabled: true},
+		evo.Conn{Source: ins[0], Target: outs[0], Weight: 9.0, Enabled: false},
+	)
+	return sub
+}
+
+func TestExporterParity(t *testing.T) {
+
+	sub := newTestSubstrate()
+	x, err := (forward.Translator{}).Translate(sub)
+	if err != nil {
+		t.Fatalf("error not expected translating: %v", err)
+	}
+	net := x.(forward.Network)
+
+	inputs := mat.NewDense(5, 2, []float64{
+		0.0, 0.0,
+		1.0, 1.0,
+		-1.0, 0.25,
+		3.5, -2.25,
+		-100.0, 1e-9,
+	})
+	exp, err := net.Activate(inputs)
+	if err != nil {
+		t.Fatalf("error not expected activating: %v", err)
+	}
+
+	var cases = []struct {
+		Desc      string
+		Float     bool
+		Tolerance float64
+	}{
+		{Desc: "double precision", Tolerance: 1e-12},
+		{Desc: "single precision", Float: true, Tolerance: 1e-4},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Desc, func(t *testing.T) {
+			b := new(bytes.Buffer)
+			if err := (Exporter{Float: c.Float}).Substrate(b, sub); err != nil {
+				t.Fatalf("error not expected exporting: %v", err)
+			}
+			m, err := decodeModel(b.Bytes())
+			if err != nil {
+				t.Fatalf("error not expected decoding: %v", err)
+			}
+			if m.IRVersion != IRVersion || m.Opset != OpsetVersion {
+				t.Errorf("incorrect versions: expected %d and %d, actual %d and %d", IRVersion, OpsetVersion, m.IRVersion, m.Opset)
+			}
+			elem := int64(typeDouble)
+			if c.Float {
+				elem = typeFloat
+			}
+			if m.Elem != elem {
+				t.Errorf("incorrect element type: expected %d, actual %d", elem, m.Elem)
+			}
+
+			// Only the mixed layers are gathered by activation and concatenated
+			var concats []int
+			for _, n := range m.Nodes {
+				if n.Op == "Concat" {
+					concats = append(concats, len(n.Inputs))
+				}
+			}
+			if len(concats) != 2 || concats[0] != 4 || concats[1] != 3 {
+				t.Errorf("incorrect concatenations: expected [4 3] groups, actual %v", concats)
+			}
+
+			r, cols := inputs.Dims()
+			in := tensor{shape: []int{r, cols}, data: inputs.RawMatrix().Data}
+			act, err := m.run(in)
+			if err != nil {
+				t.Fatalf("error not expected running model: %v", err)
+			}
+			er, ec := exp.Dims()
+			if len(act.shape) != 2 || act.shape[0] != er || act.shape[1] != ec {
+				t.Fatalf("incorrect output shape: expected [%d %d], actual %v", er, ec, act.shape)
+			}
+			for i := 0; i < er; i++ {
+				for j := 0; j < ec; j++ {
+					if e, a := exp.At(i, j), act.data[i*ec+j]; math.Abs(e-a) > c.Tolerance {
+						t.Errorf("incorrect output %d of row %d: expected %v, actual %v", j, i, e, a)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestExporterNetwork(t *testing.T) {
+	var cases = []struct {
+		Desc    string
+		Network forward.Network
+		Err     error
+		Ops     []string
+	}{
+		{
+			Desc: "single activation needs no gathering",
+			Network: forward.Network{Layers: []forward.Layer{
+				{Activations: []evo.Activation{evo.Direct, evo.Direct}},
+				{Activations: []evo.Activation{evo.Gauss, evo.Gauss}, Biases: []float64{0.5, 0.0}, Sources: []int{0}, Weights: []*mat.Dense{mat.NewDense(2, 2, []float64{-1.5, 0.0, 0.25, 1.0})}},
+			}},
+			Ops: []string{"MatMul", "Add", "Mul", "Mul", "Exp", "Identity"},
+		},
+		{
+			Desc: "layer without sources",
+			Network: forward.Network{Layers: []forward.Layer{
+				{Activations: []evo.Activation{evo.Direct}},
+				{Activations: []evo.Activation{evo.SteepenedSigmoid}, Biases: []float64{0.5}},
+			}},
+			Ops: []string{"MatMul", "Add", "Mul", "Sigmoid", "Identity"},
+		},
+		{
+			Desc:    "missing output layer",
+			Network: forward.Network{Layers: []forward.Layer{{Activations: []evo.Activation{evo.Direct}}}},
+			Err:     ErrEmptyNetwork,
+		},
+		{
+			Desc: "unknown activation",
+			Network: forward.Network{Layers: []forward.Layer{
+				{Activations: []evo.Activation{evo.Direct}},
+				{Activations: []evo.Activation{evo.Activation(99)}, Biases: []float64{0.0}},
+			}},
+			Err: ErrUnsupportedActivation,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Desc, func(t *testing.T) {
+			b := new(bytes.Buffer)
+			err := Exporter{}.Network(b, c.Network)
+			if !errors.Is(err, c.Err) {
+				t.Fatalf("incorrect error: expected %v, actual %v", c.Err, err)
+			}
+			if err != nil {
+				return
+			}
+			m, err := decodeModel(b.Bytes())
+			if err != nil {
+				t.Fatalf("error not expected decoding: %v", err)
+			}
+			if len(m.Nodes) != len(c.Ops) {
+				t.Fatalf("incorrect number of nodes: expected %d, actual %d", len(c.Ops), len(m.Nodes))
+			}
+			for i, n := range m.Nodes {
+				if n.Op != c.Ops[i] {
+					t.Errorf("incorrect operator of node %d: expected %s, actual %s", i, c.Ops[i], n.Op)
+				}
+			}
+
+			// Check the parity with a batch of observations
+			r := 4
+			ni := len(c.Network.Layers[0].Activations)
+			xs := make([]float64, r*ni)
+			for i := range xs {
+				xs[i] = float64(i)*0.3 - 1.0
+			}
+			exp, _ := c.Network.Activate(mat.NewDense(r, ni, xs))
+			act, err := m.run(tensor{shape: []int{r, ni}, data: xs})
+			if err != nil {
+				t.Fatalf("error not expected running model: %v", err)
+			}
+			_, ec := exp.Dims()
+			for i := 0; i < r; i++ {
+				for j := 0; j < ec; j++ {
+					if e, a := exp.At(i, j), act.data[i*ec+j]; math.Abs(e-a) > 1e-12 {
+						t.Errorf("incorrect output %d of row %d: expected %v, actual %v", j, i, e, a)
+					}
+				}
+			}
+		})
+	}
+}
+
+// The test decodes the model with a minimal protocol buffer reader and runs it with a small
+// interpreter of the operators the exporter uses
+
+type field struct {
+	v uint64
+	b []byte
+}
+
+func decodeMessage(b []byte) (map[int][]field, error) {
+	fs := make(map[int][]field)
+	for len(b) > 0 {
+		k, n := binary.Uvarint(b)
+		if n <= 0 {
+			return nil, errors.New("invalid key")
+		}
+		b = b[n:]
+		num, wire := int(k>>3), k&7
+		var f field
+		switch wire {
+		case 0:
+			if f.v, n = binary.Uvarint(b); n <= 0 {
+				return nil, errors.New("invalid varint")
+			}
+			b = b[n:]
+		case 1:
+			f.v, b = binary.LittleEndian.Uint64(b), b[8:]
+		case 2:
+			l, n := binary.Uvarint(b)
+			if n <= 0 || uint64(len(b)-n) < l {
+				return nil, errors.New("invalid length")
+			}
+			f.b, b = b[n:n+int(l)], b[n+int(l):]
+		case 5:
+			f.v, b = uint64(binary.LittleEndian.Uint32(b)), b[4:]
+		default:
+			return nil, fmt.Errorf("unknown wire type %d", wire)
+		}
+		fs[num] = append(fs[num], f)
+	}
+	return fs, nil
+}
+
+type tensor struct {
+	shape []int
+	data  []float64
+}
+
+type node struct {
+	Inputs []string
+	Output string
+	Op     string
+	Attrs  map[string]int64
+}
+
+type model struct {
+	IRVersion, Opset int64
+	Elem             int64
+	Nodes            []node
+	Inits            map[string]tensor
+	Input, Output    string
+}
+
+func must(fs map[int][]field, err error) map[int][]field {
+	if err != nil {
+		panic(err)
+	}
+	return fs
+}
+
+func decodeModel(b []byte) (m model, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+	mf := must(decodeMessage(b))
+	m.IRVersion = int64(mf[modelIRVersion][0].v)
+	m.Opset = int64(must(decodeMessage(mf[modelOpsetImport][0].b))[opsetVersion][0].v)
+	gf := must(decodeMessage(mf[modelGraph][0].b))
+
+	for _, f := range gf[graphNode] {
+		nf := must(decodeMessage(f.b))
+		n := node{Output: string(nf[nodeOutput][0].b), Op: string(nf[nodeOpType][0].b), Attrs: make(map[string]int64)}
+		for _, x := range nf[nodeInput] {
+			n.Inputs = append(n.Inputs, string(x.b))
+		}
+		for _, x := range nf[nodeAttribute] {
+			af := must(decodeMessage(x.b))
+			n.Attrs[string(af[attributeName][0].b)] = int64(af[attributeInt][0].v)
+		}
+		m.Nodes = append(m.Nodes, n)
+	}
+
+	m.Inits = make(map[string]tensor)
+	for _, f := range gf[graphInitializer] {
+		tf := must(decodeMessage(f.b))
+		var t tensor
+		for _, d := range tf[tensorDims] {
+			t.shape = append(t.shape, int(d.v))
+		}
+		raw := tf[tensorRawData][0].b
+		switch tf[tensorDataType][0].v {
+		case typeDouble:
+			for i := 0; i < len(raw); i += 8 {
+				t.data = append(t.data, math.Float64frombits(binary.LittleEndian.Uint64(raw[i:])))
+			}
+		case typeFloat:
+			for i := 0; i < len(raw); i += 4 {
+				t.data = append(t.data, float64(math.Float32frombits(binary.LittleEndian.Uint32(raw[i:]))))
+			}
+		case typeInt64:
+			for i := 0; i < len(raw); i += 8 {
+				t.data = append(t.data, float64(int64(binary.LittleEndian.Uint64(raw[i:]))))
+			}
+		}
+		m.Inits[string(tf[tensorName][0].b)] = t
+	}
+
+	in := must(decodeMessage(gf[graphInput][0].b))
+	m.Input = string(in[valueName][0].b)
+	tf := must(decodeMessage(must(decodeMessage(in[valueType][0].b))[typeTensor][0].b))
+	m.Elem = int64(tf[tensorElemType][0].v)
+	m.Output = string(must(decodeMessage(gf[graphOutput][0].b))[valueName][0].b)
+	return
+}
+
+// Returns the value of the operand at row i and column j of the broadcast result
+func at(t tensor, i, j int) float64 {
+	switch len(t.shape) {
+	case 0:
+		return t.data[0]
+	case 1:
+		return t.data[j]
+	default:
+		return t.data[i*t.shape[1]+j]
+	}
+}
+
+func (m model) run(in tensor) (tensor, error) {
+	vs := map[string]tensor{m.Input: in}
+	for k, v := range m.Inits {
+		vs[k] = v
+	}
+	for _, n := range m.Nodes {
+		xs := make([]tensor, len(n.Inputs))
+		for i, name := range n.Inputs {
+			var ok bool
+			if xs[i], ok = vs[name]; !ok {
+				return tensor{}, fmt.Errorf("node %s uses unknown value %s", n.Op, name)
+			}
+		}
+		var y tensor
+		unary := func(f func(float64) float64) {
+			y = tensor{shape: xs[0].shape, data: make([]float64, len(xs[0].data))}
+			for i, x := range xs[0].data {
+				y.data[i] = f(x)
+			}
+		}
+		pairwise := func(f func(a, b float64) float64) {
+			a, b := xs[0], xs[1]
+			shape := a.shape
+			if len(b.shape) > len(shape) {
+				shape = b.shape
+			}
+			r, c := 1, 1
+			switch len(shape) {
+			case 1:
+				c = shape[0]
+			case 2:
+				r, c = shape[0], shape[1]
+			}
+			y = tensor{shape: shape, data: make([]float64, r*c)}
+			for i := 0; i < r; i++ {
+				for j := 0; j < c; j++ {
+					y.data[i*c+j] = f(at(a, i, j), at(b, i, j))
+				}
+			}
+		}
+		switch n.Op {
+		case "Identity":
+			unary(func(x float64) float64 { return x })
+		case "Sigmoid":
+			unary(func(x float64) float64 { return 1.0 / (1.0 + math.Exp(-x)) })
+		case "Tanh":
+			unary(math.Tanh)
+		case "Softsign":
+			unary(func(x float64) float64 { return x / (1.0 + math.Abs(x)) })
+		case "Sin":
+			unary(math.Sin)
+		case "Exp":
+			unary(math.Exp)
+		case "Relu":
+			unary(func(x float64) float64 { return math.Max(x, 0) })
+		case "Add":
+			pairwise(func(a, b float64) float64 { return a + b })
+		case "Mul":
+			pairwise(func(a, b float64) float64 { return a * b })
+		case "MatMul":
+			a, b := xs[0], xs[1]
+			r, k, c := a.shape[0], a.shape[1], b.shape[1]
+			if b.shape[0] != k {
+				return tensor{}, fmt.Errorf("mismatched MatMul shapes %v and %v", a.shape, b.shape)
+			}
+			y = tensor{shape: []int{r, c}, data: make([]float64, r*c)}
+			for i := 0; i < r; i++ {
+				for j := 0; j < c; j++ {
+					for l := 0; l < k; l++ {
+						y.data[i*c+j] += a.data[i*k+l] * b.data[l*c+j]
+					}
+				}
+			}
+		case "Gather":
+			if n.Attrs["axis"] != 1 {
+				return tensor{}, errors.New("gather must be on axis 1")
+			}
+			x, idx := xs[0], xs[1]
+			r, c := x.shape[0], len(idx.data)
+			y = tensor{shape: []int{r, c}, data: make([]float64, r*c)}
+			for i := 0; i < r; i++ {
+				for j, l := range idx.data {
+					y.data[i*c+j] = x.data[i*x.shape[1]+int(l)]
+				}
+			}
+		case "Concat":
+			if n.Attrs["axis"] != 1 {
+				return tensor{}, errors.New("concat must be on axis 1")
+			}
+			r, c := xs[0].shape[0], 0
+			for _, x := range xs {
+				c += x.shape[1]
+			}
+			y = tensor{shape: []int{r, c}, data: make([]float64, 0, r*c)}
+			for i := 0; i < r; i++ {
+				for _, x := range xs {
+					y.data = append(y.data, x.data[i*x.shape[1]:(i+1)*x.shape[1]]...)
+				}
+			}
+		default:
+			return tensor{}, fmt.Errorf("unknown operator %s", n.Op)
+		}
+		vs[n.Output] = y
+	}
+	out, ok := vs[m.Output]
+	if !ok {
+		return tensor{}, errors.New("model does not produce its output")
+	}
+	return out, nil
+}
@@ -0,0 +1,123 @@
+package onnx
+
+import (
+	"encoding/binary"
+	"math"
+)
+
+// Wire types of the protocol buffer encoding
+const (
+	wireVarint = 0
+	wireBytes  = 2
+)
+
+// Field numbers of the ONNX messages used by the exporter, from onnx.proto
+const (
+	modelIRVersion       = 1
+	modelProducerName    = 2
+	modelProducerVersion = 3
+	modelGraph           = 7
+	modelOpsetImport     = 8
+
+	opsetDomain  = 1
+	opsetVersion = 2
+
+	graphNode        = 1
+	graphName        = 2
+	graphInitializer = 5
+	graphInput       = 11
+	graphOutput      = 12
+
+	nodeInput     = 1
+	nodeOutput    = 2
+	nodeName      = 3
+	nodeOpType    = 4
+	nodeAttribute = 5
+
+	attributeName    = 1
+	attributeInt     = 3
+	attributeType    = 20
+	attributeTypeInt = 2 // AttributeProto.INT
+
+	tensorDims     = 1
+	tensorDataType = 2
+	tensorName     = 8
+	tensorRawData  = 9
+
+	valueName = 1
+	valueType = 2
+
+	typeTensor     = 1
+	tensorElemType = 1
+	tensorShape    = 2
+	shapeDim       = 1
+	dimensionValue = 1
+	dimensionParam = 2
+)
+
+// Element types of the ONNX tensors
+const (
+	typeFloat  = 1
+	typeInt64  = 7
+	typeDouble = 11
+)
+
+// A message is a protocol buffer message being encoded. Fields are appended in the order they are
+// written.
+type message struct {
+	b []byte
+}
+
+func (m *message) key(field, wire int) {
+	m.uvarint(uint64(field)<<3 | uint64(wire))
+}
+
+func (m *message) uvarint(x uint64) {
+	var buf [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(buf[:], x)
+	m.b = append(m.b, buf[:n]...)
+}
+
+// Int writes a varint field. Negative values take ten bytes, as in the protocol buffer encoding.
+func (m *message) Int(field int, x int64) {
+	m.key(field, wireVarint)
+	m.uvarint(uint64(x))
+}
+
+// Bytes writes a length-delimited field
+func (m *message) Bytes(field int, b []byte) {
+	m.key(field, wireBytes)
+	m.uvarint(uint64(len(b)))
+	m.b = append(m.b, b...)
+}
+
+// String writes a string field
+func (m *message) String(field int, s string) { m.Bytes(field, []byte(s)) }
+
+// Message writes an embedded message field
+func (m *message) Message(field int, x *message) { m.Bytes(field, x.b) }
+
+// Returns the raw little-endian data of the values as 64- or 32-bit floats
+func rawFloats(xs []float64, single bool) []byte {
+	if single {
+		b := make([]byte, 4*len(xs))
+		for i, x := range xs {
+			binary.LittleEndian.PutUint32(b[4*i:], math.Float32bits(float32(x)))
+		}
+		return b
+	}
+	b := make([]byte, 8*len(xs))
+	for i, x := range xs {
+		binary.LittleEndian.PutUint64(b[8*i:], math.Float64bits(x))
+	}
+	return b
+}
+
+// Returns the raw little-endian data of the integers
+func rawInts(xs []int64) []byte {
+	b := make([]byte, 8*len(xs))
+	for i, x := range xs {
+		binary.LittleEndian.PutUint64(b[8*i:], uint64(x))
+	}
+	return b
+}
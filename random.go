@@ -11,6 +11,12 @@ func init() {
 	rand.Seed(time.Now().UnixNano())
 }
 
+// The seed set by SetSeed, if any, from which streams are derived
+var (
+	seed   int64
+	seeded bool
+)
+
 // Random provides the necessary functions used by this package without restricting use to the standard library's Rand
 type Random interface {
 	Float64() float64
@@ -19,12 +25,37 @@ type Random interface {
 	Perm(n int) []int
 }
 
-// SetSeed reinitialises the internal random number generator's seed value. This function is not safe for concurrent calls and really only should be used to control seed values for debugging.
-func SetSeed(seed int64) {
-	rand.Seed(seed)
+// SetSeed reinitialises the internal random number generator's seed value. This function is not safe for concurrent calls and really only should be used to control seed values for debugging or to reproduce a run. Once set, streams returned by NewStream are derived from the seed.
+func SetSeed(s int64) {
+	rand.Seed(s)
+	seed, seeded = s, true
 }
 
 // NewRandom returns a new random number generator
 func NewRandom() Random {
 	return rand.New(rand.NewSource(rand.Int63()))
 }
+
+// NewStream returns a new random number generator for the stream identified by the keys, such as a
+// generation or genome ID. If the seed has been set with SetSeed, the same keys always produce the
+// same sequence regardless of when or on which goroutine the stream is created. Otherwise, the
+// generator is the same as one returned by NewRandom.
+func NewStream(keys ...int64) Random {
+	if !seeded {
+		return NewRandom()
+	}
+	x := mix(uint64(seed))
+	for _, k := range keys {
+		x = mix(x ^ uint64(k))
+	}
+	return rand.New(rand.NewSource(int64(x)))
+}
+
+// Mix the bits of the value using the finaliser of the SplitMix64 generator so that nearby keys
+// produce unrelated seeds
+func mix(x uint64) uint64 {
+	x += 0x9e3779b97f4a7c15
+	x = (x ^ (x >> 30)) * 0xbf58476d1ce4e5b9
+	x = (x ^ (x >> 27)) * 0x94d049bb133111eb
+	return x ^ (x >> 31)
+}
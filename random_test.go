@@ -28,3 +28,24 @@ func TestNewRandom(t *testing.T) {
 	}
 
 }
+
+func TestNewStream(t *testing.T) {
+
+	// Streams with the same keys should produce the same sequence once the seed is set
+	SetSeed(100)
+	x0 := NewStream(1, 2).Float64()
+	NewRandom().Float64() // other draws do not affect the stream
+	x1 := NewStream(1, 2).Float64()
+	if x0 != x1 {
+		t.Errorf("streams with the same keys should produce the same sequence of values, x0 %f and x1 %f", x0, x1)
+	}
+
+	// Streams with different keys or seeds should produce different sequences
+	if x2 := NewStream(2, 1).Float64(); x0 == x2 {
+		t.Errorf("streams with different keys should not produce the same initial value, x0 %f and x2 %f", x0, x2)
+	}
+	SetSeed(101)
+	if x3 := NewStream(1, 2).Float64(); x0 == x3 {
+		t.Errorf("streams with different seeds should not produce the same initial value, x0 %f and x3 %f", x0, x3)
+	}
+}
@@ -0,0 +1,517 @@
+package realtime
+
+import (
+	"context"
+	"errors"
+	"math"
+	"runtime"
+
+	"github.com/klokare/evo"
+	"github.com/klokare/evo/config"
+	"github.com/klokare/evo/internal/workers"
+)
+
+// Known errors
+var (
+	ErrNoParents = errors.New("selector returned no parents for the replacement")
+)
+
+// Key of the random stream from which parent groups are chosen, distinct from those used by evo
+const parentStream int64 = 0x72746e // "rtn"
+
+// Runner evolves the population in real time, after the fashion of rtNEAT. Rather than advancing
+// the population a generation at a time, the runner continuously replaces the worst eligible genomes
+// with new offspring and evaluates them as soon as the previous batch has been searched. Each batch
+// is searched with the experiment's Searcher so that any configured searcher, and novelty scoring
+// such as that of the NEAT experiment, apply just as they do with evo.Run.
+//
+// The population is published to the experiment's subscribers as follows:
+//   - Started, once, after the initial population has been created and speciated
+//   - Advanced, every Interval replacements, before Evaluated
+//   - Evaluated, every Interval replacements, with only those genomes that have been evaluated
+//   - Completed, once, after the context is done and the outstanding evaluations have finished
+//
+// Each time Advanced is published the population's generation is incremented and the evaluated
+// genomes are aged so that helpers which count iterations, such as evo.WithIterations, continue to
+// work. Callbacks receive a copy of the population so any changes they make are not seen by the
+// runner. If the experiment is a checkpointer, a checkpoint of the whole population, including the
+// offspring still being evaluated, is taken at the same time and may be continued with Resume.
+type Runner struct {
+	BatchSize  int            // Number of offspring searched together. If zero, the number of CPUs is used.
+	MinimumAge int            // Number of evaluations a genome must witness before being eligible for replacement
+	Interval   int            // Number of replacements between published events. If zero, the population size is used.
+	Comparison evo.Comparison // Determines the worst genome. If zero, genomes are compared by fitness.
+}
+
+// Keys used by the real-time runner. NewRunner declares them with the configurer.
+var Keys = []config.Key{
+	{Name: "realtime|runner|batch-size", Type: config.Int, Min: 0.0, Max: math.Inf(1), Description: "Number of offspring searched together. The number of CPUs is used if zero."},
+	{Name: "realtime|runner|minimum-age", Type: config.Int, Min: 0.0, Max: math.Inf(1), Description: "Number of evaluations a genome must witness before being eligible for replacement"},
+	{Name: "realtime|runner|interval", Type: config.Int, Min: 0.0, Max: math.Inf(1), Description: "Number of replacements between published events. The population size is used if zero."},
+	{Name: "realtime|runner|comparison", Type: config.Comparison, Description: "Comparison deciding the worst genome"},
+}
+
+// NewRunner creates a new real-time runner using the configuration
+func NewRunner(cfg config.Configurer) *Runner {
+	cfg.Declare(Keys...)
+	return &Runner{
+		BatchSize:  cfg.Int("realtime|runner|batch-size"),
+		MinimumAge: cfg.Int("realtime|runner|minimum-age"),
+		Interval:   cfg.Int("realtime|runner|interval"),
+		Comparison: cfg.Comparison("realtime|runner|comparison"),
+	}
+}
+
+// The outcome of decoding and searching a batch of genomes
+type outcome struct {
+	IDs     []int64
+	Decoded map[int64]evo.Substrate
+	Results []evo.Result
+	Skipped bool
+	err     error
+}
+
+// Run the experiment in the given context with the evaluator. After the initial population has been
+// evaluated, each completed batch ages the evaluated genomes and the runner sends the next batch of
+// offspring to the searcher. Each offspring is created by crossing and mutating a group of parents
+// and put in the place of the worst evaluated genome that has witnessed at least the minimum number
+// of evaluations. The parent groups are chosen at random, without replacement, from those returned
+// by the experiment's Selector. The selector is called with the evaluated genomes when the runner
+// starts replacing, after each interval, and whenever the groups run out, rather than for every
+// replacement.
+func (r Runner) Run(ctx context.Context, exp evo.Experiment, eval evo.Evaluator) (pop evo.Population, err error) {
+
+	// The experiment provides subscribers so subscribe them
+	listeners := evo.Subscribe(exp)
+
+	// Create the initial population
+	if pop, err = exp.Populate(); err != nil {
+		return
+	}
+	if len(pop.Genomes) == 0 {
+		err = evo.ErrNoSeedGenomes
+		return
+	}
+
+	// Ensure every genome belongs to a species
+	if err = exp.Speciate(&pop); err != nil {
+		return
+	}
+
+	// Inform listeners that the population has started
+	if err = listeners.Publish(evo.Started, pop); err != nil {
+		return
+	}
+
+	// Evolve the population
+	return r.run(ctx, exp, eval, listeners, pop, lastID(pop.Genomes))
+}
+
+// Resume continues a real-time run from a checkpoint taken by the runner. The experiment should be
+// configured as it was when the checkpoint was taken. If it is Stateful, its helpers' internal state
+// is restored first. As the checkpoint includes the offspring which were still being evaluated, the
+// whole population is evaluated again before replacements resume. The Started event is published
+// before evolving so listeners can initialise themselves just as they would with Run.
+func (r Runner) Resume(ctx context.Context, exp evo.Experiment, eval evo.Evaluator, cp evo.Checkpoint) (pop evo.Population, err error) {
+
+	// Check for errors
+	if cp.Version != evo.CheckpointVersion {
+		err = evo.ErrUnknownCheckpointVersion
+		return
+	}
+	if len(cp.Population.Genomes) == 0 {
+		err = evo.ErrNoSeedGenomes
+		return
+	}
+
+	// Restore the helpers' internal state
+	if sx, ok := exp.(evo.Stateful); ok && len(cp.State) > 0 {
+		if err = sx.Restore(cp.State); err != nil {
+			return
+		}
+	}
+
+	// The experiment provides subscribers so subscribe them
+	listeners := evo.Subscribe(exp)
+
+	// Restore the population and genome ID sequence
+	pop = cp.Population
+	lastGID := lastID(pop.Genomes)
+	if lastGID < cp.LastGID {
+		lastGID = cp.LastGID
+	}
+
+	// Inform listeners that the population has started
+	if err = listeners.Publish(evo.Started, pop); err != nil {
+		return
+	}
+
+	// Evolve the population
+	return r.run(ctx, exp, eval, listeners, pop, lastGID)
+}
+
+// Returns the highest genome ID
+func lastID(genomes []evo.Genome) (id int64) {
+	for _, g := range genomes {
+		if id < g.ID {
+			id = g.ID
+		}
+	}
+	return
+}
+
+// Evaluate the population and then replace its genomes until the context is done or an error occurs
+func (r Runner) run(ctx context.Context, exp evo.Experiment, eval evo.Evaluator, listeners evo.Listeners, pop evo.Population, lastGID int64) (evo.Population, error) {
+
+	// Apply the defaults
+	if r.BatchSize <= 0 {
+		r.BatchSize = runtime.NumCPU()
+	}
+	if r.Interval <= 0 {
+		r.Interval = len(pop.Genomes)
+	}
+	if r.Comparison == 0 {
+		r.Comparison = evo.ByFitness
+	}
+
+	// Start the searcher. Only one batch is outstanding at a time so neither side blocks.
+	jobs := make(chan []evo.Genome, 1)
+	outcomes := make(chan outcome, 1)
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		search(exp, eval, jobs, outcomes, stop)
+	}()
+
+	// Dispatch the initial population
+	s := &state{
+		Runner:    r,
+		exp:       exp,
+		listeners: listeners,
+		pop:       &pop,
+		lastGID:   lastGID,
+		evaluated: make(map[int64]bool, len(pop.Genomes)),
+		witnessed: make(map[int64]int, len(pop.Genomes)),
+		pending:   make(map[int64]bool, len(pop.Genomes)),
+		rng:       evo.NewStream(parentStream),
+		jobs:      jobs,
+	}
+	for _, g := range pop.Genomes {
+		s.queue(g)
+	}
+	s.dispatch()
+
+	// Process the outcomes until the context is done or an error occurs
+	var err error
+	var stopping bool
+	quit := ctx.Done()
+	for len(s.pending) > 0 {
+		select {
+		case <-quit:
+			quit = nil
+			if !stopping {
+				stopping = true
+				close(stop)
+			}
+		case o := <-outcomes:
+			for _, id := range o.IDs {
+				delete(s.pending, id)
+			}
+			if o.Skipped {
+				continue
+			}
+			if err == nil && o.err != nil {
+				err = o.err
+			}
+			if err == nil {
+				s.record(o)
+				if !stopping {
+					err = s.fill()
+				}
+			}
+			if err != nil && !stopping {
+				stopping = true
+				close(stop)
+			}
+		}
+	}
+	close(jobs)
+	<-done
+
+	// Return the population of evaluated genomes
+	pop = s.snapshot()
+	if err != nil {
+		return pop, err
+	}
+
+	// Inform listeners that the experiment has completed
+	return pop, listeners.Publish(evo.Completed, pop)
+}
+
+// Search decodes each batch of genomes and searches the problem with the phenomes, using the
+// experiment's searcher, until the jobs channel is closed. Once the stop channel is closed, the
+// remaining batches are skipped.
+func search(exp evo.Experiment, eval evo.Evaluator, jobs <-chan []evo.Genome, outcomes chan<- outcome, stop <-chan struct{}) {
+	for batch := range jobs {
+		o := outcome{IDs: make([]int64, len(batch))}
+		for i, g := range batch {
+			o.IDs[i] = g.ID
+		}
+		select {
+		case <-stop:
+			o.Skipped = true
+			outcomes <- o
+			continue
+		default:
+		}
+
+		// Decode the genomes into phenomes
+		phenomes := make([]evo.Phenome, len(batch))
+		tasks := make([]workers.Task, len(batch))
+		for i := range batch {
+			tasks[i] = i
+		}
+		o.err = workers.Do(tasks, func(wt workers.Task) (err error) {
+			i := wt.(int)
+			if phenomes[i], err = evo.Decode(exp, &batch[i]); err == nil && phenomes[i].Network == nil {
+				err = evo.ErrMissingNetworkFromTranslator
+			}
+			return
+		})
+
+		// Search the problem with the phenomes
+		if o.err == nil {
+			o.Decoded = make(map[int64]evo.Substrate, len(batch))
+			for _, g := range batch {
+				o.Decoded[g.ID] = g.Decoded
+			}
+			o.Results, o.err = exp.Search(eval, phenomes)
+		}
+		outcomes <- o
+	}
+}
+
+// State of the run, owned by the runner's main loop
+type state struct {
+	Runner
+	exp       evo.Experiment
+	listeners evo.Listeners
+	pop       *evo.Population
+	lastGID   int64
+	evaluated map[int64]bool // Genomes with at least one evaluation
+	witnessed map[int64]int  // Number of evaluations completed since the genome's own evaluation
+	pending   map[int64]bool // Genomes waiting on an evaluation
+	queued    []evo.Genome   // Genomes waiting to be dispatched in the next batch
+	replaced  int            // Number of replacements made
+	offspring []int64        // Offspring created since the population was last speciated
+	parents   [][]evo.Genome // Parent groups selected but not yet used
+	rng       evo.Random
+	jobs      chan<- []evo.Genome
+}
+
+// Queue the genome for the next batch
+func (s *state) queue(g evo.Genome) {
+	s.pending[g.ID] = true
+	s.queued = append(s.queued, g)
+}
+
+// Dispatch the queued genomes to the searcher as a batch
+func (s *state) dispatch() {
+	if len(s.queued) == 0 {
+		return
+	}
+	s.jobs <- s.queued
+	s.queued = nil
+}
+
+// Record the outcome's results in the population. Every other evaluated genome witnesses each
+// evaluation.
+func (s *state) record(o outcome) {
+	for _, r := range o.Results {
+		for i, g := range s.pop.Genomes {
+			if g.ID == r.ID {
+				g.Decoded = o.Decoded[r.ID]
+				g.Fitness = r.Fitness
+				g.Novelty = r.Novelty
+				g.Objectives = r.Objectives
+				g.Solved = r.Solved
+				g.Behavior = r.Behavior
+				s.pop.Genomes[i] = g
+				s.evaluated[g.ID] = true
+			} else if s.evaluated[g.ID] {
+				s.witnessed[g.ID]++
+			}
+		}
+	}
+}
+
+// Fill the next batch with new offspring and dispatch it. Replacements do not begin until the
+// initial population has been evaluated.
+func (s *state) fill() (err error) {
+	if s.replaced == 0 && len(s.evaluated) < len(s.pop.Genomes) {
+		return
+	}
+	defer s.dispatch() // even after an error so that the queued genomes are accounted for
+	for len(s.pending) < s.BatchSize {
+		var ok bool
+		if ok, err = s.replace(); err != nil || !ok {
+			return
+		}
+	}
+	return
+}
+
+// Replace the worst eligible genome with a new offspring. If there is no eligible genome, false is
+// returned.
+func (s *state) replace() (ok bool, err error) {
+
+	// Identify the worst eligible genome. If nothing is being evaluated, the minimum age is ignored
+	// so that the run cannot stall.
+	worst := -1
+	candidates := make([]evo.Genome, 0, len(s.pop.Genomes))
+	for _, g := range s.pop.Genomes {
+		if s.evaluated[g.ID] && !s.pending[g.ID] {
+			candidates = append(candidates, g)
+		}
+	}
+	eligible := make([]evo.Genome, 0, len(candidates))
+	for _, g := range candidates {
+		if s.witnessed[g.ID] >= s.MinimumAge || len(s.pending) == 0 {
+			eligible = append(eligible, g)
+		}
+	}
+	if len(eligible) == 0 {
+		return
+	}
+	evo.SortBy(eligible, evo.BySolved, s.Comparison, evo.ByComplexity, evo.ByAge)
+	for i, g := range s.pop.Genomes {
+		if g.ID == eligible[0].ID {
+			worst = i
+			break
+		}
+	}
+
+	// Take the next group of parents
+	var pgrp []evo.Genome
+	if pgrp, err = s.next(candidates); err != nil {
+		return
+	}
+
+	// Create the offspring. Until it is speciated at the end of the interval, the offspring belongs
+	// to its first parent's species.
+	var child evo.Genome
+	if child, err = s.exp.Cross(pgrp...); err != nil {
+		return
+	}
+	s.lastGID++
+	child.ID = s.lastGID
+	child.Age = 0
+	child.Species = pgrp[0].Species
+	evo.SetLineage(&child, s.pop.Generation, pgrp...)
+	if err = s.exp.Mutate(&child); err != nil {
+		return
+	}
+
+	// Replace the worst genome
+	delete(s.evaluated, s.pop.Genomes[worst].ID)
+	delete(s.witnessed, s.pop.Genomes[worst].ID)
+	s.pop.Genomes[worst] = child
+	s.offspring = append(s.offspring, child.ID)
+
+	// Evaluate the child with the next batch
+	s.queue(child)
+	ok = true
+
+	// Publish the population at each interval
+	s.replaced++
+	if s.replaced%s.Interval == 0 {
+		err = s.advance()
+	}
+	return
+}
+
+// Next returns a group of parents chosen at random from those selected. If none remain, parents are
+// selected from the candidates. If the selector offers no parents, each continuing genome becomes a
+// group of its own and is cloned instead.
+func (s *state) next(candidates []evo.Genome) (pgrp []evo.Genome, err error) {
+	if len(s.parents) == 0 {
+		var continuing []evo.Genome
+		if continuing, s.parents, err = s.exp.Select(evo.Population{Generation: s.pop.Generation, Genomes: candidates}); err != nil {
+			return
+		}
+		if len(s.parents) == 0 {
+			for _, g := range continuing {
+				s.parents = append(s.parents, []evo.Genome{g})
+			}
+		}
+		if len(s.parents) == 0 {
+			err = ErrNoParents
+			return
+		}
+	}
+	i := s.rng.Intn(len(s.parents))
+	pgrp = s.parents[i]
+	s.parents[i] = s.parents[len(s.parents)-1]
+	s.parents = s.parents[:len(s.parents)-1]
+	return
+}
+
+// Advance the generation, age the evaluated genomes, speciate the offspring, and inform the
+// listeners. Age is kept in generations and offspring are speciated together, as they are with
+// evo.Run, so that selectors which decay by age and speciators which adjust their threshold with
+// each call behave the same.
+func (s *state) advance() (err error) {
+	s.pop.Generation++
+	born := make(map[int64]bool, len(s.offspring))
+	for _, id := range s.offspring {
+		born[id] = true
+	}
+	s.offspring = s.offspring[:0]
+	s.parents = nil // select again from the advanced population
+	for i, g := range s.pop.Genomes {
+		if s.evaluated[g.ID] {
+			s.pop.Genomes[i].Age++
+		}
+		if born[g.ID] {
+			s.pop.Genomes[i].Species = 0
+		}
+	}
+	if err = s.exp.Speciate(s.pop); err != nil {
+		return
+	}
+	pop := s.snapshot()
+	if err = s.listeners.Publish(evo.Advanced, pop); err != nil {
+		return
+	}
+	if err = s.listeners.Publish(evo.Evaluated, pop); err != nil {
+		return
+	}
+
+	// Take a checkpoint, if the experiment wants one. The checkpoint holds the whole population,
+	// including the offspring still being evaluated, so that none are lost when resuming.
+	if cx, ok := s.exp.(evo.Checkpointer); ok {
+		all := evo.Population{Generation: s.pop.Generation, Genomes: make([]evo.Genome, len(s.pop.Genomes))}
+		copy(all.Genomes, s.pop.Genomes)
+		var cp evo.Checkpoint
+		if cp, err = evo.NewCheckpoint(s.exp, all, s.lastGID); err != nil {
+			return
+		}
+		err = cx.Checkpoint(cp)
+	}
+	return
+}
+
+// Snapshot returns a copy of the population containing only the evaluated genomes
+func (s *state) snapshot() evo.Population {
+	pop := evo.Population{
+		Generation: s.pop.Generation,
+		Genomes:    make([]evo.Genome, 0, len(s.pop.Genomes)),
+	}
+	for _, g := range s.pop.Genomes {
+		if s.evaluated[g.ID] {
+			pop.Genomes = append(pop.Genomes, g)
+		}
+	}
+	return pop
+}
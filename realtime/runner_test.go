@@ -0,0 +1,350 @@
+package realtime
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/klokare/evo"
+	"github.com/klokare/evo/config"
+	"github.com/klokare/evo/config/source"
+)
+
+func TestRunnerRun(t *testing.T) {
+
+	// Run the experiment for 3 intervals
+	exp := &mockExperiment{PopSize: 10}
+	ctx, fn, cb := evo.WithIterations(context.Background(), 3)
+	defer fn()
+	exp.subscriptions = []evo.Subscription{
+		{Event: evo.Evaluated, Callback: cb},
+		{Event: evo.Completed, Callback: func(evo.Population) error { exp.completed = true; return nil }},
+	}
+	pop, err := Runner{BatchSize: 2, MinimumAge: 2}.Run(ctx, exp, &mockEvaluator{})
+	if err != nil {
+		t.Fatalf("error not expected: %v", err)
+	}
+
+	// The initial population and then each batch of offspring should be searched
+	if len(exp.searched) < 2 || exp.searched[0] != exp.PopSize {
+		t.Fatalf("incorrect searches: expected the population of %d and then batches, actual %v", exp.PopSize, exp.searched)
+	}
+	for i, n := range exp.searched[1:] {
+		if n > 2 {
+			t.Errorf("incorrect size of batch %d: expected at most 2, actual %d", i+1, n)
+		}
+	}
+
+	// The generation should reflect the number of intervals
+	if pop.Generation < 3 {
+		t.Errorf("incorrect generation: expected at least 3, actual %d", pop.Generation)
+	}
+	if !exp.completed {
+		t.Error("completed event not published")
+	}
+
+	// The population should contain evaluated offspring with unique IDs. Offspring whose evaluation
+	// was skipped when the context was cancelled are not returned.
+	if len(pop.Genomes) == 0 || len(pop.Genomes) > exp.PopSize {
+		t.Errorf("incorrect population size: expected at most %d, actual %d", exp.PopSize, len(pop.Genomes))
+	}
+	ids := make(map[int64]bool, len(pop.Genomes))
+	var children int
+	for _, g := range pop.Genomes {
+		if ids[g.ID] {
+			t.Errorf("duplicate genome ID %d", g.ID)
+		}
+		ids[g.ID] = true
+		if g.ID > int64(exp.PopSize) {
+			children++
+		}
+		if g.Fitness != float64(g.ID) {
+			t.Errorf("genome %d not evaluated: fitness %f", g.ID, g.Fitness)
+		}
+		if g.Novelty != 1.0 {
+			t.Errorf("genome %d not scored by the experiment's searcher: novelty %f", g.ID, g.Novelty)
+		}
+	}
+	if children == 0 {
+		t.Error("expected offspring in the population")
+	}
+}
+
+func TestRunnerResume(t *testing.T) {
+
+	// Run the experiment for 2 intervals, keeping the last checkpoint
+	exp := &mockExperiment{PopSize: 10, Checkpoints: true}
+	ctx, fn, cb := evo.WithIterations(context.Background(), 2)
+	defer fn()
+	exp.subscriptions = []evo.Subscription{{Event: evo.Evaluated, Callback: cb}}
+	if _, err := (Runner{BatchSize: 3}).Run(ctx, exp, &mockEvaluator{}); err != nil {
+		t.Fatalf("error not expected: %v", err)
+	}
+
+	// The checkpoint holds the whole population, including any offspring being evaluated
+	cp := exp.checkpoint
+	if len(cp.Population.Genomes) != exp.PopSize {
+		t.Fatalf("incorrect checkpoint population size: expected %d, actual %d", exp.PopSize, len(cp.Population.Genomes))
+	}
+
+	// Resume from the checkpoint. Every genome is evaluated again and new offspring follow on from
+	// the last genome ID.
+	exp2 := &mockExperiment{PopSize: 10}
+	ctx, fn, cb = evo.WithIterations(context.Background(), 1)
+	defer fn()
+	exp2.subscriptions = []evo.Subscription{{Event: evo.Evaluated, Callback: cb}}
+	pop, err := (Runner{BatchSize: 3}).Resume(ctx, exp2, &mockEvaluator{}, cp)
+	if err != nil {
+		t.Fatalf("error not expected: %v", err)
+	}
+	if len(exp2.searched) == 0 || exp2.searched[0] != len(cp.Population.Genomes) {
+		t.Errorf("incorrect first search: expected the whole population of %d, actual %v", len(cp.Population.Genomes), exp2.searched)
+	}
+	if pop.Generation <= cp.Population.Generation {
+		t.Errorf("incorrect generation: expected more than %d, actual %d", cp.Population.Generation, pop.Generation)
+	}
+	existing := make(map[int64]bool, len(cp.Population.Genomes))
+	for _, g := range cp.Population.Genomes {
+		existing[g.ID] = true
+	}
+	for _, g := range pop.Genomes {
+		if !existing[g.ID] && g.ID <= cp.LastGID {
+			t.Errorf("genome ID %d reused: last genome ID in checkpoint is %d", g.ID, cp.LastGID)
+		}
+	}
+
+	// An unknown checkpoint version cannot be resumed
+	cp.Version = evo.CheckpointVersion + 1
+	if _, err = (Runner{}).Resume(context.Background(), exp2, &mockEvaluator{}, cp); err != evo.ErrUnknownCheckpointVersion {
+		t.Errorf("incorrect error: expected %v, actual %v", evo.ErrUnknownCheckpointVersion, err)
+	}
+}
+
+func TestNewRunner(t *testing.T) {
+	cfg := config.Configurer{Source: source.Map{
+		"realtime": map[string]interface{}{
+			"batch-size":  4,
+			"minimum-age": 3,
+			"interval":    20,
+			"comparison":  "novelty",
+		},
+	}}
+	r := NewRunner(cfg)
+	expected := Runner{BatchSize: 4, MinimumAge: 3, Interval: 20, Comparison: evo.ByNovelty}
+	if *r != expected {
+		t.Errorf("incorrect runner: expected %+v, actual %+v", expected, *r)
+	}
+}
+
+func TestRunnerRunErrors(t *testing.T) {
+	var cases = []struct {
+		Desc       string
+		Experiment *mockExperiment
+		Evaluator  *mockEvaluator
+	}{
+		{Desc: "no genomes", Experiment: &mockExperiment{}, Evaluator: &mockEvaluator{}},
+		{Desc: "populator fails", Experiment: &mockExperiment{PopSize: 4, ErrorOn: "populate"}, Evaluator: &mockEvaluator{}},
+		{Desc: "speciator fails", Experiment: &mockExperiment{PopSize: 4, ErrorOn: "speciate"}, Evaluator: &mockEvaluator{}},
+		{Desc: "selector fails", Experiment: &mockExperiment{PopSize: 4, ErrorOn: "select"}, Evaluator: &mockEvaluator{}},
+		{Desc: "selector returns no parents", Experiment: &mockExperiment{PopSize: 4, NoParents: true}, Evaluator: &mockEvaluator{}},
+		{Desc: "crosser fails", Experiment: &mockExperiment{PopSize: 4, ErrorOn: "cross"}, Evaluator: &mockEvaluator{}},
+		{Desc: "mutator fails", Experiment: &mockExperiment{PopSize: 4, ErrorOn: "mutate"}, Evaluator: &mockEvaluator{}},
+		{Desc: "transcriber fails", Experiment: &mockExperiment{PopSize: 4, ErrorOn: "transcribe"}, Evaluator: &mockEvaluator{}},
+		{Desc: "translator fails", Experiment: &mockExperiment{PopSize: 4, ErrorOn: "translate"}, Evaluator: &mockEvaluator{}},
+		{Desc: "searcher fails", Experiment: &mockExperiment{PopSize: 4, ErrorOn: "search"}, Evaluator: &mockEvaluator{}},
+		{Desc: "evaluator fails", Experiment: &mockExperiment{PopSize: 4}, Evaluator: &mockEvaluator{HasError: true}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Desc, func(t *testing.T) {
+			ctx, fn := context.WithTimeout(context.Background(), time.Second)
+			defer fn()
+			if _, err := (Runner{BatchSize: 2}).Run(ctx, c.Experiment, c.Evaluator); err == nil {
+				t.Error("expected error not found")
+			}
+		})
+	}
+}
+
+func TestStateReplace(t *testing.T) {
+
+	// Genome 1 is the worst but is too young, genome 3 is being evaluated, and genome 4 has not been
+	// evaluated so genome 2 should be replaced
+	jobs := make(chan []evo.Genome, 1)
+	s := &state{
+		Runner: Runner{MinimumAge: 2, Interval: 10, Comparison: evo.ByFitness},
+		exp:    &mockExperiment{},
+		pop: &evo.Population{Genomes: []evo.Genome{
+			{ID: 1, Fitness: 1.0},
+			{ID: 2, Fitness: 2.0},
+			{ID: 3, Fitness: 0.5},
+			{ID: 4, Fitness: 0.0},
+		}},
+		lastGID:   4,
+		evaluated: map[int64]bool{1: true, 2: true, 3: true},
+		witnessed: map[int64]int{1: 1, 2: 2, 3: 5},
+		pending:   map[int64]bool{3: true},
+		rng:       evo.NewRandom(),
+		jobs:      jobs,
+	}
+	ok, err := s.replace()
+	if err != nil {
+		t.Fatalf("error not expected: %v", err)
+	}
+	if !ok {
+		t.Fatal("expected a replacement")
+	}
+	expected := []int64{1, 5, 3, 4}
+	for i, g := range s.pop.Genomes {
+		if g.ID != expected[i] {
+			t.Errorf("incorrect genome at %d: expected %d, actual %d", i, expected[i], g.ID)
+		}
+	}
+	if len(s.queued) != 1 || s.queued[0].ID != 5 {
+		t.Errorf("incorrect genomes queued: expected 5, actual %v", s.queued)
+	}
+	if !s.pending[5] {
+		t.Error("offspring should be pending")
+	}
+
+	// Nothing else is eligible while genomes are being evaluated
+	if ok, err = s.replace(); err != nil || ok {
+		t.Errorf("expected no replacement: ok %v, err %v", ok, err)
+	}
+}
+
+func TestStateNext(t *testing.T) {
+
+	// Parent groups are taken from one selection until they run out
+	exp := &mockExperiment{Groups: 3}
+	s := &state{
+		exp: exp,
+		pop: &evo.Population{},
+		rng: evo.NewRandom(),
+	}
+	candidates := []evo.Genome{{ID: 1, Fitness: 1.0}, {ID: 2, Fitness: 2.0}}
+	for i, expected := range []int{1, 1, 1, 2} {
+		pgrp, err := s.next(candidates)
+		if err != nil {
+			t.Fatalf("error not expected: %v", err)
+		}
+		if len(pgrp) != 1 || pgrp[0].ID != 2 {
+			t.Errorf("incorrect parents for replacement %d: expected genome 2, actual %v", i, pgrp)
+		}
+		if exp.selects != expected {
+			t.Errorf("incorrect number of selections after replacement %d: expected %d, actual %d", i, expected, exp.selects)
+		}
+	}
+
+	// Advancing the population discards the remaining groups
+	s.advance()
+	if _, err := s.next(candidates); err != nil {
+		t.Fatalf("error not expected: %v", err)
+	}
+	if exp.selects != 3 {
+		t.Errorf("incorrect number of selections after advancing: expected 3, actual %d", exp.selects)
+	}
+}
+
+type mockExperiment struct {
+	PopSize     int
+	ErrorOn     string
+	NoParents   bool
+	Groups      int  // Number of parent groups returned by the selector. Defaults to one.
+	Checkpoints bool // Keep the last checkpoint
+
+	subscriptions []evo.Subscription
+	completed     bool
+	selects       int
+	searched      []int // Size of each batch searched
+	checkpoint    evo.Checkpoint
+}
+
+func (m *mockExperiment) fail(method string) error {
+	if m.ErrorOn == method {
+		return errors.New("error in mock " + method)
+	}
+	return nil
+}
+
+func (m *mockExperiment) Subscriptions() []evo.Subscription { return m.subscriptions }
+
+func (m *mockExperiment) Populate() (pop evo.Population, err error) {
+	pop.Genomes = make([]evo.Genome, m.PopSize)
+	for i := range pop.Genomes {
+		pop.Genomes[i].ID = int64(i + 1)
+	}
+	return pop, m.fail("populate")
+}
+
+func (m *mockExperiment) Speciate(*evo.Population) error { return m.fail("speciate") }
+
+func (m *mockExperiment) Select(pop evo.Population) ([]evo.Genome, [][]evo.Genome, error) {
+	if m.NoParents {
+		return nil, nil, nil
+	}
+	m.selects++
+	best := pop.Genomes[0]
+	for _, g := range pop.Genomes {
+		if best.Fitness < g.Fitness {
+			best = g
+		}
+	}
+	parents := [][]evo.Genome{{best}}
+	for len(parents) < m.Groups {
+		parents = append(parents, []evo.Genome{best})
+	}
+	return nil, parents, m.fail("select")
+}
+
+func (m *mockExperiment) Cross(parents ...evo.Genome) (evo.Genome, error) {
+	return parents[0], m.fail("cross")
+}
+
+func (m *mockExperiment) Mutate(*evo.Genome) error { return m.fail("mutate") }
+
+// Search evaluates the phenomes and marks the results with a novelty so that tests can tell the
+// experiment's searcher was used
+func (m *mockExperiment) Search(eval evo.Evaluator, phenomes []evo.Phenome) (results []evo.Result, err error) {
+	m.searched = append(m.searched, len(phenomes))
+	results = make([]evo.Result, len(phenomes))
+	for i, p := range phenomes {
+		if results[i], err = eval.Evaluate(p); err != nil {
+			return
+		}
+		results[i].Novelty = 1.0
+	}
+	return results, m.fail("search")
+}
+
+// Checkpoint keeps the checkpoint, if requested. The experiment is only a checkpointer in effect if
+// Checkpoints is set.
+func (m *mockExperiment) Checkpoint(cp evo.Checkpoint) error {
+	if m.Checkpoints {
+		m.checkpoint = cp
+	}
+	return nil
+}
+
+func (m *mockExperiment) Transcribe(enc evo.Substrate) (evo.Substrate, error) {
+	return enc, m.fail("transcribe")
+}
+
+func (m *mockExperiment) Translate(evo.Substrate) (evo.Network, error) {
+	return mockNetwork{}, m.fail("translate")
+}
+
+type mockNetwork struct{}
+
+func (mockNetwork) Activate(evo.Matrix) (evo.Matrix, error) { return nil, nil }
+
+type mockEvaluator struct{ HasError bool }
+
+func (m *mockEvaluator) Evaluate(p evo.Phenome) (evo.Result, error) {
+	if m.HasError {
+		return evo.Result{}, errors.New("error in mock evaluator")
+	}
+	time.Sleep(time.Microsecond)
+	return evo.Result{ID: p.ID, Fitness: float64(p.ID)}, nil
+}
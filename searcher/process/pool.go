@@ -0,0 +1,253 @@
+package process
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"math"
+	"os"
+	"os/exec"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/klokare/evo"
+	"github.com/klokare/evo/config"
+)
+
+// Pool is an evaluator that sends each phenome to one of a pool of worker processes. Workers are
+// started when first needed and are reused for later requests. The pool is safe for concurrent use
+// so it can be used with a searcher like parallel.Searcher; at most Size evaluations are in progress
+// at once. The phenomes' networks must be created with this package's Translator.
+type Pool struct {
+	Command string        // Path of the worker executable
+	Args    []string      // Arguments passed to each worker
+	Env     []string      // Additional environment variables for each worker, in the form key=value
+	Size    int           // Number of workers. If zero, the number of CPUs is used.
+	Timeout time.Duration // Maximum time to wait for a response. If zero, there is no timeout.
+	Retries int           // Number of times a request is retried on a new worker after a failure
+	Format                // Format of the network in each request. If zero, the substrate format is used.
+
+	once    sync.Once
+	slots   chan *worker // idle workers, or nil if the slot's worker needs to be started
+	mu      sync.Mutex
+	running map[*worker]bool
+	closed  bool
+}
+
+// Keys used by the process pool. NewPool declares them with the configurer.
+var Keys = []config.Key{
+	{Name: "process|pool|command", Type: config.String, Required: true, Description: "Path of the worker executable"},
+	{Name: "process|pool|args", Type: config.Strings, Description: "Arguments passed to each worker"},
+	{Name: "process|pool|size", Type: config.Int, Min: 0.0, Max: math.Inf(1), Description: "Number of workers. The number of CPUs is used if zero."},
+	{Name: "process|pool|timeout", Type: config.Float64, Min: 0.0, Max: math.Inf(1), Description: "Maximum seconds to wait for a response. There is no timeout if zero."},
+	{Name: "process|pool|retries", Type: config.Int, Min: 0.0, Max: math.Inf(1), Description: "Number of times a request is retried on a new worker after a failure"},
+	{Name: "process|pool|format", Type: config.String, Description: "Format of the network in each request: substrate or weights"},
+}
+
+// NewPool creates a new process pool using the configuration. The timeout is given in seconds.
+func NewPool(cfg config.Configurer) *Pool {
+	cfg.Declare(Keys...)
+	return &Pool{
+		Command: cfg.String("process|pool|command"),
+		Args:    cfg.Strings("process|pool|args"),
+		Size:    cfg.Int("process|pool|size"),
+		Timeout: time.Duration(cfg.Float64("process|pool|timeout") * float64(time.Second)),
+		Retries: cfg.Int("process|pool|retries"),
+		Format:  Formats[cfg.String("process|pool|format")],
+	}
+}
+
+// Evaluate the phenome using one of the workers. If the worker exits, does not respond in time, or
+// writes an invalid response, it is killed and the request is retried on a new worker up to the
+// number of retries. An error reported by the worker in its response is returned without retrying.
+func (p *Pool) Evaluate(ph evo.Phenome) (r evo.Result, err error) {
+
+	// Check for errors
+	if p.Command == "" {
+		err = ErrMissingCommand
+		return
+	}
+
+	// Create the request
+	var req Request
+	if req, err = NewRequest(ph, p.Format); err != nil {
+		return
+	}
+	var b []byte
+	if b, err = json.Marshal(req); err != nil {
+		return
+	}
+	b = append(b, '\n')
+
+	// Send the request to a worker, retrying on failure
+	p.once.Do(p.init)
+	for attempt := 0; ; attempt++ {
+		var w *worker
+		if w, err = p.acquire(); err != nil {
+			return
+		}
+		var res Response
+		if res, err = w.call(b, ph.ID, p.Timeout); err == nil {
+			p.slots <- w
+			return res.Result()
+		}
+		p.discard(w)
+		if attempt >= p.Retries {
+			return
+		}
+	}
+}
+
+// Close the pool and kill the workers. Evaluations in progress will fail.
+func (p *Pool) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.closed = true
+	for w := range p.running {
+		w.kill()
+	}
+	p.running = nil
+	return nil
+}
+
+// Initialise the slots
+func (p *Pool) init() {
+	n := p.Size
+	if n <= 0 {
+		n = runtime.NumCPU()
+	}
+	p.slots = make(chan *worker, n)
+	for i := 0; i < n; i++ {
+		p.slots <- nil
+	}
+}
+
+// Acquire an idle worker, starting one if necessary
+func (p *Pool) acquire() (w *worker, err error) {
+	w = <-p.slots
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.closed {
+		p.slots <- w
+		return nil, ErrPoolClosed
+	}
+	if w != nil {
+		return
+	}
+	if w, err = p.start(); err != nil {
+		p.slots <- nil
+		return
+	}
+	if p.running == nil {
+		p.running = make(map[*worker]bool, cap(p.slots))
+	}
+	p.running[w] = true
+	return
+}
+
+// Discard a failed worker and free its slot
+func (p *Pool) discard(w *worker) {
+	w.kill()
+	p.mu.Lock()
+	delete(p.running, w)
+	p.mu.Unlock()
+	p.slots <- nil
+}
+
+// A worker is a running worker process
+type worker struct {
+	cmd   *exec.Cmd
+	stdin io.WriteCloser
+	lines chan []byte   // responses from the worker, closed when the worker's output ends
+	done  chan struct{} // closed when the worker is killed
+	once  sync.Once
+}
+
+// Start a new worker process
+func (p *Pool) start() (w *worker, err error) {
+	cmd := exec.Command(p.Command, p.Args...)
+	cmd.Env = append(os.Environ(), p.Env...)
+	cmd.Stderr = os.Stderr
+	w = &worker{cmd: cmd, lines: make(chan []byte), done: make(chan struct{})}
+	if w.stdin, err = cmd.StdinPipe(); err != nil {
+		return
+	}
+	var stdout io.ReadCloser
+	if stdout, err = cmd.StdoutPipe(); err != nil {
+		return
+	}
+	if err = cmd.Start(); err != nil {
+		return
+	}
+
+	// Read the responses until the worker's output ends, then wait for it to exit
+	go func() {
+		defer cmd.Wait()
+		defer close(w.lines)
+		r := bufio.NewReader(stdout)
+		for {
+			line, err := r.ReadBytes('\n')
+			if err != nil {
+				return
+			}
+			select {
+			case w.lines <- line:
+			case <-w.done:
+				io.Copy(ioutil.Discard, r) // drain so the worker can exit
+				return
+			}
+		}
+	}()
+	return
+}
+
+// Call the worker with the request and wait for its response. The timer starts before the request
+// is written, and kills the worker when it expires, so that a worker which has stopped reading
+// cannot block the write forever. Once the worker has been killed, the call fails whatever else
+// happened.
+func (w *worker) call(req []byte, id int64, timeout time.Duration) (res Response, err error) {
+	var expired chan struct{}
+	if timeout > 0 {
+		expired = make(chan struct{})
+		t := time.AfterFunc(timeout, func() {
+			close(expired)
+			w.kill()
+		})
+		defer func() {
+			if !t.Stop() {
+				err = ErrWorkerTimeout
+			}
+		}()
+	}
+	if _, err = w.stdin.Write(req); err != nil {
+		return
+	}
+	select {
+	case line, ok := <-w.lines:
+		if !ok {
+			err = ErrWorkerExited
+			return
+		}
+		if err = json.Unmarshal(line, &res); err != nil {
+			return
+		}
+		if res.ID != id {
+			err = ErrMismatchedID
+		}
+	case <-expired:
+	}
+	return
+}
+
+// Kill the worker process
+func (w *worker) kill() {
+	w.once.Do(func() {
+		close(w.done)
+		w.stdin.Close()
+		if w.cmd.Process != nil {
+			w.cmd.Process.Kill()
+		}
+	})
+}
@@ -0,0 +1,226 @@
+package process
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/klokare/evo"
+)
+
+// TestHelperProcess is not a real test. It is the worker used by the pool tests and follows the
+// protocol by returning the number of conns as the fitness. The first trait controls misbehaviour:
+// 1 exits without responding, 2 sleeps past the timeout, 3 responds with the wrong ID, 4 reports
+// an evaluation error, and 5 stops reading requests after responding.
+func TestHelperProcess(t *testing.T) {
+	if os.Getenv("EVO_PROCESS_WORKER") != "1" {
+		return
+	}
+	defer os.Exit(0)
+
+	r := bufio.NewReader(os.Stdin)
+	for {
+		line, err := r.ReadBytes('\n')
+		if err != nil {
+			return
+		}
+		var req Request
+		if err = json.Unmarshal(line, &req); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+		res := Response{ID: req.ID, Fitness: float64(len(req.Conns) + len(req.Weights)), Behavior: req.Biases}
+		if len(req.Traits) > 0 {
+			switch req.Traits[0] {
+			case 1:
+				os.Exit(1)
+			case 2:
+				time.Sleep(time.Second)
+			case 3:
+				res.ID++
+			case 4:
+				res.Error = "evaluation failed"
+			}
+		}
+		b, _ := json.Marshal(res)
+		fmt.Fprintf(os.Stdout, "%s\n", b)
+		if len(req.Traits) > 0 && req.Traits[0] == 5 {
+			time.Sleep(5 * time.Second)
+			return
+		}
+	}
+}
+
+func newTestPool() *Pool {
+	return &Pool{
+		Command: os.Args[0],
+		Args:    []string{"-test.run=TestHelperProcess"},
+		Env:     []string{"EVO_PROCESS_WORKER=1"},
+		Size:    2,
+		Timeout: 200 * time.Millisecond,
+	}
+}
+
+func newTestPhenome(t *testing.T, id int64, trait float64) evo.Phenome {
+	net, err := Translator{}.Translate(evo.Substrate{
+		Nodes: []evo.Node{
+			{Position: evo.Position{Layer: 1.0}, Neuron: evo.Output, Activation: evo.Sigmoid, Bias: 0.5},
+			{Position: evo.Position{Layer: 0.0}, Neuron: evo.Input, Activation: evo.Direct},
+		},
+		Conns: []evo.Conn{{Source: evo.Position{Layer: 0.0}, Target: evo.Position{Layer: 1.0}, Weight: 1.5, Enabled: true}},
+	})
+	if err != nil {
+		t.Fatalf("error not expected: %v", err)
+	}
+	return evo.Phenome{ID: id, Traits: []float64{trait}, Network: net}
+}
+
+func TestPoolEvaluate(t *testing.T) {
+	var cases = []struct {
+		Desc     string
+		Trait    float64
+		Format   Format
+		Retries  int
+		HasError bool
+		Expected float64
+	}{
+		{Desc: "substrate format", Trait: 0, Expected: 1.0},
+		{Desc: "weights format", Trait: 0, Format: WeightsFormat, Expected: 1.0},
+		{Desc: "worker exits", Trait: 1, HasError: true},
+		{Desc: "worker exits with retries", Trait: 1, Retries: 1, HasError: true},
+		{Desc: "worker times out", Trait: 2, HasError: true},
+		{Desc: "worker responds with wrong id", Trait: 3, HasError: true},
+		{Desc: "worker reports error", Trait: 4, HasError: true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Desc, func(t *testing.T) {
+			p := newTestPool()
+			p.Format = c.Format
+			p.Retries = c.Retries
+			defer p.Close()
+
+			r, err := p.Evaluate(newTestPhenome(t, 5, c.Trait))
+			if c.HasError {
+				if err == nil {
+					t.Error("expected error not found")
+				}
+			} else if err != nil {
+				t.Fatalf("error not expected: %v", err)
+			} else if r.ID != 5 || r.Fitness != c.Expected {
+				t.Errorf("incorrect result: expected ID 5 fitness %f, actual ID %d fitness %f", c.Expected, r.ID, r.Fitness)
+			}
+
+			// The pool should recover and continue to evaluate
+			if r, err = p.Evaluate(newTestPhenome(t, 6, 0)); err != nil {
+				t.Errorf("error not expected after recovery: %v", err)
+			} else if r.ID != 6 {
+				t.Errorf("incorrect result after recovery: expected ID 6, actual %d", r.ID)
+			}
+		})
+	}
+}
+
+func TestPoolEvaluateConcurrent(t *testing.T) {
+	p := newTestPool()
+	defer p.Close()
+
+	errs := make(chan error, 10)
+	for i := 0; i < 10; i++ {
+		go func(id int64) {
+			r, err := p.Evaluate(newTestPhenome(t, id, 0))
+			if err == nil && r.ID != id {
+				err = fmt.Errorf("incorrect result: expected ID %d, actual %d", id, r.ID)
+			}
+			errs <- err
+		}(int64(i + 1))
+	}
+	for i := 0; i < 10; i++ {
+		if err := <-errs; err != nil {
+			t.Error(err)
+		}
+	}
+	if n := len(p.running); n > p.Size {
+		t.Errorf("too many workers started: expected at most %d, actual %d", p.Size, n)
+	}
+}
+
+func TestPoolEvaluateStalledWrite(t *testing.T) {
+	p := newTestPool()
+	p.Size = 1
+	defer p.Close()
+
+	// The worker responds and then stops reading
+	if _, err := p.Evaluate(newTestPhenome(t, 1, 5)); err != nil {
+		t.Fatalf("error not expected: %v", err)
+	}
+
+	// A request larger than the pipe's buffer cannot be written so the timeout must end the call
+	ph := newTestPhenome(t, 2, 0)
+	for len(ph.Traits) < 100000 {
+		ph.Traits = append(ph.Traits, 0.123456789)
+	}
+	start := time.Now()
+	if _, err := p.Evaluate(ph); err != ErrWorkerTimeout {
+		t.Errorf("incorrect error: expected %v, actual %v", ErrWorkerTimeout, err)
+	}
+	if d := time.Since(start); d > 2*time.Second {
+		t.Errorf("call not ended by the timeout: took %v", d)
+	}
+}
+
+func TestPoolErrors(t *testing.T) {
+
+	// Missing command
+	if _, err := (&Pool{}).Evaluate(newTestPhenome(t, 1, 0)); err != ErrMissingCommand {
+		t.Errorf("incorrect error: expected %v, actual %v", ErrMissingCommand, err)
+	}
+
+	// Network not created by the process translator
+	p := newTestPool()
+	if _, err := p.Evaluate(evo.Phenome{ID: 1}); err != ErrMissingSubstrate {
+		t.Errorf("incorrect error: expected %v, actual %v", ErrMissingSubstrate, err)
+	}
+
+	// Closed pool
+	p.Close()
+	if _, err := p.Evaluate(newTestPhenome(t, 1, 0)); err != ErrPoolClosed {
+		t.Errorf("incorrect error: expected %v, actual %v", ErrPoolClosed, err)
+	}
+}
+
+func TestNewRequest(t *testing.T) {
+	p := newTestPhenome(t, 3, 0.5)
+
+	// The substrate format orders the nodes by position
+	req, err := NewRequest(p, SubstrateFormat)
+	if err != nil {
+		t.Fatalf("error not expected: %v", err)
+	}
+	if len(req.Nodes) != 2 || req.Nodes[0].Neuron != "input" || req.Nodes[1].Activation != "sigmoid" {
+		t.Errorf("incorrect nodes: %v", req.Nodes)
+	}
+	if len(req.Conns) != 1 || req.Conns[0].Weight != 1.5 || req.Conns[0].Target.Layer != 1.0 {
+		t.Errorf("incorrect conns: %v", req.Conns)
+	}
+	if len(req.Biases) != 0 || len(req.Weights) != 0 {
+		t.Error("substrate format should not include biases or weights")
+	}
+
+	// The weights format includes only the biases and weights
+	if req, err = NewRequest(p, WeightsFormat); err != nil {
+		t.Fatalf("error not expected: %v", err)
+	}
+	if len(req.Biases) != 2 || req.Biases[0] != 0.0 || req.Biases[1] != 0.5 {
+		t.Errorf("incorrect biases: %v", req.Biases)
+	}
+	if len(req.Weights) != 1 || req.Weights[0] != 1.5 {
+		t.Errorf("incorrect weights: %v", req.Weights)
+	}
+	if len(req.Nodes) != 0 || len(req.Conns) != 0 {
+		t.Error("weights format should not include nodes or conns")
+	}
+}
@@ -0,0 +1,196 @@
+// Package process evaluates phenomes in worker subprocesses so that evaluators may be written in
+// any language. Workers communicate with the pool over their standard input and output using
+// line-delimited JSON: each request and each response is a single JSON object followed by a
+// newline. A worker reads a request, evaluates it, and writes exactly one response before reading
+// the next request. Anything written to the worker's standard error is passed through to the
+// parent's.
+//
+// A request in the substrate format describes the whole network:
+//
+//	{"id":7,"traits":[0.5],"nodes":[{"layer":0,"x":0,"y":0,"z":0,"neuron":"input","activation":"direct","bias":0}, ...],
+//	 "conns":[{"source":{"layer":0,"x":0,"y":0,"z":0},"target":{"layer":1,"x":0.5,"y":0,"z":0},"weight":1.2,"enabled":true}, ...]}
+//
+// Neuron is one of input, hidden or output and activation is the name of the activation function,
+// such as sigmoid or steepened-sigmoid. Nodes are ordered by their position and conns by their
+// source and then target positions. A request in the weights format, intended for workers that
+// build a fixed topology themselves, carries only the biases of the nodes and the weights of the
+// conns in the same order:
+//
+//	{"id":7,"traits":[0.5],"biases":[0,0,0.3],"weights":[1.2,-0.4]}
+//
+// The response reports the result of the evaluation for the request with the same ID. Novelty,
+// solved, behavior and objectives are optional. If the evaluation fails, error should describe the
+// failure:
+//
+//	{"id":7,"fitness":3.9,"novelty":0,"solved":false,"behavior":[0.1,0.2],"objectives":[3.9,-0.2],"error":""}
+//
+// A worker that exits, writes an invalid response, or does not respond within the timeout is
+// killed and replaced by a new worker.
+package process
+
+import (
+	"errors"
+	"sort"
+
+	"github.com/klokare/evo"
+)
+
+// Known errors
+var (
+	ErrMissingSubstrate = errors.New("phenome network does not provide a substrate; use the process translator")
+	ErrMismatchedID     = errors.New("worker response ID does not match the request")
+	ErrMissingNetwork   = errors.New("process network has no in-process network to activate")
+	ErrMissingCommand   = errors.New("process pool requires a worker command")
+	ErrWorkerTimeout    = errors.New("worker did not respond within the timeout")
+	ErrWorkerExited     = errors.New("worker exited before responding")
+	ErrPoolClosed       = errors.New("process pool has been closed")
+)
+
+// Format of the network in the request
+type Format byte
+
+// Known formats
+const (
+	SubstrateFormat Format = iota + 1 // The nodes and conns of the decoded substrate
+	WeightsFormat                     // Only the biases and weights, in order, of the decoded substrate
+)
+
+func (f Format) String() string {
+	switch f {
+	case SubstrateFormat:
+		return "substrate"
+	case WeightsFormat:
+		return "weights"
+	default:
+		return "unknown format"
+	}
+}
+
+// Formats provides a map of formats by name
+var (
+	Formats = map[string]Format{
+		"substrate": SubstrateFormat,
+		"weights":   WeightsFormat,
+	}
+)
+
+// Request is the message sent to the worker for each phenome
+type Request struct {
+	ID      int64     `json:"id"`
+	Traits  []float64 `json:"traits"`
+	Nodes   []Node    `json:"nodes,omitempty"`
+	Conns   []Conn    `json:"conns,omitempty"`
+	Biases  []float64 `json:"biases,omitempty"`
+	Weights []float64 `json:"weights,omitempty"`
+}
+
+// Position of a node in the request
+type Position struct {
+	Layer float64 `json:"layer"`
+	X     float64 `json:"x"`
+	Y     float64 `json:"y"`
+	Z     float64 `json:"z"`
+}
+
+// Node in the request
+type Node struct {
+	Position
+	Neuron     string  `json:"neuron"`
+	Activation string  `json:"activation"`
+	Bias       float64 `json:"bias"`
+}
+
+// Conn in the request
+type Conn struct {
+	Source  Position `json:"source"`
+	Target  Position `json:"target"`
+	Weight  float64  `json:"weight"`
+	Enabled bool     `json:"enabled"`
+}
+
+// Response is the message returned by the worker for each request
+type Response struct {
+	ID         int64       `json:"id"`
+	Fitness    float64     `json:"fitness"`
+	Novelty    float64     `json:"novelty"`
+	Solved     bool        `json:"solved"`
+	Behavior   interface{} `json:"behavior,omitempty"`
+	Objectives []float64   `json:"objectives,omitempty"`
+	Error      string      `json:"error,omitempty"`
+}
+
+// NewRequest creates the request for the phenome in the given format. The phenome's network must
+// have been created by the process Translator so that its substrate is available.
+func NewRequest(p evo.Phenome, f Format) (req Request, err error) {
+
+	// Retrieve the substrate
+	net, ok := p.Network.(*Network)
+	if !ok {
+		err = ErrMissingSubstrate
+		return
+	}
+	// Order the nodes and conns as described by the protocol
+	nodes := make([]evo.Node, len(net.Nodes))
+	copy(nodes, net.Nodes)
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].Compare(nodes[j]) < 0 })
+	conns := make([]evo.Conn, len(net.Conns))
+	copy(conns, net.Conns)
+	sort.Slice(conns, func(i, j int) bool { return conns[i].Compare(conns[j]) < 0 })
+
+	// Create the request
+	req = Request{ID: p.ID, Traits: p.Traits}
+	if req.Traits == nil {
+		req.Traits = []float64{}
+	}
+	switch f {
+	case WeightsFormat:
+		req.Biases = make([]float64, len(nodes))
+		for i, n := range nodes {
+			req.Biases[i] = n.Bias
+		}
+		req.Weights = make([]float64, len(conns))
+		for i, c := range conns {
+			req.Weights[i] = c.Weight
+		}
+	default:
+		req.Nodes = make([]Node, len(nodes))
+		for i, n := range nodes {
+			req.Nodes[i] = Node{
+				Position:   position(n.Position),
+				Neuron:     n.Neuron.String(),
+				Activation: n.Activation.String(),
+				Bias:       n.Bias,
+			}
+		}
+		req.Conns = make([]Conn, len(conns))
+		for i, c := range conns {
+			req.Conns[i] = Conn{
+				Source:  position(c.Source),
+				Target:  position(c.Target),
+				Weight:  c.Weight,
+				Enabled: c.Enabled,
+			}
+		}
+	}
+	return
+}
+
+func position(p evo.Position) Position {
+	return Position{Layer: p.Layer, X: p.X, Y: p.Y, Z: p.Z}
+}
+
+// Result converts the response into the evaluation result
+func (r Response) Result() (res evo.Result, err error) {
+	res = evo.Result{
+		ID:         r.ID,
+		Fitness:    r.Fitness,
+		Novelty:    r.Novelty,
+		Solved:     r.Solved,
+		Behavior:   r.Behavior,
+		Objectives: r.Objectives,
+	}
+	if r.Error != "" {
+		err = errors.New(r.Error)
+	}
+	return
+}
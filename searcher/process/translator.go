@@ -0,0 +1,37 @@
+package process
+
+import (
+	"github.com/klokare/evo"
+)
+
+// Translator wraps another translator so that the network it creates keeps the substrate from
+// which it was made. This allows the pool to send the substrate to its workers. If there is no
+// wrapped translator, the network can only be evaluated by a worker.
+type Translator struct {
+	evo.Translator
+}
+
+// Translate the substrate into a network that also provides the substrate
+func (t Translator) Translate(sub evo.Substrate) (net evo.Network, err error) {
+	n := &Network{Substrate: sub}
+	if t.Translator != nil {
+		if n.Network, err = t.Translator.Translate(sub); err != nil {
+			return
+		}
+	}
+	return n, nil
+}
+
+// Network pairs a substrate with the network created from it
+type Network struct {
+	evo.Substrate
+	evo.Network
+}
+
+// Activate the wrapped network. If there is none, an error is returned.
+func (n *Network) Activate(inputs evo.Matrix) (evo.Matrix, error) {
+	if n.Network == nil {
+		return nil, ErrMissingNetwork
+	}
+	return n.Network.Activate(inputs)
+}
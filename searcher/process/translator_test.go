@@ -0,0 +1,38 @@
+package process
+
+import (
+	"testing"
+
+	"github.com/klokare/evo"
+	"github.com/klokare/evo/network/forward"
+)
+
+func TestTranslatorTranslate(t *testing.T) {
+	sub := evo.Substrate{
+		Nodes: []evo.Node{
+			{Position: evo.Position{Layer: 0.0}, Neuron: evo.Input, Activation: evo.Direct},
+			{Position: evo.Position{Layer: 1.0}, Neuron: evo.Output, Activation: evo.Direct},
+		},
+		Conns: []evo.Conn{{Source: evo.Position{Layer: 0.0}, Target: evo.Position{Layer: 1.0}, Weight: 2.0, Enabled: true}},
+	}
+
+	// Without a wrapped translator the network keeps the substrate but cannot be activated
+	net, err := Translator{}.Translate(sub)
+	if err != nil {
+		t.Fatalf("error not expected: %v", err)
+	}
+	if pn := net.(*Network); pn.Complexity() != sub.Complexity() {
+		t.Errorf("incorrect substrate complexity: expected %d, actual %d", sub.Complexity(), pn.Complexity())
+	}
+	if _, err = net.Activate(nil); err != ErrMissingNetwork {
+		t.Errorf("incorrect error: expected %v, actual %v", ErrMissingNetwork, err)
+	}
+
+	// With a wrapped translator the network can also be activated in process
+	if net, err = (Translator{Translator: forward.Translator{}}).Translate(sub); err != nil {
+		t.Fatalf("error not expected: %v", err)
+	}
+	if _, ok := net.(*Network).Network.(forward.Network); !ok {
+		t.Errorf("incorrect wrapped network type: %T", net.(*Network).Network)
+	}
+}
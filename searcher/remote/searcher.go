@@ -0,0 +1,380 @@
+package remote
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/klokare/evo"
+	"github.com/klokare/evo/config"
+	"github.com/klokare/evo/searcher/process"
+)
+
+// Known errors
+var (
+	ErrNoWorkers        = errors.New("no remote workers are available")
+	ErrMissingSubstrate = process.ErrMissingSubstrate
+)
+
+// Searcher ships each phenome to a remote worker over HTTP. The phenomes' networks must be created
+// with process.Translator so that their substrates can be sent; the evaluator passed to Search is
+// not used as each worker has its own.
+//
+// Tasks are shared out evenly between the workers' queues. A worker that has emptied its own queue
+// steals from the back of the longest queue of another worker. Once all queues are empty, an idle
+// worker also takes a copy of a task still being evaluated by another worker so that a straggler
+// does not hold up the search. Each worker's heartbeat is checked at every interval and, after the
+// maximum number of missed heartbeats or failed requests, the worker is considered lost: its
+// outstanding requests are abandoned and its tasks re-dispatched to the remaining workers. If a
+// task's result arrives more than once, the first is kept. Workers are given a fresh start with
+// each search.
+type Searcher struct {
+	Workers     []string      // Base URLs of the workers, such as http://localhost:8080
+	Concurrency int           // Number of concurrent tasks per worker. If zero, 1 is used.
+	Heartbeat   time.Duration // Interval between heartbeats. If zero, 1 second is used.
+	MaxMissed   int           // Missed heartbeats or failed requests before a worker is lost. If zero, 3 is used.
+	Client      *http.Client  // Client used for requests. If nil, the default client is used.
+}
+
+// Keys used by the remote searcher. NewSearcher declares them with the configurer.
+var Keys = []config.Key{
+	{Name: "remote|searcher|workers", Type: config.Strings, Required: true, Description: "Base URLs of the workers"},
+	{Name: "remote|searcher|concurrency", Type: config.Int, Default: 1, Min: 0.0, Max: math.Inf(1), Description: "Number of concurrent tasks per worker"},
+	{Name: "remote|searcher|heartbeat", Type: config.Float64, Default: 1.0, Min: 0.0, Max: math.Inf(1), Description: "Seconds between heartbeats"},
+	{Name: "remote|searcher|max-missed", Type: config.Int, Default: 3, Min: 0.0, Max: math.Inf(1), Description: "Missed heartbeats or failed requests before a worker is lost"},
+}
+
+// NewSearcher creates a new remote searcher using the configuration. The worker URLs may be a list
+// or a comma-separated string and the heartbeat is given in seconds.
+func NewSearcher(cfg config.Configurer) *Searcher {
+	cfg.Declare(Keys...)
+	urls := cfg.Strings("remote|searcher|workers")
+	if len(urls) == 1 {
+		urls = strings.Split(urls[0], ",")
+	}
+	return &Searcher{
+		Workers:     urls,
+		Concurrency: cfg.Int("remote|searcher|concurrency"),
+		Heartbeat:   time.Duration(cfg.Float64("remote|searcher|heartbeat") * float64(time.Second)),
+		MaxMissed:   cfg.Int("remote|searcher|max-missed"),
+	}
+}
+
+// Search the solution space with the phenomes using the remote workers
+func (s *Searcher) Search(eval evo.Evaluator, phenomes []evo.Phenome) (results []evo.Result, err error) {
+
+	// Check for errors
+	if len(s.Workers) == 0 {
+		err = ErrNoWorkers
+		return
+	}
+	if len(phenomes) == 0 {
+		return
+	}
+
+	// Create the tasks
+	tasks := make([]Task, len(phenomes))
+	for i, p := range phenomes {
+		net, ok := p.Network.(*process.Network)
+		if !ok {
+			err = ErrMissingSubstrate
+			return
+		}
+		tasks[i] = Task{ID: p.ID, Traits: p.Traits, Substrate: net.Substrate}
+	}
+
+	// Create the search and share out the tasks
+	ctx, fn := context.WithCancel(context.Background())
+	defer fn()
+	x := &search{
+		Searcher:  *s,
+		tasks:     tasks,
+		results:   make([]evo.Result, len(tasks)),
+		received:  make([]bool, len(tasks)),
+		remaining: len(tasks),
+		queues:    make([][]int, len(s.Workers)),
+		lost:      make([]bool, len(s.Workers)),
+		strikes:   make([]int, len(s.Workers)),
+		inflight:  make([]int, len(tasks)),
+		running:   make([]map[int]bool, len(s.Workers)),
+		cancels:   make([]context.CancelFunc, len(s.Workers)),
+	}
+	x.applyDefaults()
+	x.cond = sync.NewCond(&x.mu)
+	for i := range tasks {
+		w := i % len(s.Workers)
+		x.queues[w] = append(x.queues[w], i)
+	}
+	for w := range x.running {
+		x.running[w] = make(map[int]bool, x.Concurrency)
+	}
+
+	// Start the dispatchers and heartbeats for each worker
+	var wg sync.WaitGroup
+	for w := range s.Workers {
+		var wctx context.Context
+		wctx, x.cancels[w] = context.WithCancel(ctx)
+		for i := 0; i < x.Concurrency; i++ {
+			wg.Add(1)
+			go func(w int) {
+				defer wg.Done()
+				x.dispatch(wctx, w)
+			}(w)
+		}
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			x.heartbeat(wctx, w)
+		}(w)
+	}
+
+	// Wait for the results
+	x.mu.Lock()
+	for x.remaining > 0 && x.err == nil {
+		x.cond.Wait()
+	}
+	err = x.err
+	x.done = true
+	x.cond.Broadcast()
+	x.mu.Unlock()
+	fn()
+	wg.Wait()
+	if err != nil {
+		return
+	}
+	results = x.results
+	return
+}
+
+// The state of a single search
+type search struct {
+	Searcher
+	tasks     []Task
+	results   []evo.Result
+	received  []bool
+	remaining int
+	queues    [][]int        // task indexes waiting for each worker
+	lost      []bool         // workers considered lost
+	strikes   []int          // consecutive missed heartbeats or failed requests for each worker
+	inflight  []int          // number of outstanding requests for each task
+	running   []map[int]bool // tasks with outstanding requests on each worker
+	cancels   []context.CancelFunc
+	done      bool
+	err       error
+
+	mu   sync.Mutex
+	cond *sync.Cond
+}
+
+func (x *search) applyDefaults() {
+	if x.Concurrency <= 0 {
+		x.Concurrency = 1
+	}
+	if x.Heartbeat <= 0 {
+		x.Heartbeat = time.Second
+	}
+	if x.MaxMissed <= 0 {
+		x.MaxMissed = 3
+	}
+	if x.Client == nil {
+		x.Client = http.DefaultClient
+	}
+}
+
+// Dispatch tasks to the worker until the search is done or the worker is lost
+func (x *search) dispatch(ctx context.Context, w int) {
+	for {
+
+		// Wait for the next task
+		x.mu.Lock()
+		var t int
+		var ok bool
+		for {
+			if x.done || x.lost[w] {
+				x.mu.Unlock()
+				return
+			}
+			if t, ok = x.next(w); ok {
+				break
+			}
+			x.cond.Wait()
+		}
+		x.inflight[t]++
+		x.running[w][t] = true
+		x.mu.Unlock()
+
+		// Send the task to the worker
+		reply, err := x.send(ctx, w, x.tasks[t])
+
+		// Record the outcome
+		x.mu.Lock()
+		x.inflight[t]--
+		delete(x.running[w], t)
+		switch {
+		case err != nil: // the request failed so try the task elsewhere
+			if x.inflight[t] == 0 {
+				x.requeue(t)
+			}
+			x.strike(w)
+		case reply.Error != "": // the evaluation failed so end the search
+			if x.err == nil {
+				x.err = fmt.Errorf("remote worker %s: %s", x.Workers[w], reply.Error)
+			}
+		default:
+			x.strikes[w] = 0
+			if !x.received[t] {
+				x.received[t] = true
+				x.results[t] = reply.Result
+				x.remaining--
+			}
+		}
+		x.cond.Broadcast()
+		x.mu.Unlock()
+	}
+}
+
+// Next returns the next task for the worker, stealing from the longest queue if the worker's own
+// is empty or copying a task being evaluated elsewhere if all are empty. The lock must be held.
+func (x *search) next(w int) (t int, ok bool) {
+	if q := x.queues[w]; len(q) > 0 {
+		t, x.queues[w] = q[0], q[1:]
+		return t, true
+	}
+	v := -1
+	for i, q := range x.queues {
+		if i != w && len(q) > 0 && (v < 0 || len(q) > len(x.queues[v])) {
+			v = i
+		}
+	}
+	if v < 0 {
+		return x.straggler(w)
+	}
+	q := x.queues[v]
+	t, x.queues[v] = q[len(q)-1], q[:len(q)-1]
+	return t, true
+}
+
+// Straggler returns a task being evaluated by another worker and by no other. The lock must be
+// held.
+func (x *search) straggler(w int) (t int, ok bool) {
+	for v, ts := range x.running {
+		if v == w {
+			continue
+		}
+		for t = range ts {
+			if x.inflight[t] == 1 && !x.received[t] {
+				return t, true
+			}
+		}
+	}
+	return
+}
+
+// Requeue the task with the remaining worker that has the fewest waiting tasks. The lock must be
+// held.
+func (x *search) requeue(t int) {
+	if x.received[t] {
+		return
+	}
+	v := -1
+	for i, q := range x.queues {
+		if !x.lost[i] && (v < 0 || len(q) < len(x.queues[v])) {
+			v = i
+		}
+	}
+	if v < 0 {
+		if x.err == nil {
+			x.err = ErrNoWorkers
+		}
+		return
+	}
+	x.queues[v] = append(x.queues[v], t)
+}
+
+// Strike the worker for a missed heartbeat or failed request and declare it lost if it has too
+// many. The lock must be held.
+func (x *search) strike(w int) {
+	if x.lost[w] {
+		return
+	}
+	x.strikes[w]++
+	if x.strikes[w] < x.MaxMissed {
+		return
+	}
+
+	// Abandon the worker's requests and move its tasks to the remaining workers
+	x.lost[w] = true
+	x.cancels[w]()
+	q := x.queues[w]
+	x.queues[w] = nil
+	for _, t := range q {
+		x.requeue(t)
+	}
+	x.cond.Broadcast()
+}
+
+// Send the task to the worker
+func (x *search) send(ctx context.Context, w int, task Task) (reply Reply, err error) {
+	var b []byte
+	if b, err = json.Marshal(task); err != nil {
+		return
+	}
+	var req *http.Request
+	if req, err = http.NewRequest(http.MethodPost, strings.TrimRight(x.Workers[w], "/")+EvaluatePath, bytes.NewReader(b)); err != nil {
+		return
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/json")
+	var resp *http.Response
+	if resp, err = x.Client.Do(req); err != nil {
+		return
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		err = fmt.Errorf("remote worker %s returned status %d", x.Workers[w], resp.StatusCode)
+		return
+	}
+	err = json.NewDecoder(resp.Body).Decode(&reply)
+	return
+}
+
+// Heartbeat checks the worker at each interval until the search is done or the worker is lost
+func (x *search) heartbeat(ctx context.Context, w int) {
+	url := strings.TrimRight(x.Workers[w], "/") + HeartbeatPath
+	ticker := time.NewTicker(x.Heartbeat)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		// Check the worker, allowing up to the interval for a response
+		hctx, fn := context.WithTimeout(ctx, x.Heartbeat)
+		ok := false
+		if req, err := http.NewRequest(http.MethodGet, url, nil); err == nil {
+			if resp, err := x.Client.Do(req.WithContext(hctx)); err == nil {
+				ok = resp.StatusCode == http.StatusOK
+				resp.Body.Close()
+			}
+		}
+		fn()
+
+		// Record the outcome
+		x.mu.Lock()
+		if ok {
+			x.strikes[w] = 0
+		} else if ctx.Err() == nil {
+			x.strike(w)
+		}
+		x.mu.Unlock()
+	}
+}
@@ -0,0 +1,215 @@
+package remote
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/klokare/evo"
+	"github.com/klokare/evo/searcher/process"
+)
+
+// Mock evaluator returns the number of conns as the fitness and counts its evaluations
+type mockEvaluator struct {
+	HasError bool
+	sync.Mutex
+	count int
+}
+
+func (m *mockEvaluator) Evaluate(p evo.Phenome) (evo.Result, error) {
+	m.Lock()
+	m.count++
+	m.Unlock()
+	if m.HasError {
+		return evo.Result{}, errors.New("error in mock evaluator")
+	}
+	sub := p.Network.(*process.Network).Substrate
+	return evo.Result{ID: p.ID, Fitness: float64(len(sub.Conns))}, nil
+}
+
+func (m *mockEvaluator) Count() int {
+	m.Lock()
+	defer m.Unlock()
+	return m.count
+}
+
+// Create phenomes with increasing numbers of conns
+func newTestPhenomes(n int) []evo.Phenome {
+	phenomes := make([]evo.Phenome, n)
+	for i := range phenomes {
+		sub := evo.Substrate{Conns: make([]evo.Conn, i)}
+		net, _ := process.Translator{}.Translate(sub)
+		phenomes[i] = evo.Phenome{ID: int64(i + 1), Network: net}
+	}
+	return phenomes
+}
+
+func checkResults(t *testing.T, results []evo.Result, n int) {
+	if len(results) != n {
+		t.Fatalf("incorrect number of results: expected %d, actual %d", n, len(results))
+	}
+	for i, r := range results {
+		if r.ID != int64(i+1) || r.Fitness != float64(i) {
+			t.Errorf("incorrect result at %d: expected ID %d fitness %d, actual ID %d fitness %f", i, i+1, i, r.ID, r.Fitness)
+		}
+	}
+}
+
+func TestSearcherSearch(t *testing.T) {
+
+	// Start two workers. The worker wraps the process translator so the evaluator can see the
+	// substrate.
+	e1, e2 := &mockEvaluator{}, &mockEvaluator{}
+	s1 := httptest.NewServer(Worker{Evaluator: e1, Translator: process.Translator{}})
+	defer s1.Close()
+	s2 := httptest.NewServer(Worker{Evaluator: e2, Translator: process.Translator{}})
+	defer s2.Close()
+
+	// Search with the workers
+	s := &Searcher{Workers: []string{s1.URL, s2.URL + "/"}, Concurrency: 2, Heartbeat: 10 * time.Millisecond}
+	results, err := s.Search(nil, newTestPhenomes(20))
+	if err != nil {
+		t.Fatalf("error not expected: %v", err)
+	}
+	checkResults(t, results, 20)
+
+	// Both workers should have been used
+	if e1.Count() == 0 || e2.Count() == 0 {
+		t.Errorf("expected both workers to evaluate: actual %d and %d", e1.Count(), e2.Count())
+	}
+}
+
+func TestSearcherLostWorker(t *testing.T) {
+	var cases = []struct {
+		Desc    string
+		Handler func(http.Handler) http.Handler
+	}{
+		{
+			Desc: "worker is unreachable",
+		},
+		{
+			Desc: "worker hangs and misses its heartbeats",
+			Handler: func(http.Handler) http.Handler {
+				return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+					if r.URL.Path == HeartbeatPath {
+						rw.WriteHeader(http.StatusServiceUnavailable)
+						return
+					}
+					ioutil.ReadAll(r.Body)
+					<-r.Context().Done() // never respond
+				})
+			},
+		},
+		{
+			Desc: "worker fails requests",
+			Handler: func(http.Handler) http.Handler {
+				return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+					http.Error(rw, "failed", http.StatusInternalServerError)
+				})
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Desc, func(t *testing.T) {
+
+			// Start a good worker and a bad one
+			good := httptest.NewServer(Worker{Evaluator: &mockEvaluator{}, Translator: process.Translator{}})
+			defer good.Close()
+			var bad *httptest.Server
+			if c.Handler == nil {
+				bad = httptest.NewServer(http.NotFoundHandler())
+				bad.Close() // nothing is listening now
+			} else {
+				bad = httptest.NewServer(c.Handler(nil))
+				defer bad.Close()
+			}
+
+			// The bad worker's tasks should be re-dispatched to the good worker
+			s := &Searcher{Workers: []string{bad.URL, good.URL}, Heartbeat: 10 * time.Millisecond, MaxMissed: 2}
+			results, err := s.Search(nil, newTestPhenomes(10))
+			if err != nil {
+				t.Fatalf("error not expected: %v", err)
+			}
+			checkResults(t, results, 10)
+		})
+	}
+}
+
+func TestSearcherErrors(t *testing.T) {
+
+	// No workers
+	if _, err := (&Searcher{}).Search(nil, newTestPhenomes(1)); err != ErrNoWorkers {
+		t.Errorf("incorrect error: expected %v, actual %v", ErrNoWorkers, err)
+	}
+
+	// Network not created by the process translator. The error is the process package's own.
+	if _, err := (&Searcher{Workers: []string{"http://localhost"}}).Search(nil, []evo.Phenome{{ID: 1}}); err != process.ErrMissingSubstrate {
+		t.Errorf("incorrect error: expected %v, actual %v", process.ErrMissingSubstrate, err)
+	}
+
+	// All workers lost
+	srv := httptest.NewServer(http.NotFoundHandler())
+	srv.Close()
+	s := &Searcher{Workers: []string{srv.URL}, Heartbeat: 10 * time.Millisecond, MaxMissed: 1}
+	if _, err := s.Search(nil, newTestPhenomes(3)); err != ErrNoWorkers {
+		t.Errorf("incorrect error: expected %v, actual %v", ErrNoWorkers, err)
+	}
+
+	// Evaluator fails
+	srv = httptest.NewServer(Worker{Evaluator: &mockEvaluator{HasError: true}, Translator: process.Translator{}})
+	defer srv.Close()
+	s = &Searcher{Workers: []string{srv.URL}}
+	if _, err := s.Search(nil, newTestPhenomes(3)); err == nil || !strings.Contains(err.Error(), "mock evaluator") {
+		t.Errorf("expected evaluator error not found: %v", err)
+	}
+}
+
+func TestWorkerServeHTTP(t *testing.T) {
+	var cases = []struct {
+		Desc     string
+		Method   string
+		Path     string
+		Body     string
+		Status   int
+		Contains string
+	}{
+		{Desc: "heartbeat", Method: http.MethodGet, Path: HeartbeatPath, Status: http.StatusOK},
+		{Desc: "unknown path", Method: http.MethodGet, Path: "/unknown", Status: http.StatusNotFound},
+		{Desc: "evaluate with wrong method", Method: http.MethodGet, Path: EvaluatePath, Status: http.StatusMethodNotAllowed},
+		{Desc: "evaluate with bad body", Method: http.MethodPost, Path: EvaluatePath, Body: "{", Status: http.StatusBadRequest},
+		{Desc: "translation fails", Method: http.MethodPost, Path: EvaluatePath, Body: `{"ID":1}`, Status: http.StatusOK, Contains: `"Error"`},
+		{
+			Desc:     "evaluate",
+			Method:   http.MethodPost,
+			Path:     EvaluatePath,
+			Body:     `{"ID":3,"Substrate":{"Nodes":[{"Neuron":1,"Activation":1},{"Layer":1,"Neuron":3,"Activation":1}]}}`,
+			Status:   http.StatusOK,
+			Contains: `"ID":3`,
+		},
+	}
+
+	w := Worker{Evaluator: evaluatorFunc(func(p evo.Phenome) (evo.Result, error) { return evo.Result{ID: p.ID}, nil })}
+	for _, c := range cases {
+		t.Run(c.Desc, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			w.ServeHTTP(rec, httptest.NewRequest(c.Method, c.Path, bytes.NewBufferString(c.Body)))
+			if rec.Code != c.Status {
+				t.Errorf("incorrect status: expected %d, actual %d", c.Status, rec.Code)
+			}
+			if c.Contains != "" && !strings.Contains(rec.Body.String(), c.Contains) {
+				t.Errorf("response %q does not contain %q", rec.Body.String(), c.Contains)
+			}
+		})
+	}
+}
+
+type evaluatorFunc func(evo.Phenome) (evo.Result, error)
+
+func (f evaluatorFunc) Evaluate(p evo.Phenome) (evo.Result, error) { return f(p) }
@@ -0,0 +1,80 @@
+package remote
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/klokare/evo"
+	"github.com/klokare/evo/network/forward"
+)
+
+// Paths served by the worker
+const (
+	EvaluatePath  = "/evaluate"
+	HeartbeatPath = "/heartbeat"
+)
+
+// Task is the message sent to a worker for each phenome
+type Task struct {
+	ID        int64
+	Traits    []float64
+	Substrate evo.Substrate
+}
+
+// Reply is the message returned by a worker for each task. If the evaluator fails, the error is
+// described in the reply.
+type Reply struct {
+	Result evo.Result
+	Error  string `json:",omitempty"`
+}
+
+// Worker serves evaluation requests over HTTP, wrapping any evaluator. Each task's substrate is
+// translated into a network before being evaluated. Run a worker on each node with
+// ListenAndServe.
+type Worker struct {
+	evo.Evaluator
+	evo.Translator // Creates the network from the task's substrate. If nil, a forward translator is used.
+}
+
+// ListenAndServe starts the worker on the address, such as ":8080"
+func ListenAndServe(addr string, w Worker) error {
+	return http.ListenAndServe(addr, w)
+}
+
+// ServeHTTP handles the evaluate and heartbeat requests
+func (w Worker) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case HeartbeatPath:
+		rw.WriteHeader(http.StatusOK)
+	case EvaluatePath:
+		if r.Method != http.MethodPost {
+			http.Error(rw, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		var task Task
+		if err := json.NewDecoder(r.Body).Decode(&task); err != nil {
+			http.Error(rw, err.Error(), http.StatusBadRequest)
+			return
+		}
+		rw.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(rw).Encode(w.evaluate(task))
+	default:
+		http.NotFound(rw, r)
+	}
+}
+
+// Evaluate the task, returning any error in the reply
+func (w Worker) evaluate(task Task) (reply Reply) {
+	tr := w.Translator
+	if tr == nil {
+		tr = forward.Translator{}
+	}
+	net, err := tr.Translate(task.Substrate)
+	if err == nil {
+		reply.Result, err = w.Evaluate(evo.Phenome{ID: task.ID, Traits: task.Traits, Network: net})
+	}
+	if err != nil {
+		reply.Error = err.Error()
+	}
+	return
+}
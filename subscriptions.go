@@ -29,10 +29,30 @@ type SubscriptionProvider interface {
 	Subscriptions() []Subscription
 }
 
+// Listeners maps events to the callbacks subscribed to them
+type Listeners map[Event][]Callback
+
+// Subscribe maps the experiment's subscriptions, if any, to their events. Runners other than Run
+// use it, with Publish, to inform an experiment's subscribers in the same way.
+func Subscribe(exp Experiment) Listeners {
+	listeners := make(Listeners, 10)
+	if sx, ok := exp.(SubscriptionProvider); ok {
+		for _, s := range sx.Subscriptions() {
+			var ls []Callback
+			if ls, ok = listeners[s.Event]; !ok {
+				ls = make([]Callback, 0, 10)
+			}
+			ls = append(ls, s.Callback)
+			listeners[s.Event] = ls
+		}
+	}
+	return listeners
+}
+
 // Publish an event to the listeners. Callbacks will be called concurrently so there is no
 // guarantee the order in which they are called.
-func publish(listeners map[Event][]Callback, event Event, pop Population) (err error) {
-	callbacks := listeners[event]
+func (l Listeners) Publish(event Event, pop Population) (err error) {
+	callbacks := l[event]
 	if len(callbacks) == 0 {
 		return
 	}
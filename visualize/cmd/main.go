@@ -0,0 +1,57 @@
+// Command cmd renders a genome or substrate, saved with the codec package, as a DOT graph or SVG
+// image.
+//
+//	cmd -output best.svg best.json
+//	cmd -decoded -output best.dot best.bin
+//
+// The format is taken from the output file's extension. A genome's encoded substrate is rendered
+// unless its decoded one is requested.
+package main
+
+import (
+	"flag"
+	"log"
+
+	"github.com/klokare/evo"
+	"github.com/klokare/evo/codec"
+	"github.com/klokare/evo/visualize"
+)
+
+func main() {
+
+	// Parse the flags
+	var (
+		output  = flag.String("output", "substrate.svg", "filename for the output, ending in .dot or .svg")
+		decoded = flag.Bool("decoded", false, "render the genome's decoded substrate rather than its encoded one")
+		labels  = flag.Bool("labels", false, "label the connections with their weights")
+		height  = flag.Float64("height", 480, "image height in pixels")
+		width   = flag.Float64("width", 640, "image width in pixels")
+	)
+	flag.Parse()
+	args := flag.Args()
+	if len(args) != 1 {
+		log.Fatal("you must specify one genome or substrate file")
+	}
+
+	// Load the substrate, trying the file as a genome first
+	var sub evo.Substrate
+	var g evo.Genome
+	err := codec.ReadFile(args[0], &g)
+	switch {
+	case err == nil && *decoded:
+		sub = g.Decoded
+	case err == nil:
+		sub = g.Encoded
+	case err == codec.ErrMismatchedKind:
+		err = codec.ReadFile(args[0], &sub)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Render the substrate
+	r := visualize.Renderer{Width: *width, Height: *height, Labels: *labels}
+	if err = r.WriteFile(*output, sub); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,412 @@
+// Package visualize renders substrates and networks as Graphviz DOT graphs or self-contained SVG
+// images so that evolved topologies can be inspected. Nodes are placed by their positions, with the
+// layer increasing up the image and X across it, and coloured by neuron type, with the outline
+// showing the activation. Connections are coloured by the sign of their weight and drawn wider
+// as the weight grows. Disabled connections are dashed and locked nodes and connections outlined.
+package visualize
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/klokare/evo"
+	"github.com/klokare/evo/network/forward"
+)
+
+// Known errors
+var (
+	ErrUnknownNode   = errors.New("connection refers to a node not in the substrate")
+	ErrUnknownFormat = errors.New("unknown visualization format")
+)
+
+// Format of the rendered image
+type Format byte
+
+// Known formats
+const (
+	DOT Format = iota + 1
+	SVG
+)
+
+// Formats maps the names of the formats to their values
+var Formats = map[string]Format{
+	"dot": DOT,
+	"svg": SVG,
+}
+
+// Fill colours of the neuron types
+var neuronColors = map[evo.Neuron]string{
+	evo.Input:  "#9ecae1",
+	evo.Hidden: "#d9d9d9",
+	evo.Output: "#fdae6b",
+}
+
+// Outline colours of the activations
+var activationColors = map[evo.Activation]string{
+	evo.Direct:           "#636363",
+	evo.Sigmoid:          "#3182bd",
+	evo.SteepenedSigmoid: "#08519c",
+	evo.Tanh:             "#31a354",
+	evo.InverseAbs:       "#756bb1",
+	evo.Sin:              "#e6550d",
+	evo.Gauss:            "#de2d26",
+	evo.ReLU:             "#8c6d31",
+}
+
+// Colours of the connections
+const (
+	positiveColor = "#2166ac"
+	negativeColor = "#b2182b"
+	disabledColor = "#bdbdbd"
+	lockedColor   = "#252525"
+)
+
+// Renderer draws substrates. The zero value is ready to use.
+type Renderer struct {
+	Width, Height float64 // Size of the SVG image in pixels. Defaults to 640 by 480.
+	Radius        float64 // Radius of the nodes in pixels. Defaults to 12.
+	Labels        bool    // Label the connections with their weights
+}
+
+// Render the substrate to the writer in the format
+func (r Renderer) Render(w io.Writer, sub evo.Substrate, f Format) error {
+	switch f {
+	case DOT:
+		return r.DOT(w, sub)
+	case SVG:
+		return r.SVG(w, sub)
+	default:
+		return ErrUnknownFormat
+	}
+}
+
+// WriteFile renders the substrate to the file. The format is taken from the file's extension.
+func (r Renderer) WriteFile(filename string, sub evo.Substrate) (err error) {
+	f, ok := Formats[strings.TrimPrefix(strings.ToLower(filepath.Ext(filename)), ".")]
+	if !ok {
+		return ErrUnknownFormat
+	}
+	var file *os.File
+	if file, err = os.Create(filename); err != nil {
+		return
+	}
+	if err = r.Render(file, sub, f); err != nil {
+		file.Close() // ignore error as it would overwrite the render one
+		return
+	}
+	return file.Close()
+}
+
+// DOT writes the substrate as a Graphviz directed graph. Nodes in the same layer share a rank and
+// carry their positions so that the layout engines which honour them, such as neato -n, can
+// place the nodes as the SVG renderer does.
+func (r Renderer) DOT(w io.Writer, sub evo.Substrate) (err error) {
+	var l layout
+	if l, err = r.layout(sub); err != nil {
+		return
+	}
+	b := new(strings.Builder)
+	b.WriteString("digraph substrate {\n")
+	b.WriteString("\trankdir=BT;\n")
+	b.WriteString("\tnode [shape=circle, style=filled, fixedsize=true, width=0.4, fontsize=8];\n")
+	b.WriteString("\tedge [arrowsize=0.5];\n")
+
+	// Write the nodes with their positions in points and group them by layer
+	for i, n := range l.nodes {
+		x, y := l.point(n.Position)
+		extra := ""
+		if n.Locked {
+			extra = ", peripheries=2"
+		}
+		fmt.Fprintf(b, "\tn%d [label=\"%s\", tooltip=\"%s\", fillcolor=\"%s\", color=\"%s\", penwidth=2, pos=\"%.1f,%.1f!\"%s];\n",
+			i, escapeDOT(short(n)), escapeDOT(n.String()), neuronColor(n.Neuron), activationColor(n.Activation), x, l.height-y, extra)
+	}
+	for _, rank := range l.ranks() {
+		b.WriteString("\t{rank=same;")
+		for _, i := range rank {
+			fmt.Fprintf(b, " n%d;", i)
+		}
+		b.WriteString("}\n")
+	}
+
+	// Write the connections
+	for _, c := range sub.Conns {
+		si, ti := l.index[c.Source], l.index[c.Target]
+		clr := connColor(c)
+		if c.Locked {
+			clr = lockedColor + ":" + clr // drawn as parallel strokes
+		}
+		attrs := []string{
+			fmt.Sprintf("color=\"%s\"", clr),
+			fmt.Sprintf("penwidth=%.2f", l.stroke(c)),
+		}
+		if !c.Enabled {
+			attrs = append(attrs, "style=dashed")
+		}
+		if r.Labels {
+			attrs = append(attrs, fmt.Sprintf("label=\"%.3g\"", c.Weight))
+		}
+		fmt.Fprintf(b, "\tn%d -> n%d [%s];\n", si, ti, strings.Join(attrs, ", "))
+	}
+	b.WriteString("}\n")
+	_, err = io.WriteString(w, b.String())
+	return
+}
+
+// SVG writes the substrate as a self-contained SVG image. Each node's description is shown as a
+// tooltip. Connections to the same or an earlier layer are drawn as curves.
+func (r Renderer) SVG(w io.Writer, sub evo.Substrate) (err error) {
+	var l layout
+	if l, err = r.layout(sub); err != nil {
+		return
+	}
+	b := new(strings.Builder)
+	fmt.Fprintf(b, "<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%.0f\" height=\"%.0f\" viewBox=\"0 0 %.0f %.0f\">\n", l.width, l.height, l.width, l.height)
+	b.WriteString("<defs>\n")
+	for _, clr := range []string{positiveColor, negativeColor, disabledColor} {
+		fmt.Fprintf(b, "<marker id=\"arrow%s\" viewBox=\"0 0 10 10\" refX=\"10\" refY=\"5\" markerWidth=\"6\" markerHeight=\"6\" orient=\"auto-start-reverse\"><path d=\"M0,0 L10,5 L0,10 z\" fill=\"%s\"/></marker>\n", clr[1:], clr)
+	}
+	b.WriteString("</defs>\n")
+	b.WriteString("<rect width=\"100%\" height=\"100%\" fill=\"white\"/>\n")
+
+	// Draw the connections beneath the nodes
+	for _, c := range sub.Conns {
+		x1, y1 := l.point(c.Source)
+		x2, y2 := l.point(c.Target)
+		path := l.path(x1, y1, x2, y2, c.Source.Layer >= c.Target.Layer)
+		clr := connColor(c)
+		if c.Locked {
+			fmt.Fprintf(b, "<path d=\"%s\" fill=\"none\" stroke=\"%s\" stroke-width=\"%.2f\"/>\n", path, lockedColor, l.stroke(c)+3.0)
+		}
+		dash := ""
+		if !c.Enabled {
+			dash = " stroke-dasharray=\"6,4\""
+		}
+		fmt.Fprintf(b, "<path d=\"%s\" fill=\"none\" stroke=\"%s\" stroke-width=\"%.2f\"%s marker-end=\"url(#arrow%s)\"><title>%s</title></path>\n",
+			path, clr, l.stroke(c), dash, clr[1:], html.EscapeString(describe(c)))
+		if r.Labels {
+			fmt.Fprintf(b, "<text x=\"%.1f\" y=\"%.1f\" font-size=\"9\" font-family=\"sans-serif\" fill=\"%s\">%.3g</text>\n", (x1+x2)/2.0+3.0, (y1+y2)/2.0, clr, c.Weight)
+		}
+	}
+
+	// Draw the nodes
+	for _, n := range l.nodes {
+		x, y := l.point(n.Position)
+		fmt.Fprintf(b, "<g><title>%s</title>\n", html.EscapeString(n.String()))
+		if n.Locked {
+			fmt.Fprintf(b, "<circle cx=\"%.1f\" cy=\"%.1f\" r=\"%.1f\" fill=\"none\" stroke=\"%s\" stroke-width=\"1\"/>\n", x, y, l.radius+4.0, lockedColor)
+		}
+		fmt.Fprintf(b, "<circle cx=\"%.1f\" cy=\"%.1f\" r=\"%.1f\" fill=\"%s\" stroke=\"%s\" stroke-width=\"2.5\"/>\n", x, y, l.radius, neuronColor(n.Neuron), activationColor(n.Activation))
+		fmt.Fprintf(b, "<text x=\"%.1f\" y=\"%.1f\" font-size=\"8\" font-family=\"sans-serif\" text-anchor=\"middle\" dominant-baseline=\"central\">%s</text>\n", x, y, html.EscapeString(short(n)))
+		b.WriteString("</g>\n")
+	}
+	b.WriteString("</svg>\n")
+	_, err = io.WriteString(w, b.String())
+	return
+}
+
+// FromNetwork returns a substrate describing the forward network so that it can be rendered. The
+// network's layers are spread evenly from the inputs to the outputs and the neurons of each layer
+// evenly across it. Only non-zero weights are included as connections.
+func FromNetwork(net forward.Network) (sub evo.Substrate) {
+	n := len(net.Layers)
+	pos := make([][]evo.Position, n)
+	for i, lay := range net.Layers {
+		layer := 0.0
+		if n > 1 {
+			layer = float64(i) / float64(n-1)
+		}
+		neuron := evo.Hidden
+		if i == 0 {
+			neuron = evo.Input
+		} else if i == n-1 {
+			neuron = evo.Output
+		}
+		pos[i] = make([]evo.Position, len(lay.Activations))
+		for j, a := range lay.Activations {
+			pos[i][j] = evo.Position{Layer: layer, X: (float64(j) + 0.5) / float64(len(lay.Activations))}
+			node := evo.Node{Position: pos[i][j], Neuron: neuron, Activation: a}
+			if j < len(lay.Biases) {
+				node.Bias = lay.Biases[j]
+			}
+			sub.Nodes = append(sub.Nodes, node)
+		}
+	}
+	for i, lay := range net.Layers {
+		for k, src := range lay.Sources {
+			w := lay.Weights[k]
+			rows, cols := w.Dims()
+			for s := 0; s < rows; s++ {
+				for t := 0; t < cols; t++ {
+					if x := w.At(s, t); x != 0.0 {
+						sub.Conns = append(sub.Conns, evo.Conn{Source: pos[src][s], Target: pos[i][t], Weight: x, Enabled: true})
+					}
+				}
+			}
+		}
+	}
+	return
+}
+
+// Layout of a substrate on the image
+type layout struct {
+	nodes         []evo.Node
+	index         map[evo.Position]int
+	width, height float64 // size of the image
+	radius        float64
+	margin        float64
+	minL, maxL    float64 // range of the layers
+	minX, maxX    float64 // range of the X coordinates, widened by the Y offsets
+	maxW          float64 // largest absolute weight
+}
+
+// Lay out the substrate, checking that every connection joins known nodes
+func (r Renderer) layout(sub evo.Substrate) (l layout, err error) {
+	l.width, l.height, l.radius = r.Width, r.Height, r.Radius
+	if l.width <= 0 {
+		l.width = 640
+	}
+	if l.height <= 0 {
+		l.height = 480
+	}
+	if l.radius <= 0 {
+		l.radius = 12
+	}
+	l.margin = l.radius * 3.0
+
+	// Order the nodes and index their positions
+	l.nodes = make([]evo.Node, len(sub.Nodes))
+	copy(l.nodes, sub.Nodes)
+	sort.Slice(l.nodes, func(i, j int) bool { return l.nodes[i].Compare(l.nodes[j]) < 0 })
+	l.index = make(map[evo.Position]int, len(l.nodes))
+	l.minL, l.maxL = math.Inf(1), math.Inf(-1)
+	l.minX, l.maxX = math.Inf(1), math.Inf(-1)
+	for i, n := range l.nodes {
+		l.index[n.Position] = i
+		x := n.X + n.Y*obliqueness
+		l.minL, l.maxL = math.Min(l.minL, n.Layer), math.Max(l.maxL, n.Layer)
+		l.minX, l.maxX = math.Min(l.minX, x), math.Max(l.maxX, x)
+	}
+
+	// Check the connections
+	for _, c := range sub.Conns {
+		if _, ok := l.index[c.Source]; !ok {
+			return l, ErrUnknownNode
+		}
+		if _, ok := l.index[c.Target]; !ok {
+			return l, ErrUnknownNode
+		}
+		l.maxW = math.Max(l.maxW, math.Abs(c.Weight))
+	}
+	return
+}
+
+// Share of a node's Y coordinate added to its X so that depth appears as an oblique offset
+const obliqueness = 0.25
+
+// Returns the image coordinates of the position
+func (l layout) point(p evo.Position) (x, y float64) {
+	x, y = l.width/2.0, l.height/2.0
+	if l.maxX > l.minX {
+		x = l.margin + (p.X+p.Y*obliqueness-l.minX)/(l.maxX-l.minX)*(l.width-2.0*l.margin)
+	}
+	if l.maxL > l.minL {
+		y = l.height - l.margin - (p.Layer-l.minL)/(l.maxL-l.minL)*(l.height-2.0*l.margin)
+	}
+	return
+}
+
+// Returns the stroke width of the connection, from 0.5 to 4 pixels as its weight grows
+func (l layout) stroke(c evo.Conn) float64 {
+	if l.maxW == 0.0 {
+		return 0.5
+	}
+	return 0.5 + 3.5*math.Abs(c.Weight)/l.maxW
+}
+
+// Returns the SVG path between the points, stopping at the target node's edge. Recurrent
+// connections are curved to the side and a node's connection to itself is drawn as a loop.
+func (l layout) path(x1, y1, x2, y2 float64, recurrent bool) string {
+	if x1 == x2 && y1 == y2 {
+		r := l.radius
+		return fmt.Sprintf("M%.1f,%.1f C%.1f,%.1f %.1f,%.1f %.1f,%.1f", x1+r*0.7, y1-r*0.7, x1+r*3.0, y1-r*3.0, x1+r*3.0, y1+r*3.0, x1+r, y1+r*0.2)
+	}
+	dx, dy := x2-x1, y2-y1
+	d := math.Hypot(dx, dy)
+	if !recurrent {
+		return fmt.Sprintf("M%.1f,%.1f L%.1f,%.1f", x1, y1, x2-dx/d*l.radius, y2-dy/d*l.radius)
+	}
+	cx, cy := (x1+x2)/2.0-dy*0.3, (y1+y2)/2.0+dx*0.3 // control point to the side of the line
+	ex, ey := x2-cx, y2-cy
+	e := math.Hypot(ex, ey)
+	return fmt.Sprintf("M%.1f,%.1f Q%.1f,%.1f %.1f,%.1f", x1, y1, cx, cy, x2-ex/e*l.radius, y2-ey/e*l.radius)
+}
+
+// Returns the indexes of the nodes in each layer
+func (l layout) ranks() (ranks [][]int) {
+	for i, n := range l.nodes {
+		if i == 0 || n.Layer != l.nodes[i-1].Layer {
+			ranks = append(ranks, nil)
+		}
+		ranks[len(ranks)-1] = append(ranks[len(ranks)-1], i)
+	}
+	return
+}
+
+func neuronColor(n evo.Neuron) string {
+	if c, ok := neuronColors[n]; ok {
+		return c
+	}
+	return "#ffffff"
+}
+
+func activationColor(a evo.Activation) string {
+	if c, ok := activationColors[a]; ok {
+		return c
+	}
+	return "#000000"
+}
+
+func connColor(c evo.Conn) string {
+	switch {
+	case !c.Enabled:
+		return disabledColor
+	case c.Weight < 0:
+		return negativeColor
+	default:
+		return positiveColor
+	}
+}
+
+// Returns the short label of the node, which is its bias unless it is an input
+func short(n evo.Node) string {
+	if n.Neuron == evo.Input {
+		return "in"
+	}
+	return fmt.Sprintf("%.2g", n.Bias)
+}
+
+// Escapes the text for use within a quoted DOT string. Node descriptions include the names of custom
+// activations, which may contain any character.
+func escapeDOT(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s)
+}
+
+// Returns the description of the connection
+func describe(c evo.Conn) string {
+	s := fmt.Sprintf("%s → %s weight %.4f", c.Source, c.Target, c.Weight)
+	if !c.Enabled {
+		s += " disabled"
+	}
+	if c.Locked {
+		s += " locked"
+	}
+	return s
+}
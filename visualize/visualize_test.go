@@ -0,0 +1,209 @@
+package visualize
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/klokare/evo"
+	"github.com/klokare/evo/network/forward"
+)
+
+func newTestSubstrate() evo.Substrate {
+	in1 := evo.Position{Layer: 0.0, X: 0.0}
+	in2 := evo.Position{Layer: 0.0, X: 1.0}
+	hid := evo.Position{Layer: 0.5, X: 0.5}
+	out := evo.Position{Layer: 1.0, X: 0.5}
+	return evo.Substrate{
+		Nodes: []evo.Node{
+			{Position: in1, Neuron: evo.Input, Activation: evo.Direct, Locked: true},
+			{Position: in2, Neuron: evo.Input, Activation: evo.Direct, Locked: true},
+			{Position: hid, Neuron: evo.Hidden, Activation: evo.Tanh, Bias: 0.5},
+			{Position: out, Neuron: evo.Output, Activation: evo.Sigmoid, Bias: -1.0},
+		},
+		Conns: []evo.Conn{
+			{Source: in1, Target: hid, Weight: 1.5, Enabled: true},
+			{Source: in2, Target: hid, Weight: -0.5, Enabled: true},
+			{Source: in1, Target: out, Weight: 0.25, Enabled: false},
+			{Source: hid, Target: out, Weight: 2.0, Enabled: true, Locked: true},
+			{Source: out, Target: hid, Weight: 0.1, Enabled: true}, // recurrent
+		},
+	}
+}
+
+func TestDOT(t *testing.T) {
+	b := new(bytes.Buffer)
+	if err := (Renderer{Labels: true}).DOT(b, newTestSubstrate()); err != nil {
+		t.Fatalf("error not expected: %v", err)
+	}
+	s := b.String()
+	for _, x := range []string{
+		"digraph substrate {",
+		"{rank=same; n0; n1;}",
+		`fillcolor="#fdae6b", color="#3182bd"`, // sigmoid output
+		"peripheries=2",                        // locked inputs
+		`n0 -> n3 [color="#bdbdbd", penwidth=0.94, style=dashed, label="0.25"];`,
+		`n2 -> n3 [color="#252525:#2166ac", penwidth=4.00, label="2"];`,
+		`n1 -> n2 [color="#b2182b"`,
+	} {
+		if !strings.Contains(s, x) {
+			t.Errorf("DOT graph does not contain %s:\n%s", x, s)
+		}
+	}
+	if n := strings.Count(s, "->"); n != 5 {
+		t.Errorf("incorrect number of edges: expected 5, actual %d", n)
+	}
+}
+
+func TestSVG(t *testing.T) {
+	b := new(bytes.Buffer)
+	if err := (Renderer{Width: 300, Height: 200, Labels: true}).SVG(b, newTestSubstrate()); err != nil {
+		t.Fatalf("error not expected: %v", err)
+	}
+
+	// The image should be well-formed XML with a circle for each node, plus the locked outlines,
+	// and a path for each connection, plus the locked underlay
+	var circles, paths int
+	dec := xml.NewDecoder(bytes.NewReader(b.Bytes()))
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatalf("SVG is not well-formed: %v", err)
+		}
+		if se, ok := tok.(xml.StartElement); ok {
+			switch se.Name.Local {
+			case "circle":
+				circles++
+			case "path":
+				for _, a := range se.Attr {
+					if a.Name.Local == "fill" && a.Value == "none" { // not an arrow head
+						paths++
+					}
+				}
+			}
+		}
+	}
+	if circles != 4+2 {
+		t.Errorf("incorrect number of circles: expected 6, actual %d", circles)
+	}
+	if paths != 5+1 {
+		t.Errorf("incorrect number of paths: expected 6, actual %d", paths)
+	}
+	for _, x := range []string{`width="300" height="200"`, `stroke-dasharray="6,4"`, " Q", "<title>hidden node"} {
+		if !strings.Contains(b.String(), x) {
+			t.Errorf("SVG does not contain %s", x)
+		}
+	}
+}
+
+// A custom activation whose name needs escaping in both formats
+var awkward, errAwkward = evo.RegisterActivation(`vis<"&>\`, func(x float64) float64 { return x }, nil)
+
+func TestEscaping(t *testing.T) {
+	if errAwkward != nil {
+		t.Fatalf("error not expected registering: %v", errAwkward)
+	}
+	sub := newTestSubstrate()
+	sub.Nodes[2].Activation = awkward
+
+	// The DOT tooltip is quoted
+	b := new(bytes.Buffer)
+	if err := (Renderer{}).DOT(b, sub); err != nil {
+		t.Fatalf("error not expected: %v", err)
+	}
+	if x := `vis<\"&>\\`; !strings.Contains(b.String(), x) {
+		t.Errorf("DOT graph does not contain %s:\n%s", x, b.String())
+	}
+
+	// The SVG title is escaped so the image remains well-formed and shows the name
+	b.Reset()
+	if err := (Renderer{}).SVG(b, sub); err != nil {
+		t.Fatalf("error not expected: %v", err)
+	}
+	var titles []string
+	dec := xml.NewDecoder(bytes.NewReader(b.Bytes()))
+	var inTitle bool
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatalf("SVG is not well-formed: %v", err)
+		}
+		switch x := tok.(type) {
+		case xml.StartElement:
+			inTitle = x.Name.Local == "title"
+		case xml.CharData:
+			if inTitle {
+				titles = append(titles, string(x))
+			}
+		case xml.EndElement:
+			inTitle = false
+		}
+	}
+	var found bool
+	for _, x := range titles {
+		if strings.Contains(x, `vis<"&>\`) {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("SVG titles do not contain the activation name: %v", titles)
+	}
+}
+
+func TestRenderErrors(t *testing.T) {
+	sub := newTestSubstrate()
+	sub.Conns = append(sub.Conns, evo.Conn{Source: evo.Position{Layer: 0.7}, Target: sub.Nodes[3].Position})
+	for _, f := range []Format{DOT, SVG} {
+		if err := (Renderer{}).Render(io.Discard, sub, f); err != ErrUnknownNode {
+			t.Errorf("incorrect error: expected %v, actual %v", ErrUnknownNode, err)
+		}
+	}
+	if err := (Renderer{}).Render(io.Discard, newTestSubstrate(), Format(9)); err != ErrUnknownFormat {
+		t.Errorf("incorrect error: expected %v, actual %v", ErrUnknownFormat, err)
+	}
+	if err := (Renderer{}).WriteFile("substrate.png", newTestSubstrate()); err != ErrUnknownFormat {
+		t.Errorf("incorrect error: expected %v, actual %v", ErrUnknownFormat, err)
+	}
+}
+
+func TestFromNetwork(t *testing.T) {
+
+	// Translate a substrate without the recurrent and disabled connections
+	sub := newTestSubstrate()
+	sub.Conns = []evo.Conn{sub.Conns[0], sub.Conns[1], sub.Conns[3]}
+	net, err := forward.Translator{}.Translate(sub)
+	if err != nil {
+		t.Fatalf("error not expected: %v", err)
+	}
+
+	// The network's neurons and weights should be recovered
+	x := FromNetwork(net.(forward.Network))
+	if len(x.Nodes) != len(sub.Nodes) {
+		t.Fatalf("incorrect number of nodes: expected %d, actual %d", len(sub.Nodes), len(x.Nodes))
+	}
+	for i, n := range x.Nodes {
+		if n.Neuron != sub.Nodes[i].Neuron || n.Activation != sub.Nodes[i].Activation || n.Bias != sub.Nodes[i].Bias {
+			t.Errorf("incorrect node %d: expected %v, actual %v", i, sub.Nodes[i], n)
+		}
+	}
+	if len(x.Conns) != 3 {
+		t.Fatalf("incorrect number of conns: expected 3, actual %d", len(x.Conns))
+	}
+	weights := map[float64]bool{1.5: true, -0.5: true, 2.0: true}
+	for _, c := range x.Conns {
+		if !weights[c.Weight] {
+			t.Errorf("unexpected conn weight %f", c.Weight)
+		}
+	}
+
+	// And the substrate rendered
+	if err = (Renderer{}).SVG(io.Discard, x); err != nil {
+		t.Errorf("error not expected: %v", err)
+	}
+}